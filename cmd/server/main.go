@@ -2,14 +2,23 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/radek-zitek-cloud/goedu-theta/internal/audit"
 	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/idempotency"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/lifecycle"
 	"github.com/radek-zitek-cloud/goedu-theta/internal/logger"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/ratelimit"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/restart"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/routes"
 	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
 	"github.com/radek-zitek-cloud/goedu-theta/internal/database"
 )
@@ -23,22 +32,89 @@ import (
 //   - Provides detailed debug/error logging for each step
 //
 // Error Handling:
-//   - If configuration loading fails, logs the error and exits gracefully
+//   - If configuration loading, database connection or server startup fails,
+//     logs the error and exits with status 1 after running all cleanup
+//   - If the HTTP server stops serving unexpectedly, shuts down and exits with status 1
+//   - If a graceful restart fails, keeps serving in the current process
+//
+// Signals:
+//   - SIGINT, SIGTERM: Graceful shutdown; /ready reports 503 for server.drain_delay seconds before the listeners close
+//   - SIGUSR2: Graceful restart when server.restart.enabled is set; a new process takes over the listeners
 //
 // Usage:
 //
 //	Run the compiled binary. The application expects configuration files in the 'configs/' directory
 //	and optionally a .env file in the project root.
 //
+// Subcommands:
+//   - routes: Prints the routing table for the loaded configuration and exits without serving
+//   - audit-verify: Verifies the hash chain of the audit trail and exits
+//
 // Example:
 //
-//	$ go run cmd/server/main.go
+//	$ go run ./cmd/server
+//	$ go run ./cmd/server routes
+//	$ go run ./cmd/server audit-verify -file audit/audit.log
 //
 // Complexity:
 //
 //	Time: O(1) (all operations are constant time except for file I/O)
 //	Space: O(1) (config struct is small)
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "routes":
+			os.Exit(printRoutes())
+		case "audit-verify":
+			os.Exit(auditVerify(os.Args[2:]))
+		}
+	}
+	os.Exit(run())
+}
+
+// printRoutes prints every route the server would register with the loaded
+// configuration, including admin routes when server.admin_port is set.
+//
+// Returns:
+//   - int: Process exit code; 1 if the configuration cannot be loaded
+func printRoutes() int {
+	// Keep configuration logging out of the table
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error loading configuration:", err)
+		return 1
+	}
+
+	// Building the server registers its routes without binding any listener
+	srv := server.NewServer(cfg.Server, slog.Default())
+	if err := routes.Write(os.Stdout, srv.Routes().Routes("")); err != nil {
+		fmt.Fprintln(os.Stderr, "error writing routes:", err)
+		return 1
+	}
+	return 0
+}
+
+// run starts the application and blocks until it is shut down.
+//
+// Returns:
+//   - int: Process exit code; 0 after a graceful shutdown, 1 after any failure
+//
+// Keeping the application in run lets deferred cleanup (database, audit trail,
+// log flushing) complete before main calls os.Exit.
+//
+// Shutdown Phases:
+// Resources register a shutdown phase as they are acquired; the phases run in
+// reverse order within one server.shutdown_timeout budget, each logged with
+// its duration. A full shutdown runs:
+//   - readiness: /ready reports 503 and keep-alives are off for the drain delay
+//   - requests: Listeners close and in-flight requests finish
+//   - workers: Background workers are canceled and awaited
+//   - logs: Queued log records are written out
+//   - audit: The service.stop (or, after a graceful restart, service.handover) event is recorded and the audit trail closed
+//   - database: The MongoDB connection is closed last
+func run() (code int) {
 	// Initialize the slog bootstrap logger for early logging.
 	// This logger uses default settings and is replaced after config is loaded.
 	logger.InitializeBootstrapLogger()
@@ -55,42 +131,109 @@ func main() {
 		slog.Error("🔠 Error loading configuration",
 			slog.Any("error", err),
 		)
-		return
+		return 1
 	}
 
-	// Log the loaded configuration for debugging purposes.
-	// This is useful for verifying that all config values are as expected.
+	// Log the main configuration values for debugging purposes.
+	// The bootstrap logger does not redact, so only secret-free fields are listed;
+	// the admin listener's /config endpoint shows the full redacted configuration.
 	slog.Debug("🔠 Configuration loaded successfully",
-		slog.Any("config", cfg),
+		slog.String("environment", cfg.Environment), // Selected environment
+		slog.Int("server_port", cfg.Server.Port),    // Public API port
+		slog.Any("database", cfg.Database),          // Masks the password via Database.LogValue
 	)
 
 	// Reconfigure the logger with the loaded configuration.
 	// This allows log level, format, and other options to be set via config.
 	logger.ConfigureLogger(cfg.Logger)
 
+	// Drain the async log queue and close remote log sinks last, after every other deferred cleanup has logged.
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := logger.Shutdown(ctx); err != nil {
+			slog.Error("❌ Failed to flush remote log sinks", slog.Any("error", err))
+		}
+	}()
+
 	// Confirm that the logger has been reconfigured.
 	slog.Debug("🔠 Logger configured successfully",
-		slog.Any("logger", cfg.Logger),
+		slog.String("level", cfg.Logger.Level),                      // Minimum log level
+		slog.String("format", cfg.Logger.Format),                    // json, text or pretty
+		slog.String("output", cfg.Logger.Output),                    // Local destination
+		slog.Attr{Key: "sinks", Value: sinkGroup(cfg.Logger.Sinks)}, // Remote collectors, headers masked
 	)
 
+	// Release everything acquired below in reverse order, also when startup fails.
+	// After a graceful restart the new process serves the same sockets and
+	// continues the audit trail, which changes how this one shuts down.
+	handedOver := false
+	shutdown := lifecycle.NewShutdown(logger.GetLogger())
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout)*time.Second)
+		defer cancel()
+		if err := shutdown.Run(ctx); err != nil {
+			slog.Error("❌ Graceful shutdown incomplete", slog.Any("error", err))
+			code = 1
+			return
+		}
+		if code == 0 {
+			slog.Info("✅ Server shutdown completed successfully")
+		}
+	}()
+
 	// Initialize MongoDB connection
 	slog.Info("🍃 Initializing MongoDB connection...")
 
 	dbManager, err := database.NewMongoDBManager(cfg.Database, logger.GetLogger())
 	if err != nil {
 		slog.Error("❌ Failed to initialize MongoDB connection", slog.Any("error", err))
-		return
+		return 1
 	}
-	defer func() {
+	shutdown.Add("database", func(context.Context) error {
 		if err := dbManager.Close(); err != nil {
-			slog.Error("❌ Failed to close MongoDB connection", slog.Any("error", err))
-		} else {
-			slog.Info("🍃 MongoDB connection closed successfully")
+			return err
 		}
-	}()
+		slog.Info("🍃 MongoDB connection closed successfully")
+		return nil
+	})
 
 	slog.Info("🍃 MongoDB connection established successfully")
 
+	// Open the audit trail. Startup fails if it cannot be opened, so administrative
+	// actions are never served without being recorded.
+	auditStore, err := audit.OpenStore(context.Background(), cfg.Audit, dbManager.GetDatabase())
+	if err != nil {
+		slog.Error("❌ Failed to open audit trail", slog.Any("error", err))
+		return 1
+	}
+	if auditStore == nil {
+		slog.Warn("📝 Audit trail disabled - administrative actions will be rejected")
+	} else {
+		auditLog, err := audit.New(context.Background(), auditStore, []byte(cfg.Audit.HMACSecret), logger.GetLogger())
+		if err != nil {
+			slog.Error("❌ Failed to initialize audit trail", slog.Any("error", err))
+			return 1
+		}
+		audit.SetDefault(auditLog)
+		shutdown.Add("audit", func(ctx context.Context) error {
+			action := "service.stop"
+			if handedOver {
+				action = "service.handover" // The new process keeps the service running
+			}
+			_ = audit.Record(ctx, audit.System("goedu-theta"), action,
+				audit.Resource{Type: "service", ID: "goedu-theta"}, audit.OutcomeSuccess)
+			audit.SetDefault(nil)
+			return auditLog.Close(ctx)
+		})
+		if err := audit.Record(context.Background(), audit.System("goedu-theta"), "service.start",
+			audit.Resource{Type: "service", ID: "goedu-theta"}, audit.OutcomeSuccess); err != nil {
+			slog.Error("❌ Failed to write to audit trail", slog.Any("error", err))
+			return 1
+		}
+		slog.Info("📝 Audit trail opened", slog.String("backend", cfg.Audit.Backend))
+	}
+
 
 
 	// This is a placeholder for future application startup code.
@@ -98,39 +241,149 @@ func main() {
 
 	// Create the HTTP server instance
 	httpServer := server.NewServer(cfg.Server, logger.GetLogger())
+	httpServer.SetConfigDump(cfg) // Served redacted on the admin listener, if enabled
+
+	// Share rate limit buckets across instances when a persistent store is configured
+	if cfg.Server.RateLimit.Enabled {
+		rateLimitStore, err := ratelimit.OpenStore(context.Background(), cfg.Server.RateLimit, dbManager.GetDatabase())
+		if err != nil {
+			slog.Error("❌ Failed to open rate limit store", slog.Any("error", err))
+			return 1
+		}
+		httpServer.SetRateLimitStore(rateLimitStore)
+		slog.Info("🚦 Rate limiting enabled",
+			slog.String("store", cfg.Server.RateLimit.Store),    // Bucket storage backend
+			slog.Int("requests", cfg.Server.RateLimit.Requests), // Default requests per window
+			slog.Int("window", cfg.Server.RateLimit.Window),     // Default window in seconds
+		)
+	}
+
+	// Recognise retried requests across instances when a persistent store is configured
+	if cfg.Server.Idempotency.Enabled {
+		idempotencyStore, err := idempotency.OpenStore(context.Background(), cfg.Server.Idempotency, dbManager.GetDatabase())
+		if err != nil {
+			slog.Error("❌ Failed to open idempotency key store", slog.Any("error", err))
+			return 1
+		}
+		httpServer.SetIdempotencyStore(idempotencyStore)
+		slog.Info("🔁 Idempotency keys enabled",
+			slog.String("store", cfg.Server.Idempotency.Store),  // Key storage backend
+			slog.Int("ttl", cfg.Server.Idempotency.TTL),         // Seconds keys are remembered
+			slog.Any("methods", cfg.Server.Idempotency.Methods), // Methods honouring Idempotency-Key
+		)
+	}
 
 	// Start the HTTP server
 	if err := httpServer.Start(); err != nil {
 		slog.Error("❌ Failed to start HTTP server",
 			slog.Any("error", err),
 		)
-		return
+		return 1
 	}
 
 	slog.Info("🪛 HTTP server started successfully",
-		slog.String("address", cfg.Server.Host),
-		slog.Int("port", cfg.Server.Port),
+		slog.Any("addresses", httpServer.Addrs()), // Bound addresses, including OS-assigned ports and Unix sockets
 	)
 
+	// Registered in reverse: readiness runs first, then requests, workers and logs.
+	// After a graceful restart this process skips the readiness delay instead of
+	// reporting the shared sockets not ready.
+	shutdown.Add("logs", func(ctx context.Context) error {
+		// Losing queued log records does not fail the shutdown
+		if err := logger.Flush(ctx); err != nil {
+			slog.Warn("⚠️ Log buffers not fully flushed before shutdown timeout",
+				slog.Any("error", err),
+			)
+		}
+		return nil
+	})
+	shutdown.Add("workers", httpServer.Workers().Stop)
+	shutdown.Add("requests", httpServer.Shutdown)
+	shutdown.Add("readiness", func(ctx context.Context) error {
+		if handedOver {
+			return nil
+		}
+		return httpServer.Drain(ctx)
+	})
+
+	// Let the previous process drain and exit if this one replaces it in a graceful restart
+	if err := restart.Ready(); err != nil {
+		slog.Error("❌ Failed to notify previous process", slog.Any("error", err))
+	}
+
 	// Set up graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
-	// Wait for shutdown signal
-	<-quit
-	slog.Info("🛑 Shutdown signal received, initiating graceful shutdown...")
+	// Set up graceful restart; signals arriving during a restart are handled after it
+	restartRequests := make(chan os.Signal, 1)
+	if cfg.Server.Restart.Enabled {
+		signal.Notify(restartRequests, syscall.SIGUSR2)
+	} else {
+		signal.Ignore(syscall.SIGUSR2) // Instead of terminating, the default action
+	}
+
+	// Wait for a shutdown signal, a completed restart or for the server to stop serving on its own
+	exitCode := 0
+wait:
+	for {
+		select {
+		case <-quit:
+			slog.Info("🛑 Shutdown signal received, initiating graceful shutdown...")
+			break wait
+		case <-restartRequests:
+			slog.Info("🔁 Restart signal received, starting new process...")
+			pid, err := handOver(httpServer, cfg.Server.Restart)
+			if err != nil {
+				slog.Error("❌ Graceful restart failed, continuing to serve", slog.Any("error", err))
+				continue
+			}
+			slog.Info("🔁 New process is serving, draining this one",
+				slog.Int("pid", pid), // Process now accepting connections
+			)
+			handedOver = true
+			break wait
+		case <-httpServer.Done():
+			slog.Error("❌ HTTP server stopped unexpectedly, shutting down",
+				slog.Any("error", httpServer.Err()),
+			)
+			exitCode = 1
+			break wait
+		}
+	}
 
-	// Create shutdown context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout)*time.Second)
-	defer cancel()
+	// The deferred shutdown sequence drains and releases everything
+	return exitCode
+}
 
-	// Shutdown the HTTP server gracefully
-	if err := httpServer.Shutdown(ctx); err != nil {
-		slog.Error("❌ Error during server shutdown",
-			slog.Any("error", err),
-		)
-		return
+// sinkGroup lists sinks as a group keyed by position, so each sink is logged
+// through LogSink.LogValue with its authentication headers masked.
+func sinkGroup(sinks []config.LogSink) slog.Value {
+	attrs := make([]slog.Attr, len(sinks))
+	for i, sink := range sinks {
+		attrs[i] = slog.Any(strconv.Itoa(i), sink)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// handOver starts a new process of this executable with the listeners of
+// httpServer and waits until it serves them.
+//
+// Returns:
+//   - int: PID of the new process
+//   - error: Why the new process is not serving; the current one must keep serving
+func handOver(httpServer *server.Server, cfg config.ServerRestart) (int, error) {
+	listeners, err := httpServer.ListenerFiles()
+	if err != nil {
+		return 0, err
 	}
+	defer func() {
+		for _, l := range listeners {
+			l.File.Close()
+		}
+	}()
 
-	slog.Info("✅ Server shutdown completed successfully")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Timeout)*time.Second)
+	defer cancel()
+	return restart.Spawn(ctx, listeners)
 }
@@ -0,0 +1,138 @@
+// Package apiversion supports the versioned API under /api: negotiating the
+// version from the Accept header, announcing the deprecation of routes, and
+// counting calls to deprecated routes so they can be removed once clients
+// have migrated.
+//
+// Version Negotiation:
+// Clients select a version by path (/api/v2/...) or, where the server serves
+// unversioned paths, with a vendor media type in the Accept header:
+//
+//	Accept: application/vnd.goedu-theta.v2+json
+//
+// Deprecation:
+// A deprecated route answers with the Deprecation (RFC 9745), Sunset
+// (RFC 8594) and Link rel="successor-version" headers, so clients learn about
+// the retirement from the responses themselves.
+//
+// Usage Examples:
+//
+//	dep := apiversion.Deprecation{
+//	    Since:     time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+//	    Sunset:    time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC),
+//	    Successor: "/api/v2/",
+//	}
+//	apiversion.TrackDeprecated("GET /api/v1/", dep)
+//
+//	// In the route's handler chain:
+//	dep.SetHeaders(c.Writer.Header())
+//	apiversion.RecordDeprecatedCall("GET /api/v1/")
+//
+// Metrics:
+// Calls to deprecated routes are reported by DeprecatedCalls for the /metrics
+// endpoint, including routes nobody calls any more.
+package apiversion
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Vendor media type parts; the version goes in between.
+const (
+	mediaTypePrefix = "application/vnd.goedu-theta."
+	mediaTypeSuffix = "+json"
+)
+
+// MediaType returns the Accept media type selecting version, e.g.
+// "application/vnd.goedu-theta.v2+json".
+func MediaType(version string) string {
+	return mediaTypePrefix + version + mediaTypeSuffix
+}
+
+// FromAccept returns the version named by the first vendor media type in an
+// Accept header. ok is false when the header names no version.
+func FromAccept(accept string) (version string, ok bool) {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(mediaRange, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		if rest, found := strings.CutPrefix(mediaType, mediaTypePrefix); found {
+			if version, found := strings.CutSuffix(rest, mediaTypeSuffix); found && version != "" {
+				return version, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Deprecation declares a route deprecated.
+type Deprecation struct {
+	Since     time.Time // When the route was deprecated
+	Sunset    time.Time // When the route will be removed; zero if not yet scheduled
+	Successor string    // Path of the replacement route; empty if there is none
+}
+
+// SetHeaders writes the Deprecation, Sunset and Link headers announcing d.
+func (d Deprecation) SetHeaders(h http.Header) {
+	h.Set("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+	if !d.Sunset.IsZero() {
+		h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Successor != "" {
+		h.Add("Link", "<"+d.Successor+`>; rel="successor-version"`)
+	}
+}
+
+// DeprecatedCallStats are the call counters of one deprecated route.
+type DeprecatedCallStats struct {
+	Route    string    // "METHOD /path"
+	Sunset   time.Time // Planned removal; zero if not yet scheduled
+	Calls    uint64    // Calls since startup
+	LastCall time.Time // Time of the latest call; zero if never called
+}
+
+var (
+	statsMu sync.Mutex
+	stats   = make(map[string]*DeprecatedCallStats)
+)
+
+// TrackDeprecated starts counting calls to a deprecated route, so it is
+// reported even before its first call.
+func TrackDeprecated(route string, d Deprecation) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	if s, ok := stats[route]; ok {
+		s.Sunset = d.Sunset
+		return
+	}
+	stats[route] = &DeprecatedCallStats{Route: route, Sunset: d.Sunset}
+}
+
+// RecordDeprecatedCall counts a call to a deprecated route.
+func RecordDeprecatedCall(route string) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	s, ok := stats[route]
+	if !ok {
+		s = &DeprecatedCallStats{Route: route}
+		stats[route] = s
+	}
+	s.Calls++
+	s.LastCall = time.Now().UTC()
+}
+
+// DeprecatedCalls returns the call counters of every tracked deprecated
+// route, sorted by route.
+func DeprecatedCalls() []DeprecatedCallStats {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	result := make([]DeprecatedCallStats, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Route < result[j].Route })
+	return result
+}
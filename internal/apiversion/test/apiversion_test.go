@@ -0,0 +1,86 @@
+package apiversion_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apiversion"
+)
+
+// TestFromAccept tests reading the API version from Accept headers.
+func TestFromAccept(t *testing.T) {
+	tests := []struct {
+		accept  string
+		version string
+		ok      bool
+	}{
+		{"application/vnd.goedu-theta.v2+json", "v2", true},
+		{"text/html, application/vnd.goedu-theta.v1+json;q=0.9, */*;q=0.1", "v1", true},
+		{"Application/VND.GoEdu-Theta.V3+JSON", "v3", true},
+		{"application/json", "", false},
+		{"application/vnd.goedu-theta.+json", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		version, ok := apiversion.FromAccept(tt.accept)
+		if version != tt.version || ok != tt.ok {
+			t.Errorf("FromAccept(%q) = %q, %v; expected %q, %v", tt.accept, version, ok, tt.version, tt.ok)
+		}
+	}
+	if got := apiversion.MediaType("v2"); got != "application/vnd.goedu-theta.v2+json" {
+		t.Errorf("Unexpected media type %q", got)
+	}
+}
+
+// TestDeprecation_SetHeaders tests the Deprecation, Sunset and Link headers.
+func TestDeprecation_SetHeaders(t *testing.T) {
+	h := http.Header{}
+	apiversion.Deprecation{
+		Since:     time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC),
+		Successor: "/api/v2/",
+	}.SetHeaders(h)
+
+	if got := h.Get("Deprecation"); got != "@1792281600" {
+		t.Errorf("Deprecation: got %q", got)
+	}
+	if got := h.Get("Sunset"); got != "Fri, 30 Apr 2027 00:00:00 GMT" {
+		t.Errorf("Sunset: got %q", got)
+	}
+	if got := h.Get("Link"); got != `</api/v2/>; rel="successor-version"` {
+		t.Errorf("Link: got %q", got)
+	}
+
+	// Unscheduled removal without a successor sends the Deprecation header only
+	h = http.Header{}
+	apiversion.Deprecation{Since: time.Unix(0, 0)}.SetHeaders(h)
+	if h.Get("Deprecation") != "@0" || h.Get("Sunset") != "" || h.Get("Link") != "" {
+		t.Errorf("Unexpected headers %v", h)
+	}
+}
+
+// TestDeprecatedCalls tests that tracked routes are reported before and after calls.
+func TestDeprecatedCalls(t *testing.T) {
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	apiversion.TrackDeprecated("GET /test/calls", apiversion.Deprecation{Sunset: sunset})
+
+	find := func() apiversion.DeprecatedCallStats {
+		for _, s := range apiversion.DeprecatedCalls() {
+			if s.Route == "GET /test/calls" {
+				return s
+			}
+		}
+		t.Fatal("Tracked route not reported")
+		return apiversion.DeprecatedCallStats{}
+	}
+
+	if s := find(); s.Calls != 0 || !s.LastCall.IsZero() || !s.Sunset.Equal(sunset) {
+		t.Errorf("Expected an unused route with its sunset, got %+v", s)
+	}
+	apiversion.RecordDeprecatedCall("GET /test/calls")
+	apiversion.RecordDeprecatedCall("GET /test/calls")
+	if s := find(); s.Calls != 2 || s.LastCall.IsZero() {
+		t.Errorf("Expected 2 calls, got %+v", s)
+	}
+}
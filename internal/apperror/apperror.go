@@ -0,0 +1,251 @@
+// Package apperror defines typed application errors and their rendering as
+// RFC 7807 problem details (application/problem+json).
+//
+// Handlers return or build an *Error carrying a stable machine-readable code,
+// the HTTP status, a client-safe message and optional details and field
+// errors. The underlying cause can be attached with Wrap; it is logged but
+// never sent to clients. Any other error is treated as an internal error, so
+// unexpected failures never leak implementation details.
+//
+// Usage Examples:
+//
+//	if course == nil {
+//	    return apperror.NotFound("course not found").WithDetail("course_id", id)
+//	}
+//
+//	return apperror.Validation("invalid enrollment",
+//	    apperror.FieldError{Field: "email", Code: "required", Message: "email is required"})
+//
+//	return apperror.Internal(err) // 500, cause kept for logging
+//
+// Response Format:
+//
+//	HTTP/1.1 404 Not Found
+//	Content-Type: application/problem+json
+//
+//	{
+//	    "type": "about:blank",
+//	    "title": "Not Found",
+//	    "status": 404,
+//	    "detail": "course not found",
+//	    "instance": "/api/v1/courses/42",
+//	    "code": "not_found",
+//	    "request_id": "4f3c2a9e0d1b4c6a8e7f5d3b1a092c4e",
+//	    "details": {"course_id": "42"}
+//	}
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ContentType is the media type of problem detail responses.
+const ContentType = "application/problem+json"
+
+// Error codes shared across the API. Clients should branch on the code, not
+// on the message, which may change.
+const (
+	CodeBadRequest           = "bad_request"
+	CodeValidation           = "validation_failed"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeNotAcceptable        = "not_acceptable"
+	CodeConflict             = "conflict"
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+	CodePayloadTooLarge      = "payload_too_large"
+	CodeTooManyRequests      = "rate_limited"
+	CodeInternal             = "internal_error"
+	CodeServiceUnavailable   = "service_unavailable"
+	CodeTimeout              = "request_timeout"
+	CodeUpstreamTimeout      = "upstream_timeout"
+)
+
+// FieldError describes a problem with one request field.
+type FieldError struct {
+	Field   string `json:"field"`   // Field name or JSON pointer, e.g. "email" or "/items/0/qty"
+	Code    string `json:"code"`    // Machine-readable reason, e.g. "required"
+	Message string `json:"message"` // Human-readable explanation
+}
+
+// Error is an application error with everything needed to answer a request.
+type Error struct {
+	Code    string         // Stable machine-readable error code
+	Status  int            // HTTP status code
+	Message string         // Client-safe description
+	Details map[string]any // Additional client-safe context
+	Fields  []FieldError   // Per-field validation problems
+	Err     error          // Underlying cause; logged, never sent to clients
+}
+
+// New creates an Error with the given status, code and message.
+func New(status int, code, message string) *Error {
+	return &Error{Code: code, Status: status, Message: message}
+}
+
+// Error implements the error interface, including the cause if present.
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return e.Code + ": " + e.Message
+}
+
+// Unwrap returns the underlying cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap returns a copy of e with err attached as the cause.
+func (e *Error) Wrap(err error) *Error {
+	c := e.clone()
+	c.Err = err
+	return c
+}
+
+// WithDetail returns a copy of e with an additional detail entry.
+func (e *Error) WithDetail(key string, value any) *Error {
+	c := e.clone()
+	c.Details = make(map[string]any, len(e.Details)+1)
+	for k, v := range e.Details {
+		c.Details[k] = v
+	}
+	c.Details[key] = value
+	return c
+}
+
+// WithField returns a copy of e with an additional field error.
+func (e *Error) WithField(field, code, message string) *Error {
+	c := e.clone()
+	c.Fields = append(append([]FieldError(nil), e.Fields...), FieldError{Field: field, Code: code, Message: message})
+	return c
+}
+
+// clone returns a shallow copy so the With methods never modify shared errors.
+func (e *Error) clone() *Error {
+	c := *e
+	return &c
+}
+
+// BadRequest returns a 400 error for malformed requests.
+func BadRequest(message string) *Error {
+	return New(http.StatusBadRequest, CodeBadRequest, message)
+}
+
+// Validation returns a 422 error listing invalid fields.
+func Validation(message string, fields ...FieldError) *Error {
+	e := New(http.StatusUnprocessableEntity, CodeValidation, message)
+	e.Fields = fields
+	return e
+}
+
+// Unauthorized returns a 401 error for missing or invalid credentials.
+func Unauthorized(message string) *Error {
+	return New(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+// Forbidden returns a 403 error for authenticated callers lacking permission.
+func Forbidden(message string) *Error {
+	return New(http.StatusForbidden, CodeForbidden, message)
+}
+
+// NotFound returns a 404 error.
+func NotFound(message string) *Error {
+	return New(http.StatusNotFound, CodeNotFound, message)
+}
+
+// Conflict returns a 409 error for requests conflicting with the current state.
+func Conflict(message string) *Error {
+	return New(http.StatusConflict, CodeConflict, message)
+}
+
+// MethodNotAllowed returns a 405 error listing the methods the resource supports.
+func MethodNotAllowed(method string, allowed []string) *Error {
+	return New(http.StatusMethodNotAllowed, CodeMethodNotAllowed, "method "+method+" is not allowed for this resource").
+		WithDetail("allowed_methods", allowed)
+}
+
+// NotAcceptable returns a 406 error for requests asking for a representation,
+// such as an API version, the resource does not offer.
+func NotAcceptable(message string) *Error {
+	return New(http.StatusNotAcceptable, CodeNotAcceptable, message)
+}
+
+// PayloadTooLarge returns a 413 error for request bodies over limit bytes.
+func PayloadTooLarge(limit int64) *Error {
+	return New(http.StatusRequestEntityTooLarge, CodePayloadTooLarge, fmt.Sprintf("request body exceeds %d bytes", limit)).
+		WithDetail("max_body_bytes", limit)
+}
+
+// TooManyRequests returns a 429 error for clients exceeding their rate limit.
+func TooManyRequests(message string) *Error {
+	return New(http.StatusTooManyRequests, CodeTooManyRequests, message)
+}
+
+// Internal returns a 500 error with cause attached. The message sent to the
+// client is generic.
+func Internal(cause error) *Error {
+	return &Error{Code: CodeInternal, Status: http.StatusInternalServerError, Message: "internal server error", Err: cause}
+}
+
+// ServiceUnavailable returns a 503 error for temporarily unavailable dependencies.
+func ServiceUnavailable(message string) *Error {
+	return New(http.StatusServiceUnavailable, CodeServiceUnavailable, message)
+}
+
+// Timeout returns a 503 error for requests that ran past their handler
+// deadline. The request may be retried later.
+func Timeout() *Error {
+	return New(http.StatusServiceUnavailable, CodeTimeout, "request took too long to process")
+}
+
+// GatewayTimeout returns a 504 error for upstream calls that timed out while
+// the request itself was still within its deadline.
+func GatewayTimeout(message string) *Error {
+	return New(http.StatusGatewayTimeout, CodeUpstreamTimeout, message)
+}
+
+// From converts any error into an *Error. Errors that are not (and do not
+// wrap) an *Error become internal errors, hiding their text from clients.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err)
+}
+
+// Problem is an RFC 7807 problem details document with this API's extension
+// members (code, request_id, errors, details).
+type Problem struct {
+	Type      string         `json:"type"`
+	Title     string         `json:"title"`
+	Status    int            `json:"status"`
+	Detail    string         `json:"detail,omitempty"`
+	Instance  string         `json:"instance,omitempty"`
+	Code      string         `json:"code"`
+	RequestID string         `json:"request_id,omitempty"`
+	Errors    []FieldError   `json:"errors,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+// Problem renders e as problem details for the request path instance.
+//
+// The type is "about:blank", so the title is the standard status text; the
+// code member distinguishes errors sharing a status.
+func (e *Error) Problem(instance, requestID string) Problem {
+	return Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(e.Status),
+		Status:    e.Status,
+		Detail:    e.Message,
+		Instance:  instance,
+		Code:      e.Code,
+		RequestID: requestID,
+		Errors:    e.Fields,
+		Details:   e.Details,
+	}
+}
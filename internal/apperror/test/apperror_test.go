@@ -0,0 +1,55 @@
+package apperror_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apperror"
+)
+
+// TestError_WrapAndFrom tests cause wrapping and conversion of arbitrary errors.
+func TestError_WrapAndFrom(t *testing.T) {
+	cause := errors.New("mongo: no documents in result")
+	err := fmt.Errorf("loading course: %w", apperror.NotFound("course not found").Wrap(cause))
+
+	appErr := apperror.From(err)
+	if appErr.Status != http.StatusNotFound || appErr.Code != apperror.CodeNotFound {
+		t.Errorf("Expected the wrapped not_found error, got %+v", appErr)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("Expected the cause to be reachable with errors.Is")
+	}
+
+	internal := apperror.From(errors.New("boom"))
+	if internal.Status != http.StatusInternalServerError || internal.Message != "internal server error" {
+		t.Errorf("Expected a generic internal error, got %+v", internal)
+	}
+}
+
+// TestError_WithMethodsCopy tests that With methods never modify the receiver.
+func TestError_WithMethodsCopy(t *testing.T) {
+	base := apperror.BadRequest("invalid query")
+	detailed := base.WithDetail("param", "page").WithField("page", "min", "page must be at least 1")
+
+	if base.Details != nil || base.Fields != nil {
+		t.Errorf("Expected the base error to stay unchanged, got %+v", base)
+	}
+	if detailed.Details["param"] != "page" || len(detailed.Fields) != 1 {
+		t.Errorf("Expected detail and field on the copy, got %+v", detailed)
+	}
+}
+
+// TestError_Problem tests rendering as RFC 7807 problem details.
+func TestError_Problem(t *testing.T) {
+	problem := apperror.Validation("invalid enrollment",
+		apperror.FieldError{Field: "email", Code: "required", Message: "email is required"},
+	).Problem("/api/v1/enrollments", "req-1")
+
+	if problem.Type != "about:blank" || problem.Title != "Unprocessable Entity" || problem.Status != 422 ||
+		problem.Detail != "invalid enrollment" || problem.Instance != "/api/v1/enrollments" ||
+		problem.Code != apperror.CodeValidation || problem.RequestID != "req-1" || len(problem.Errors) != 1 {
+		t.Errorf("Unexpected problem %+v", problem)
+	}
+}
@@ -0,0 +1,306 @@
+// Package audit provides a tamper-evident audit trail for administrative actions,
+// kept separate from the operational slog output.
+//
+// Every call to Record appends one Entry to a Store (an append-only file or a
+// dedicated MongoDB collection). Entries carry a gapless sequence number and are
+// chained: each entry's Hash is an HMAC-SHA256 over its own fields plus the Hash
+// of the previous entry. Editing, deleting or reordering any stored entry
+// therefore breaks the chain at that point, which Verify (and the audit-verify
+// subcommand) reports.
+//
+// The HMAC key is configured separately (AUDIT_HMAC_SECRET) and never stored
+// with the trail. Whoever can write to the file or collection but does not
+// hold the key cannot recompute the hashes after an edit, so rewriting the
+// chain from the edited entry onwards is detected as well.
+//
+// Design Principles:
+//   - Fail closed: Record returns an error when the entry could not be persisted,
+//     so callers can refuse the administrative action instead of performing it unaudited
+//   - Typed API: actor, action, resource and outcome are explicit fields, not free-form attributes
+//   - Storage independent: the hash chain is computed here, stores only persist entries
+//
+// Usage Examples:
+//
+//	store, err := audit.OpenFileStore("/var/lib/goedu/audit.log")
+//	auditLog, err := audit.New(ctx, store, []byte(cfg.Audit.HMACSecret), slog.Default())
+//	audit.SetDefault(auditLog)
+//
+//	err = audit.Record(ctx, audit.User("u-42", clientIP), "course.delete",
+//	    audit.Resource{Type: "course", ID: courseID}, audit.OutcomeSuccess)
+//
+// Limitations:
+// The chain proves that stored entries were not altered, but removing entries
+// from the end of the trail cannot be distinguished from those actions never
+// happening. Record the head reported by Verify (sequence and hash) in an
+// external system periodically to anchor the trail against truncation.
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+)
+
+// Outcome is the result of an audited action.
+type Outcome string
+
+const (
+	// OutcomeSuccess records an action that was performed.
+	OutcomeSuccess Outcome = "success"
+
+	// OutcomeFailure records an action that was attempted but failed.
+	OutcomeFailure Outcome = "failure"
+
+	// OutcomeDenied records an action rejected by authorization checks.
+	OutcomeDenied Outcome = "denied"
+)
+
+// Actor identifies who performed an action.
+type Actor struct {
+	Type string `json:"type" bson:"type"`       // "user", "service" or "system"
+	ID   string `json:"id" bson:"id"`           // Stable identifier (user ID, service name)
+	IP   string `json:"ip,omitempty" bson:"ip"` // Client address when the action came from a request
+}
+
+// User returns an Actor for an authenticated user acting from the given client address.
+func User(id, ip string) Actor {
+	return Actor{Type: "user", ID: id, IP: ip}
+}
+
+// System returns an Actor for actions initiated by the application itself.
+func System(name string) Actor {
+	return Actor{Type: "system", ID: name}
+}
+
+// Resource identifies what an action was performed on.
+type Resource struct {
+	Type string `json:"type" bson:"type"` // e.g. "course", "user"
+	ID   string `json:"id" bson:"id"`     // Identifier of the affected object
+}
+
+// Entry is one persisted audit record.
+type Entry struct {
+	Seq      uint64    `json:"seq" bson:"seq"`             // Gapless sequence number starting at 1
+	Time     time.Time `json:"time" bson:"time"`           // UTC, truncated to milliseconds
+	Actor    Actor     `json:"actor" bson:"actor"`         // Who performed the action
+	Action   string    `json:"action" bson:"action"`       // What was done, e.g. "course.update"
+	Resource Resource  `json:"resource" bson:"resource"`   // What it was done to
+	Outcome  Outcome   `json:"outcome" bson:"outcome"`     // Result of the action
+	PrevHash string    `json:"prev_hash" bson:"prev_hash"` // Hash of the entry with Seq-1 ("" for the first)
+	Hash     string    `json:"hash" bson:"hash"`           // HMAC-SHA256 over all fields above
+}
+
+// ComputeHash returns the chain hash of e under key. The hash covers every
+// field except Hash itself, in a fixed JSON encoding, so it is stable across stores.
+func (e Entry) ComputeHash(key []byte) string {
+	canonical, _ := json.Marshal(struct {
+		Seq      uint64   `json:"seq"`
+		Time     string   `json:"time"`
+		Actor    Actor    `json:"actor"`
+		Action   string   `json:"action"`
+		Resource Resource `json:"resource"`
+		Outcome  Outcome  `json:"outcome"`
+		PrevHash string   `json:"prev_hash"`
+	}{e.Seq, e.Time.UTC().Format(time.RFC3339Nano), e.Actor, e.Action, e.Resource, e.Outcome, e.PrevHash})
+	mac := hmac.New(sha256.New, key)
+	mac.Write(canonical)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Store persists audit entries. Implementations must keep entries in sequence
+// order and must never modify an entry once appended.
+type Store interface {
+	// Append persists e. It returns ErrConflict if an entry with e.Seq already
+	// exists, i.e. another writer extended the chain concurrently.
+	Append(ctx context.Context, e Entry) error
+
+	// Last returns the entry with the highest sequence number, or nil for an empty trail.
+	Last(ctx context.Context) (*Entry, error)
+
+	// Iterate calls fn for every entry in sequence order, stopping at the first error.
+	Iterate(ctx context.Context, fn func(Entry) error) error
+
+	// Close releases the underlying resources.
+	Close(ctx context.Context) error
+}
+
+var (
+	// ErrConflict is returned by Store.Append when the sequence number is already taken.
+	ErrConflict = errors.New("audit entry sequence already exists")
+
+	// ErrNotConfigured is returned by the package-level Record before SetDefault was called.
+	ErrNotConfigured = errors.New("audit logger not configured")
+
+	// ErrCorrupt is returned by Store.Iterate when stored data cannot be decoded as an entry.
+	ErrCorrupt = errors.New("audit trail corrupt")
+
+	// ErrWeakKey is returned by New and Verify when the HMAC key is shorter than MinKeyLength.
+	ErrWeakKey = fmt.Errorf("audit HMAC key must be at least %d bytes", MinKeyLength)
+)
+
+// MinKeyLength is the shortest accepted HMAC key, e.g. from `openssl rand -hex 32`.
+const MinKeyLength = 32
+
+// maxAppendAttempts bounds retries after ErrConflict when several instances share a store.
+const maxAppendAttempts = 5
+
+// Logger appends chained entries to a Store. It is safe for concurrent use.
+type Logger struct {
+	store  Store
+	key    []byte // HMAC key of the chain
+	logger *slog.Logger
+	now    func() time.Time
+
+	mu   sync.Mutex
+	last *Entry // Head of the chain, nil when the trail is empty
+}
+
+// New creates an audit Logger that continues the chain already present in store.
+//
+// Parameters:
+//   - ctx: Bounds loading the current head of the chain
+//   - store: Destination for audit entries
+//   - key: HMAC key of the chain, at least MinKeyLength bytes; keep it out of the store
+//   - logger: Operational logger for audit failures (not for the audit entries themselves)
+//
+// Returns:
+//   - *Logger: Ready to record entries
+//   - error: ErrWeakKey, or the head of the chain could not be read
+func New(ctx context.Context, store Store, key []byte, logger *slog.Logger) (*Logger, error) {
+	if len(key) < MinKeyLength {
+		return nil, ErrWeakKey
+	}
+	last, err := store.Last(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load audit trail head: %w", err)
+	}
+	return &Logger{store: store, key: key, logger: logger, now: time.Now, last: last}, nil
+}
+
+// Record appends an entry for an action and returns once it is persisted.
+// A non-nil error means the action is NOT audited; callers handling
+// administrative requests should treat it as a failure of the request.
+func (l *Logger) Record(ctx context.Context, actor Actor, action string, resource Resource, outcome Outcome) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		e := Entry{
+			Seq:      1,
+			Time:     l.now().UTC().Truncate(time.Millisecond), // MongoDB stores millisecond precision
+			Actor:    actor,
+			Action:   action,
+			Resource: resource,
+			Outcome:  outcome,
+		}
+		if l.last != nil {
+			e.Seq = l.last.Seq + 1
+			e.PrevHash = l.last.Hash
+		}
+		e.Hash = e.ComputeHash(l.key)
+
+		err := l.store.Append(ctx, e)
+		if err == nil {
+			l.last = &e
+			return nil
+		}
+		if !errors.Is(err, ErrConflict) || attempt == maxAppendAttempts {
+			l.logger.Error("📝 Failed to write audit entry",
+				slog.String("action", action),
+				slog.String("actor", actor.ID),
+				slog.String("resource", resource.Type+"/"+resource.ID),
+				slog.Any("error", err),
+			)
+			return fmt.Errorf("failed to write audit entry: %w", err)
+		}
+
+		// Another instance extended the chain; continue from its head.
+		last, lastErr := l.store.Last(ctx)
+		if lastErr != nil {
+			return fmt.Errorf("failed to reload audit trail head: %w", lastErr)
+		}
+		l.last = last
+	}
+}
+
+// Close closes the underlying store.
+func (l *Logger) Close(ctx context.Context) error {
+	return l.store.Close(ctx)
+}
+
+// Package-level default logger used by Record.
+var (
+	defaultMu     sync.RWMutex
+	defaultLogger *Logger
+)
+
+// SetDefault installs l as the logger used by the package-level Record function.
+// Passing nil disables auditing; Record then returns ErrNotConfigured.
+func SetDefault(l *Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLogger = l
+}
+
+// Default returns the logger installed by SetDefault, or nil.
+func Default() *Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLogger
+}
+
+// Record appends an entry through the default logger.
+// It returns ErrNotConfigured if SetDefault has not been called.
+//
+// Example:
+//
+//	if err := audit.Record(ctx, audit.User(userID, c.ClientIP()), "user.role.grant",
+//	    audit.Resource{Type: "user", ID: targetID}, audit.OutcomeSuccess); err != nil {
+//	    c.AbortWithStatus(http.StatusInternalServerError)
+//	    return
+//	}
+func Record(ctx context.Context, actor Actor, action string, resource Resource, outcome Outcome) error {
+	l := Default()
+	if l == nil {
+		return ErrNotConfigured
+	}
+	return l.Record(ctx, actor, action, resource, outcome)
+}
+
+// OpenStore opens the store selected by cfg.Backend. It returns a nil Store
+// and no error when auditing is disabled. db is only used by the "mongodb"
+// backend and may be nil otherwise.
+func OpenStore(ctx context.Context, cfg config.Audit, db *mongo.Database) (Store, error) {
+	switch strings.ToLower(cfg.Backend) {
+	case "disabled", "none":
+		return nil, nil
+	case "mongodb", "mongo":
+		if db == nil {
+			return nil, errors.New("audit backend mongodb requires a database connection")
+		}
+		return NewMongoStore(ctx, db, cfg.Collection)
+	case "file", "":
+		path := cfg.FilePath
+		if path == "" {
+			path = defaultFilePath
+		}
+		return OpenFileStore(path)
+	default:
+		return nil, fmt.Errorf("unknown audit backend %q", cfg.Backend)
+	}
+}
+
+// defaultFilePath is the audit file used by the "file" backend when no path is configured.
+const defaultFilePath = "audit/audit.log"
@@ -0,0 +1,187 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// maxFileEntrySize bounds a single JSON line when reading an audit file.
+const maxFileEntrySize = 1 << 20
+
+// FileStore keeps the audit trail in an append-only JSON Lines file, one entry
+// per line. Each append is synced to disk before Record returns.
+//
+// The file is opened with O_APPEND and never rewritten. Several processes on
+// the same host may write one file, e.g. the old and new process during a
+// graceful restart: appends hold an exclusive flock and re-read entries other
+// writers added, so a stale head yields ErrConflict instead of a forked chain.
+// flock is advisory and unreliable on network filesystems; for several hosts
+// use MongoStore. Protect the file with filesystem permissions (it is created
+// with mode 0600) and, where available, the append-only attribute (chattr +a).
+type FileStore struct {
+	path string
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64  // Offset after the last complete entry, used to undo torn writes
+	last *Entry // Head of the chain as of the last read up to size
+}
+
+// OpenFileStore opens (or creates) the audit file at path and loads the head of
+// its chain. It fails if the file's last line is not a complete entry, so a
+// damaged trail is never silently extended.
+func OpenFileStore(path string) (*FileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+
+	s := &FileStore{path: path, f: f}
+	if err := s.withLock(syscall.LOCK_SH, s.refresh); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// withLock runs fn while holding an flock of the given kind (LOCK_SH or LOCK_EX)
+// on the audit file, serializing it with other processes writing the same file.
+func (s *FileStore) withLock(how int, fn func() error) error {
+	if err := syscall.Flock(int(s.f.Fd()), how); err != nil {
+		return fmt.Errorf("failed to lock audit file: %w", err)
+	}
+	defer syscall.Flock(int(s.f.Fd()), syscall.LOCK_UN)
+	return fn()
+}
+
+// refresh reads the entries other writers appended since the last read and
+// advances the cached head. The caller must hold s.mu and an flock.
+func (s *FileStore) refresh() error {
+	info, err := s.f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat audit file: %w", err)
+	}
+	if info.Size() == s.size {
+		return nil
+	}
+	if info.Size() < s.size {
+		return fmt.Errorf("%w: file shrank from %d to %d bytes", ErrCorrupt, s.size, info.Size())
+	}
+
+	tail := io.NewSectionReader(s.f, s.size, info.Size()-s.size)
+	if err := readEntries(context.Background(), tail, func(e Entry) error {
+		s.last = &e
+		return nil
+	}); err != nil {
+		return err
+	}
+	s.size = info.Size()
+	return nil
+}
+
+// Append implements Store. It returns ErrConflict when e does not directly
+// follow the head of the file, including entries appended by other processes.
+func (s *FileStore) Append(_ context.Context, e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.withLock(syscall.LOCK_EX, func() error {
+		if err := s.refresh(); err != nil {
+			return err
+		}
+		if s.last != nil && e.Seq <= s.last.Seq {
+			return ErrConflict
+		}
+		return s.write(e)
+	})
+}
+
+// write appends e to the file. The caller must hold s.mu and an exclusive flock.
+func (s *FileStore) write(e Entry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	n, err := s.f.Write(line)
+	if err == nil {
+		err = s.f.Sync()
+	}
+	if err != nil {
+		// Remove a partially written line so the file stays parseable.
+		if n > 0 {
+			_ = s.f.Truncate(s.size)
+		}
+		return fmt.Errorf("failed to write audit file: %w", err)
+	}
+	s.size += int64(n)
+	s.last = &e
+	return nil
+}
+
+// Last implements Store. It includes entries appended by other processes.
+func (s *FileStore) Last(context.Context) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.withLock(syscall.LOCK_SH, s.refresh); err != nil {
+		return nil, err
+	}
+	if s.last == nil {
+		return nil, nil
+	}
+	last := *s.last
+	return &last, nil
+}
+
+// Iterate implements Store. Lines that are not valid entries yield ErrCorrupt.
+func (s *FileStore) Iterate(ctx context.Context, fn func(Entry) error) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	defer f.Close()
+	return readEntries(ctx, f, fn)
+}
+
+// readEntries decodes the JSON lines in r and calls fn for each entry in order.
+// Lines that are not valid entries yield ErrCorrupt.
+func readEntries(ctx context.Context, r io.Reader, fn func(Entry) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxFileEntrySize)
+	for line := 1; scanner.Scan(); line++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var e Entry
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&e); err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrCorrupt, line, err)
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	return nil
+}
+
+// Close implements Store.
+func (s *FileStore) Close(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
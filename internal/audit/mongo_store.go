@@ -0,0 +1,92 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultCollection is the MongoDB collection used when none is configured.
+const DefaultCollection = "audit_log"
+
+// MongoStore keeps the audit trail in a dedicated MongoDB collection with a
+// unique index on the sequence number, so several application instances can
+// share one chain: a concurrent append fails with ErrConflict and is retried
+// on top of the new head.
+//
+// The application should connect with a role that may only insert and find in
+// this collection, so the trail cannot be modified through the application.
+type MongoStore struct {
+	coll *mongo.Collection
+}
+
+// NewMongoStore prepares the audit collection in db, creating the unique
+// sequence index if needed. An empty collection name selects DefaultCollection.
+func NewMongoStore(ctx context.Context, db *mongo.Database, collection string) (*MongoStore, error) {
+	if collection == "" {
+		collection = DefaultCollection
+	}
+	coll := db.Collection(collection)
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "seq", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("seq_unique"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit sequence index: %w", err)
+	}
+	return &MongoStore{coll: coll}, nil
+}
+
+// Append implements Store.
+func (s *MongoStore) Append(ctx context.Context, e Entry) error {
+	if _, err := s.coll.InsertOne(ctx, e); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrConflict
+		}
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+	return nil
+}
+
+// Last implements Store.
+func (s *MongoStore) Last(ctx context.Context) (*Entry, error) {
+	var e Entry
+	err := s.coll.FindOne(ctx, bson.D{}, options.FindOne().SetSort(bson.D{{Key: "seq", Value: -1}})).Decode(&e)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit trail head: %w", err)
+	}
+	return &e, nil
+}
+
+// Iterate implements Store. Documents that cannot be decoded yield ErrCorrupt.
+func (s *MongoStore) Iterate(ctx context.Context, fn func(Entry) error) error {
+	cursor, err := s.coll.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "seq", Value: 1}}))
+	if err != nil {
+		return fmt.Errorf("failed to read audit trail: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var e Entry
+		if err := cursor.Decode(&e); err != nil {
+			return fmt.Errorf("%w: document %v: %v", ErrCorrupt, cursor.Current.Lookup("_id"), err)
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// Close implements Store. The MongoDB client is owned by the database package
+// and is not closed here.
+func (s *MongoStore) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,401 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/audit"
+)
+
+var (
+	testActor    = audit.User("admin-1", "10.0.0.1")
+	testResource = audit.Resource{Type: "course", ID: "c-42"}
+	testKey      = []byte("0123456789abcdef0123456789abcdef")
+)
+
+// discardLogger returns an operational logger that drops all output.
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// writeTrail records n entries into a new audit file and returns its path.
+func writeTrail(t *testing.T, n int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	store, err := audit.OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open file store: %v", err)
+	}
+	log, err := audit.New(context.Background(), store, testKey, discardLogger())
+	if err != nil {
+		t.Fatalf("Failed to create audit logger: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		if err := log.Record(context.Background(), testActor, "course.update", testResource, audit.OutcomeSuccess); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+	if err := log.Close(context.Background()); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	return path
+}
+
+// verifyFile opens the trail at path and verifies it.
+func verifyFile(t *testing.T, path string) audit.Report {
+	t.Helper()
+	store, err := audit.OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open file store: %v", err)
+	}
+	defer store.Close(context.Background())
+	report, err := audit.Verify(context.Background(), store, testKey)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	return report
+}
+
+// rewriteLines applies edit to the lines of the audit file.
+func rewriteLines(t *testing.T, path string, edit func([]string) []string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read audit file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if err := os.WriteFile(path, []byte(strings.Join(edit(lines), "\n")+"\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write audit file: %v", err)
+	}
+}
+
+func TestFileStore_ChainIsValidAcrossReopen(t *testing.T) {
+	path := writeTrail(t, 3)
+
+	// Reopening continues the existing chain.
+	store, err := audit.OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen file store: %v", err)
+	}
+	log, err := audit.New(context.Background(), store, testKey, discardLogger())
+	if err != nil {
+		t.Fatalf("Failed to create audit logger: %v", err)
+	}
+	if err := log.Record(context.Background(), testActor, "course.delete", testResource, audit.OutcomeDenied); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	log.Close(context.Background())
+
+	report := verifyFile(t, path)
+	if !report.Valid() || report.Entries != 4 || report.HeadSeq != 4 {
+		t.Errorf("Expected a valid 4-entry trail, got %+v", report)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected audit file mode 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	testCases := []struct {
+		name     string
+		edit     func([]string) []string
+		wantKind audit.ProblemKind
+		wantSeq  uint64
+	}{
+		{
+			name: "Edited entry",
+			edit: func(lines []string) []string {
+				lines[1] = strings.Replace(lines[1], `"outcome":"success"`, `"outcome":"failure"`, 1)
+				return lines
+			},
+			wantKind: audit.ProblemHash,
+			wantSeq:  2,
+		},
+		{
+			name:     "Deleted entry",
+			edit:     func(lines []string) []string { return append(lines[:1], lines[2:]...) },
+			wantKind: audit.ProblemGap,
+			wantSeq:  3,
+		},
+		{
+			name: "Reordered entries",
+			edit: func(lines []string) []string {
+				lines[1], lines[2] = lines[2], lines[1]
+				return lines
+			},
+			wantKind: audit.ProblemGap,
+			wantSeq:  3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeTrail(t, 4)
+			rewriteLines(t, path, tc.edit)
+
+			report := verifyFile(t, path)
+			if report.Valid() {
+				t.Fatal("Expected tampering to be detected")
+			}
+			if p := report.Problems[0]; p.Kind != tc.wantKind || p.Seq != tc.wantSeq {
+				t.Errorf("Expected first problem %s at seq %d, got %v", tc.wantKind, tc.wantSeq, report.Problems)
+			}
+		})
+	}
+}
+
+func TestVerify_DetectsRehashWithoutKey(t *testing.T) {
+	// Whoever edits the store without the HMAC key cannot produce valid hashes,
+	// even when re-hashing the entry and everything after it.
+	path := writeTrail(t, 3)
+	store, err := audit.OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open file store: %v", err)
+	}
+	forged := &memStore{}
+	forgerKey := []byte(strings.Repeat("f", audit.MinKeyLength))
+	prevHash := ""
+	store.Iterate(context.Background(), func(e audit.Entry) error {
+		if e.Seq == 2 {
+			e.Outcome = audit.OutcomeFailure
+		}
+		e.PrevHash = prevHash
+		e.Hash = e.ComputeHash(forgerKey)
+		prevHash = e.Hash
+		return forged.Append(context.Background(), e)
+	})
+	store.Close(context.Background())
+
+	if report, _ := audit.Verify(context.Background(), forged, forgerKey); !report.Valid() {
+		t.Fatalf("Expected the forged chain to be self-consistent, got %v", report.Problems)
+	}
+	report, err := audit.Verify(context.Background(), forged, testKey)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if len(report.Problems) != 3 || report.Problems[0].Kind != audit.ProblemHash {
+		t.Errorf("Expected every re-hashed entry to fail verification, got %v", report.Problems)
+	}
+}
+
+func TestNew_RejectsWeakKey(t *testing.T) {
+	if _, err := audit.New(context.Background(), &memStore{}, []byte("short"), discardLogger()); !errors.Is(err, audit.ErrWeakKey) {
+		t.Errorf("Expected ErrWeakKey, got %v", err)
+	}
+	if _, err := audit.Verify(context.Background(), &memStore{}, nil); !errors.Is(err, audit.ErrWeakKey) {
+		t.Errorf("Expected ErrWeakKey from Verify, got %v", err)
+	}
+}
+
+func TestVerify_DetectsRewrittenChain(t *testing.T) {
+	// A forged entry with a correct hash over fabricated content still breaks the link to its predecessor.
+	path := writeTrail(t, 2)
+	store := &memStore{}
+	log, _ := audit.New(context.Background(), store, testKey, discardLogger())
+	log.Record(context.Background(), testActor, "user.delete", audit.Resource{Type: "user", ID: "u-7"}, audit.OutcomeSuccess)
+	log.Record(context.Background(), testActor, "user.delete", audit.Resource{Type: "user", ID: "u-8"}, audit.OutcomeSuccess)
+
+	rewriteLines(t, path, func(lines []string) []string {
+		forged := strings.Split(strings.TrimSpace(store.jsonLines(t)), "\n")
+		return []string{lines[0], forged[1]}
+	})
+
+	report := verifyFile(t, path)
+	if report.Valid() || report.Problems[0].Kind != audit.ProblemChain {
+		t.Errorf("Expected a chain problem, got %+v", report.Problems)
+	}
+}
+
+func TestOpenFileStore_RejectsCorruptTrail(t *testing.T) {
+	path := writeTrail(t, 1)
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	f.WriteString(`{"seq":2,"action":`)
+	f.Close()
+
+	if _, err := audit.OpenFileStore(path); !errors.Is(err, audit.ErrCorrupt) {
+		t.Errorf("Expected ErrCorrupt for a torn trailing line, got %v", err)
+	}
+}
+
+func TestLogger_ConcurrentRecordsStayGapless(t *testing.T) {
+	store := &memStore{}
+	log, _ := audit.New(context.Background(), store, testKey, discardLogger())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := log.Record(context.Background(), testActor, "course.update", testResource, audit.OutcomeSuccess); err != nil {
+				t.Errorf("Record failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	report, err := audit.Verify(context.Background(), store, testKey)
+	if err != nil || !report.Valid() || report.HeadSeq != 50 {
+		t.Errorf("Expected a valid 50-entry chain, got %+v (err %v)", report, err)
+	}
+}
+
+func TestLogger_RetriesAfterConcurrentWriter(t *testing.T) {
+	store := &memStore{}
+	first, _ := audit.New(context.Background(), store, testKey, discardLogger())
+	second, _ := audit.New(context.Background(), store, testKey, discardLogger())
+
+	// Both instances start from an empty trail; the second must continue after the first.
+	if err := first.Record(context.Background(), testActor, "course.create", testResource, audit.OutcomeSuccess); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := second.Record(context.Background(), testActor, "course.update", testResource, audit.OutcomeSuccess); err != nil {
+		t.Fatalf("Record after conflict failed: %v", err)
+	}
+
+	report, _ := audit.Verify(context.Background(), store, testKey)
+	if !report.Valid() || report.HeadSeq != 2 {
+		t.Errorf("Expected a valid 2-entry chain, got %+v", report)
+	}
+}
+
+func TestFileStore_SharedFileStaysChained(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	open := func() (*audit.FileStore, *audit.Logger) {
+		store, err := audit.OpenFileStore(path)
+		if err != nil {
+			t.Fatalf("Failed to open file store: %v", err)
+		}
+		log, err := audit.New(context.Background(), store, testKey, discardLogger())
+		if err != nil {
+			t.Fatalf("Failed to create audit logger: %v", err)
+		}
+		return store, log
+	}
+
+	// Like the old and new process of a graceful restart, both writers start from the same head.
+	parentStore, parent := open()
+	if err := parent.Record(context.Background(), testActor, "service.start", testResource, audit.OutcomeSuccess); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	stale, _ := parentStore.Last(context.Background())
+	_, child := open()
+	if err := child.Record(context.Background(), testActor, "service.start", testResource, audit.OutcomeSuccess); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	// An entry built from the outdated head must not fork the chain.
+	next := audit.Entry{Seq: stale.Seq + 1, PrevHash: stale.Hash}
+	next.Hash = next.ComputeHash(testKey)
+	if err := parentStore.Append(context.Background(), next); !errors.Is(err, audit.ErrConflict) {
+		t.Fatalf("Expected ErrConflict for a stale head, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, log := range []*audit.Logger{parent, child} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				if err := log.Record(context.Background(), testActor, "course.update", testResource, audit.OutcomeSuccess); err != nil {
+					t.Errorf("Record failed: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	parent.Close(context.Background())
+	child.Close(context.Background())
+
+	report := verifyFile(t, path)
+	if !report.Valid() || report.HeadSeq != 22 {
+		t.Errorf("Expected a valid 22-entry chain, got %+v", report)
+	}
+}
+
+func TestRecord_RequiresDefaultLogger(t *testing.T) {
+	audit.SetDefault(nil)
+	err := audit.Record(context.Background(), testActor, "course.update", testResource, audit.OutcomeSuccess)
+	if !errors.Is(err, audit.ErrNotConfigured) {
+		t.Errorf("Expected ErrNotConfigured, got %v", err)
+	}
+}
+
+func TestRecord_FailsWhenStoreFails(t *testing.T) {
+	store := &memStore{failAppend: errors.New("disk full")}
+	log, _ := audit.New(context.Background(), store, testKey, discardLogger())
+	audit.SetDefault(log)
+	defer audit.SetDefault(nil)
+
+	if err := audit.Record(context.Background(), testActor, "user.delete", testResource, audit.OutcomeSuccess); err == nil {
+		t.Error("Expected Record to fail when the entry cannot be persisted")
+	}
+}
+
+// memStore is an in-memory audit.Store enforcing unique sequence numbers.
+type memStore struct {
+	mu         sync.Mutex
+	entries    []audit.Entry
+	failAppend error
+}
+
+func (s *memStore) Append(_ context.Context, e audit.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failAppend != nil {
+		return s.failAppend
+	}
+	for _, existing := range s.entries {
+		if existing.Seq == e.Seq {
+			return audit.ErrConflict
+		}
+	}
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+func (s *memStore) Last(context.Context) (*audit.Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) == 0 {
+		return nil, nil
+	}
+	last := s.entries[len(s.entries)-1]
+	return &last, nil
+}
+
+func (s *memStore) Iterate(_ context.Context, fn func(audit.Entry) error) error {
+	s.mu.Lock()
+	entries := append([]audit.Entry(nil), s.entries...)
+	s.mu.Unlock()
+	for _, e := range entries {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *memStore) Close(context.Context) error { return nil }
+
+// jsonLines renders the stored entries in the file store format.
+func (s *memStore) jsonLines(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "forged.log")
+	fs, err := audit.OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open file store: %v", err)
+	}
+	for _, e := range s.entries {
+		fs.Append(context.Background(), e)
+	}
+	fs.Close(context.Background())
+	data, _ := os.ReadFile(path)
+	return string(data)
+}
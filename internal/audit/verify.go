@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+)
+
+// ProblemKind classifies an integrity violation found by Verify.
+type ProblemKind string
+
+const (
+	// ProblemGap means one or more sequence numbers are missing (entries deleted).
+	ProblemGap ProblemKind = "gap"
+
+	// ProblemOrder means a sequence number repeats or goes backwards (entries duplicated or reordered).
+	ProblemOrder ProblemKind = "order"
+
+	// ProblemHash means an entry's content no longer matches its hash (entry edited).
+	ProblemHash ProblemKind = "hash"
+
+	// ProblemChain means an entry does not reference the hash of its predecessor
+	// (entries replaced or the chain re-written from this point).
+	ProblemChain ProblemKind = "chain"
+
+	// ProblemCorrupt means stored data could not be decoded at all.
+	ProblemCorrupt ProblemKind = "corrupt"
+)
+
+// Problem is one integrity violation.
+type Problem struct {
+	Seq    uint64      // Sequence number of the offending entry (0 if unknown)
+	Kind   ProblemKind // Violation category
+	Detail string      // Human-readable explanation
+}
+
+// String formats the problem for command output.
+func (p Problem) String() string {
+	return fmt.Sprintf("seq %d: %s: %s", p.Seq, p.Kind, p.Detail)
+}
+
+// Report summarizes an audit trail verification.
+type Report struct {
+	Entries  int       // Number of entries read
+	HeadSeq  uint64    // Sequence number of the last entry
+	HeadHash string    // Hash of the last entry; record it externally to detect truncation
+	Problems []Problem // Integrity violations, in trail order
+}
+
+// Valid reports whether no problems were found.
+func (r Report) Valid() bool {
+	return len(r.Problems) == 0
+}
+
+// Verify walks the whole trail in store and checks that sequence numbers are
+// gapless and increasing, that every entry's hash matches its content under
+// key and that every entry references the hash of its predecessor.
+//
+// Verification continues after a problem so a single report lists every
+// damaged location. The returned error is non-nil only when the key is too
+// short or the store could not be read; integrity violations are reported in
+// Report.Problems.
+func Verify(ctx context.Context, store Store, key []byte) (Report, error) {
+	if len(key) < MinKeyLength {
+		return Report{}, ErrWeakKey
+	}
+	var (
+		report Report
+		prev   *Entry
+	)
+	err := store.Iterate(ctx, func(e Entry) error {
+		report.Entries++
+
+		expectedSeq := uint64(1)
+		if prev != nil {
+			expectedSeq = prev.Seq + 1
+		}
+		switch {
+		case e.Seq > expectedSeq:
+			report.Problems = append(report.Problems, Problem{e.Seq, ProblemGap,
+				fmt.Sprintf("expected seq %d, %d entries missing", expectedSeq, e.Seq-expectedSeq)})
+		case e.Seq < expectedSeq:
+			report.Problems = append(report.Problems, Problem{e.Seq, ProblemOrder,
+				fmt.Sprintf("expected seq %d", expectedSeq)})
+		}
+
+		if hash := e.ComputeHash(key); !hmac.Equal([]byte(hash), []byte(e.Hash)) {
+			report.Problems = append(report.Problems, Problem{e.Seq, ProblemHash,
+				"content does not match stored hash"})
+		}
+
+		expectedPrev := ""
+		if prev != nil {
+			expectedPrev = prev.Hash
+		}
+		if e.PrevHash != expectedPrev && e.Seq == expectedSeq {
+			report.Problems = append(report.Problems, Problem{e.Seq, ProblemChain,
+				"prev_hash does not match the preceding entry"})
+		}
+
+		entry := e
+		prev = &entry
+		return nil
+	})
+	if errors.Is(err, ErrCorrupt) {
+		report.Problems = append(report.Problems, Problem{Kind: ProblemCorrupt, Detail: err.Error()})
+		err = nil
+	}
+	if prev != nil {
+		report.HeadSeq = prev.Seq
+		report.HeadHash = prev.Hash
+	}
+	return report, err
+}
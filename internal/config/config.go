@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -192,7 +193,16 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to apply environment overrides: %w", err)
 	}
 
-	// Step 7: Log the final loaded configuration for debugging and operational visibility
+	// Step 7: Reject combinations of settings that cannot work together
+	// Individual values are not range-checked here; consumers clamp those
+	if err := cfg.Validate(); err != nil {
+		slog.Error("🔠 Invalid configuration",
+			slog.Any("error", err), // Which settings conflict
+		)
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// Step 8: Log the final loaded configuration for debugging and operational visibility
 	// This provides a comprehensive view of the active configuration without exposing secrets
 	// Sensitive values should be logged as "[REDACTED]" or similar
 	slog.Debug("🔠 Configuration successfully loaded and merged from all sources",
@@ -212,6 +222,25 @@ func NewConfig() (*Config, error) {
 	return &cfg, nil
 }
 
+// Validate checks settings that depend on each other.
+//
+// Checks:
+//   - Server.DrainDelay must be shorter than Server.ShutdownTimeout. The drain
+//     delay is spent within the shutdown budget; if it used up the whole
+//     budget, closing the listeners, stopping the workers and closing the
+//     database would be abandoned.
+//
+// Returns:
+//   - error: Describes every conflict found, nil if there is none
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Server.DrainDelay > 0 && c.Server.DrainDelay >= c.Server.ShutdownTimeout {
+		errs = append(errs, fmt.Errorf("server.drain_delay (%ds) must be shorter than server.shutdown_timeout (%ds)",
+			c.Server.DrainDelay, c.Server.ShutdownTimeout))
+	}
+	return errors.Join(errs...)
+}
+
 // NewConfig loads the application configuration from JSON files and environment variables.
 //
 // This function merges base, environment-specific, and local config files, then overrides
@@ -133,6 +133,143 @@ func NewDefaultConfig(logger slog.Logger) *Config {
 			// This allows in-flight requests to complete while not delaying deployments too long.
 			// Can be increased for applications with longer-running request processing.
 			ShutdownTimeout: 15,
+
+			// TLS: Disabled so development works over plain HTTP and production can
+			// terminate TLS at a load balancer. When enabled, TLS 1.2 is the floor
+			// and certificate files are checked for renewal every 10 seconds.
+			TLS: ServerTLS{
+				MinVersion:     "1.2",
+				CipherPolicy:   "default",
+				ClientAuth:     "require",
+				ReloadInterval: 10,
+			},
+
+			// AdminHost: The admin listener (disabled until AdminPort is set) only
+			// accepts local connections unless explicitly exposed.
+			AdminHost: "localhost",
+
+			// CORS: No origins are allowed until configured. The method, header and
+			// max-age defaults suit a typical single-page application front-end.
+			CORS: ServerCORS{
+				CORSPolicy: CORSPolicy{
+					AllowedMethods: []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"},
+					AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "Idempotency-Key", "X-Request-ID"},
+					MaxAge:         600,
+				},
+			},
+
+			// RateLimit: Off until enabled; when enabled, each client IP may send
+			// 300 requests per minute from an in-memory store. Probes and metrics
+			// are exempt.
+			RateLimit: ServerRateLimit{
+				Store:      "memory",
+				Collection: "rate_limits",
+				RateLimitPolicy: RateLimitPolicy{
+					Requests: 300,
+					Window:   60,
+				},
+				ExemptPaths: []string{"/health", "/ready", "/metrics"},
+			},
+
+			// TrustedProxies: None; the service is reachable directly, so
+			// forwarding headers are ignored and the peer address is the client IP.
+			TrustedProxies: []string{},
+
+			// Compression: Negotiate zstd or gzip for text and JSON bodies of 1 KiB
+			// and more, at levels that favor speed over ratio.
+			Compression: ServerCompression{
+				Enabled:    true,
+				Algorithms: []string{"zstd", "gzip"},
+				MinSize:    1024,
+				GzipLevel:  5,
+				ZstdLevel:  3,
+				ContentTypes: []string{
+					"application/json",
+					"application/problem+json",
+					"application/javascript",
+					"application/xml",
+					"image/svg+xml",
+					"text/",
+				},
+			},
+
+			// ReadHeaderTimeout, IdleTimeout, MaxHeaderBytes: Close slow or idle
+			// connections promptly and keep header memory per request bounded.
+			ReadHeaderTimeout: 10,
+			IdleTimeout:       120,
+			MaxHeaderBytes:    1 << 20,
+
+			// Limits: 1 MiB bodies suit JSON APIs; uploads need a group override.
+			// Handlers get 25 seconds, leaving room to answer within WriteTimeout.
+			Limits: ServerLimits{
+				RouteLimits: RouteLimits{
+					MaxBodyBytes:   1 << 20,
+					HandlerTimeout: 25,
+				},
+			},
+
+			// SocketMode: Unix sockets are reachable by the owner and group only.
+			// SocketActivation: Only takes effect when systemd passes LISTEN_FDS.
+			SocketMode:       "0660",
+			SocketActivation: true,
+
+			// Restart: Opt-in, since the main PID changes with every restart.
+			// The new process gets 30 seconds to connect to MongoDB and bind.
+			Restart: ServerRestart{
+				Enabled: false,
+				Timeout: 30,
+			},
+
+			// DrainDelay: 5 seconds covers a readiness probe every 2 seconds
+			// failing twice, leaving 10 of the 15 shutdown seconds for requests.
+			DrainDelay: 5,
+
+			// Security: A strict policy suited to a JSON API; inline scripts and
+			// styles of HTML pages need the per-request nonce. HSTS is sent only
+			// over TLS, for a year, so browsers never downgrade to plain HTTP.
+			Security: ServerSecurity{
+				Enabled:               true,
+				CSP:                   "default-src 'none'; script-src 'self' 'nonce-{nonce}'; style-src 'self' 'nonce-{nonce}'; img-src 'self' data:; connect-src 'self'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'",
+				CSPReportOnly:         false,
+				CSPReportPath:         "/csp-report",
+				HSTSMaxAge:            31536000,
+				HSTSIncludeSubdomains: true,
+				HSTSPreload:           false,
+				ReferrerPolicy:        "no-referrer",
+				PermissionsPolicy:     "camera=(), microphone=(), geolocation=(), payment=()",
+				FrameOptions:          "DENY",
+			},
+
+			// API: Versions are selected by path; header negotiation is opt-in
+			// so unversioned paths do not silently change meaning.
+			API: ServerAPI{
+				Negotiation:    "path",
+				DefaultVersion: "v1",
+			},
+
+			// Idempotency: Keys are remembered for a day, long enough for client
+			// retry schedules; a crashed request frees its key after a minute.
+			Idempotency: ServerIdempotency{
+				Enabled:          true,
+				Store:            "memory",
+				Collection:       "idempotency_keys",
+				TTL:              86400,
+				LockTimeout:      60,
+				MaxResponseBytes: 1 << 20,
+				Methods:          []string{"POST", "PATCH"},
+			},
+
+			// LoadShedding: Opt-in. Starts at 100 concurrent requests and adapts
+			// between 10 and 1000, keeping the smoothed latency under 500 ms.
+			LoadShedding: ServerLoadShedding{
+				Enabled:       false,
+				InitialLimit:  100,
+				MinLimit:      10,
+				MaxLimit:      1000,
+				LatencyTarget: 500,
+				RetryAfter:    1,
+				ExemptPaths:   []string{"/health", "/ready", "/metrics"},
+			},
 		},
 
 		// Database: Configure database connection settings with secure defaults.
@@ -161,5 +298,14 @@ func NewDefaultConfig(logger slog.Logger) *Config {
 			// test scenarios provide explicit configuration values rather than
 			// relying on potentially inappropriate defaults.
 		},
+
+		// Audit: Store the audit trail in a local append-only file so development
+		// setups work without extra configuration. Multi-instance deployments
+		// should switch to the "mongodb" backend.
+		Audit: Audit{
+			Backend:    "file",
+			FilePath:   "audit/audit.log",
+			Collection: "audit_log",
+		},
 	}
 }
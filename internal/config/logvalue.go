@@ -0,0 +1,58 @@
+package config
+
+import (
+	"log/slog"
+	"sort"
+)
+
+// maskedValue replaces secrets in log output. It matches the literal used by
+// the logger's redaction and the database package, so all sources look alike.
+const maskedValue = "***MASKED***"
+
+// LogValue implements slog.LogValuer so a database configuration can be
+// logged without revealing the password. An unset password is shown as empty.
+func (d Database) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("host", d.Host),
+		slog.Int("port", d.Port),
+		slog.String("user", d.User),
+		slog.String("password", mask(d.Password)),
+		slog.String("name", d.Name),
+		slog.Bool("is_atlas", d.IsAtlas),
+		slog.String("atlas_app_name", d.AtlasAppName),
+	)
+}
+
+// LogValue implements slog.LogValuer so a sink configuration can be logged
+// without revealing its authentication headers. Header names are kept, since
+// they tell which credential is configured; their values are masked.
+func (s LogSink) LogValue() slog.Value {
+	names := make([]string, 0, len(s.Headers))
+	for name := range s.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	headers := make([]slog.Attr, len(names))
+	for i, name := range names {
+		headers[i] = slog.String(name, mask(s.Headers[name]))
+	}
+
+	return slog.GroupValue(
+		slog.String("type", s.Type),
+		slog.String("address", s.Address),
+		slog.String("level", s.Level),
+		slog.String("facility", s.Facility),
+		slog.String("app_name", s.AppName),
+		slog.Attr{Key: "headers", Value: slog.GroupValue(headers...)},
+		slog.Int("batch_size", s.BatchSize),
+		slog.Int("buffer_size", s.BufferSize),
+	)
+}
+
+// mask returns maskedValue for a set secret and "" for an unset one.
+func mask(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return maskedValue
+}
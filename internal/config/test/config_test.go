@@ -63,3 +63,28 @@ func TestOverrideFromEnv_OverridesStringAndBool(t *testing.T) {
 		t.Errorf("Expected Logger.AddSource to be overridden to false, got %v", cfg.Logger.AddSource)
 	}
 }
+
+// TestValidate_DrainDelay tests that a drain delay must leave time for the other shutdown phases.
+func TestValidate_DrainDelay(t *testing.T) {
+	tests := []struct {
+		name            string
+		drainDelay      int
+		shutdownTimeout int
+		wantErr         bool
+	}{
+		{"shorter than timeout", 5, 30, false},
+		{"disabled", 0, 0, false},
+		{"equal to timeout", 30, 30, true},
+		{"longer than timeout", 60, 30, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg config.Config
+			cfg.Server.DrainDelay = tt.drainDelay
+			cfg.Server.ShutdownTimeout = tt.shutdownTimeout
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
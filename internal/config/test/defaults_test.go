@@ -41,4 +41,45 @@ func TestNewDefaultConfig_Values(t *testing.T) {
 	if cfg.Server.ShutdownTimeout != 15 {
 		t.Errorf("Expected default shutdown timeout 15, got %d", cfg.Server.ShutdownTimeout)
 	}
+	if cfg.Server.TLS.Enabled || cfg.Server.TLS.MinVersion != "1.2" {
+		t.Errorf("Expected TLS disabled with min version 1.2 by default, got %+v", cfg.Server.TLS)
+	}
+	if rl := cfg.Server.RateLimit; rl.Enabled || rl.Store != "memory" || len(rl.ExemptPaths) != 3 {
+		t.Errorf("Expected rate limiting disabled with memory store and exempt probes by default, got %+v", rl)
+	}
+	if comp := cfg.Server.Compression; !comp.Enabled || comp.MinSize != 1024 || len(comp.Algorithms) != 2 || comp.Algorithms[0] != "zstd" {
+		t.Errorf("Expected zstd/gzip compression from 1024 bytes by default, got %+v", comp)
+	}
+	if cfg.Server.ReadHeaderTimeout != 10 || cfg.Server.IdleTimeout != 120 || cfg.Server.MaxHeaderBytes != 1<<20 {
+		t.Errorf("Expected header timeout 10, idle timeout 120 and 1 MiB headers by default, got %d/%d/%d",
+			cfg.Server.ReadHeaderTimeout, cfg.Server.IdleTimeout, cfg.Server.MaxHeaderBytes)
+	}
+	if limits := cfg.Server.Limits; limits.MaxBodyBytes != 1<<20 || limits.HandlerTimeout != 25 {
+		t.Errorf("Expected 1 MiB bodies and a 25 second handler timeout by default, got %+v", limits)
+	}
+	if cfg.Server.Address != "" || cfg.Server.SocketMode != "0660" || !cfg.Server.SocketActivation {
+		t.Errorf("Expected Host:Port, 0660 sockets and socket activation by default, got %q/%q/%t",
+			cfg.Server.Address, cfg.Server.SocketMode, cfg.Server.SocketActivation)
+	}
+	if r := cfg.Server.Restart; r.Enabled || r.Timeout != 30 {
+		t.Errorf("Expected graceful restarts disabled with a 30 second timeout by default, got %+v", r)
+	}
+	if cfg.Server.DrainDelay != 5 {
+		t.Errorf("Expected a 5 second drain delay by default, got %d", cfg.Server.DrainDelay)
+	}
+	if sec := cfg.Server.Security; !sec.Enabled || sec.CSPReportOnly || sec.CSPReportPath != "/csp-report" || sec.HSTSMaxAge != 31536000 || sec.FrameOptions != "DENY" {
+		t.Errorf("Expected enforced CSP with reporting, one year HSTS and DENY framing by default, got %+v", sec)
+	}
+	if api := cfg.Server.API; api.Negotiation != "path" || api.DefaultVersion != "v1" {
+		t.Errorf("Expected path version negotiation defaulting to v1, got %+v", api)
+	}
+	if idem := cfg.Server.Idempotency; !idem.Enabled || idem.Store != "memory" || idem.TTL != 86400 || idem.LockTimeout != 60 || len(idem.Methods) != 2 {
+		t.Errorf("Expected in-memory idempotency keys for a day on POST and PATCH by default, got %+v", idem)
+	}
+	if len(cfg.Server.TrustedProxies) != 0 {
+		t.Errorf("Expected no trusted proxies by default, got %v", cfg.Server.TrustedProxies)
+	}
+	if ls := cfg.Server.LoadShedding; ls.Enabled || ls.InitialLimit != 100 || ls.LatencyTarget != 500 || len(ls.ExemptPaths) != 3 {
+		t.Errorf("Expected load shedding off with a 500 ms latency target by default, got %+v", ls)
+	}
 }
@@ -0,0 +1,41 @@
+package config_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+)
+
+// TestLogValue_MasksSecrets tests that database and sink configurations log without their secrets.
+func TestLogValue_MasksSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	log.Info("configured",
+		slog.Any("database", config.Database{Host: "localhost", Port: 27017, User: "app", Password: "hunter2"}),
+		slog.Any("sink", config.LogSink{Type: "otlp", Address: "https://otel.example.com", Headers: map[string]string{"Authorization": "Bearer abc"}}),
+	)
+
+	out := buf.String()
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "Bearer abc") {
+		t.Fatalf("Expected secrets to be masked, got %s", out)
+	}
+	for _, want := range []string{`"host":"localhost"`, `"password":"***MASKED***"`, `"Authorization":"***MASKED***"`, `"address":"https://otel.example.com"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %s in %s", want, out)
+		}
+	}
+}
+
+// TestLogValue_UnsetSecret tests that an unset password is shown as empty rather than masked.
+func TestLogValue_UnsetSecret(t *testing.T) {
+	db := config.Database{Host: "localhost"}
+	for _, a := range db.LogValue().Group() {
+		if a.Key == "password" && a.Value.String() != "" {
+			t.Errorf("Expected an empty password, got %q", a.Value.String())
+		}
+	}
+}
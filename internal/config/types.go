@@ -71,7 +71,7 @@ type Config struct {
 	// format for development environments.
 	//
 	// Key features:
-	// - Configurable log levels (debug, info, warn, error)
+	// - Configurable log levels (trace, debug, info, warn, error, fatal)
 	// - Multiple output formats (JSON for production, text for development)
 	// - Flexible output destinations (stdout, stderr, files)
 	// - Source code location tracking for debugging
@@ -109,6 +109,13 @@ type Config struct {
 	// Test configuration is only loaded and used when Environment is set to "test"
 	// or when running automated test suites.
 	Test Test `json:"test" yaml:"test" env:"TEST"`
+
+	// Audit contains the tamper-evident audit trail configuration for
+	// administrative actions (course and user management).
+	//
+	// The audit trail is kept separate from operational logs: entries are
+	// hash-chained, written synchronously and never rotated or sampled.
+	Audit Audit `json:"audit" yaml:"audit" env:"AUDIT"`
 }
 
 // Logger defines the complete logging system configuration for structured and efficient
@@ -150,10 +157,12 @@ type Logger struct {
 	// which log messages are processed and which are discarded.
 	//
 	// Supported levels (in order of increasing severity):
+	// - "trace": Wire-level detail below debug (e.g. individual MongoDB commands)
 	// - "debug": Detailed debugging information, typically only enabled during development
 	// - "info": General informational messages about application operation
 	// - "warn": Warning messages about potentially problematic situations
 	// - "error": Error messages about failures that don't stop the application
+	// - "fatal": Only unrecoverable errors logged immediately before the process exits
 	//
 	// Level Selection Guidelines:
 	// - Development: "debug" for maximum visibility into application behavior
@@ -244,6 +253,228 @@ type Logger struct {
 	// Environment variable: SLOG_ADD_SOURCE
 	// Default: false (performance-first approach)
 	AddSource bool `json:"add_source" yaml:"add_source" env:"SLOG_ADD_SOURCE"`
+
+	// Redaction controls masking of sensitive attributes (passwords, tokens,
+	// credentials in connection strings, e-mail addresses) before log records
+	// reach any output. Redaction is enabled unless explicitly disabled.
+	//
+	// See LoggerRedaction for the individual settings.
+	Redaction LoggerRedaction `json:"redaction" yaml:"redaction"`
+
+	// Sinks lists remote log collectors that receive a copy of every log record
+	// in addition to the local Output. Each sink batches records in a bounded
+	// in-memory buffer and ships them asynchronously, so a slow or unavailable
+	// collector never blocks request handling.
+	//
+	// Supported sink types:
+	// - "syslog": RFC 5424 over UDP, TCP or a unix socket (rsyslog, syslog-ng)
+	// - "gelf": GELF 1.1 over UDP (Graylog)
+	// - "otlp": OTLP/HTTP JSON log export (OpenTelemetry collector)
+	//
+	// Default: empty (local output only)
+	Sinks []LogSink `json:"sinks" yaml:"sinks"`
+
+	// Async moves writes to the local Output off the request path through a
+	// bounded in-memory queue. See LoggerAsync for the overflow behavior.
+	Async LoggerAsync `json:"async" yaml:"async"`
+
+	// Pretty controls colors, theme and layout of the "pretty" console format.
+	// It has no effect on the json and text formats.
+	Pretty LoggerPretty `json:"pretty" yaml:"pretty"`
+}
+
+// LoggerPretty configures the human-friendly console output used when
+// Logger.Format is "pretty".
+//
+// Colors are emitted only when stdout is a terminal unless overridden here or
+// by the standard NO_COLOR / FORCE_COLOR environment variables, so piping the
+// output to a file or another program never produces escape sequences.
+//
+// Example configuration:
+//
+//	"pretty": {"color": "auto", "theme": "light", "layout": "compact"}
+type LoggerPretty struct {
+	// Color selects when ANSI colors are used:
+	// - "auto": Only on terminals; NO_COLOR disables and FORCE_COLOR enables (default)
+	// - "always": Always, e.g. for CI systems that render ANSI sequences
+	// - "never": Never
+	//
+	// Environment variable: SLOG_PRETTY_COLOR
+	// Default: "auto"
+	Color string `json:"color" yaml:"color" env:"SLOG_PRETTY_COLOR"`
+
+	// Theme selects the color palette: "default" (dark backgrounds), "light",
+	// "high-contrast" or "mono". Unknown names fall back to "default".
+	//
+	// Environment variable: SLOG_PRETTY_THEME
+	// Default: "default"
+	Theme string `json:"theme" yaml:"theme" env:"SLOG_PRETTY_THEME"`
+
+	// Layout selects how attributes are arranged:
+	// - "expanded": One attribute per line with aligned columns and indented
+	//   blocks for groups, structs, errors and stack traces
+	// - "compact": The whole record on one line
+	// - "auto": Compact on terminals narrower than CompactWidth, expanded otherwise (default)
+	//
+	// Environment variable: SLOG_PRETTY_LAYOUT
+	// Default: "auto"
+	Layout string `json:"layout" yaml:"layout" env:"SLOG_PRETTY_LAYOUT"`
+
+	// CompactWidth is the terminal width in columns below which the "auto"
+	// layout switches to compact output.
+	//
+	// Environment variable: SLOG_PRETTY_COMPACT_WIDTH
+	// Default: 100
+	CompactWidth int `json:"compact_width" yaml:"compact_width" env:"SLOG_PRETTY_COMPACT_WIDTH"`
+}
+
+// LoggerAsync configures asynchronous buffering of the local log output.
+//
+// When enabled, log calls only enqueue the record; a background goroutine
+// performs the actual write. This keeps slow terminals, pipes or container log
+// drivers from adding latency to request handling. The queue is drained during
+// graceful shutdown so no buffered record is lost on a clean exit.
+//
+// Example configuration:
+//
+//	"async": {"enabled": true, "buffer_size": 8192, "overflow_policy": "drop_debug_first"}
+type LoggerAsync struct {
+	// Enabled turns asynchronous local output on.
+	//
+	// Environment variable: SLOG_ASYNC_ENABLED
+	// Default: false (synchronous writes)
+	Enabled bool `json:"enabled" yaml:"enabled" env:"SLOG_ASYNC_ENABLED"`
+
+	// BufferSize is the maximum number of records waiting to be written.
+	//
+	// Environment variable: SLOG_ASYNC_BUFFER_SIZE
+	// Default: 4096
+	BufferSize int `json:"buffer_size" yaml:"buffer_size" env:"SLOG_ASYNC_BUFFER_SIZE"`
+
+	// OverflowPolicy decides what happens when the queue is full:
+	// - "block": The logging call waits for space (lossless, applies backpressure)
+	// - "drop_newest": The incoming record is discarded
+	// - "drop_debug_first": Debug records are discarded first, then the incoming record
+	//
+	// Environment variable: SLOG_ASYNC_OVERFLOW_POLICY
+	// Default: "block"
+	OverflowPolicy string `json:"overflow_policy" yaml:"overflow_policy" env:"SLOG_ASYNC_OVERFLOW_POLICY"`
+}
+
+// LogSink configures one remote log shipping destination.
+//
+// Example configuration:
+//
+//	"sinks": [
+//	    {"type": "gelf", "address": "graylog.internal:12201", "level": "info"},
+//	    {"type": "otlp", "address": "http://otel-collector:4318/v1/logs",
+//	     "headers": {"Authorization": "Bearer ..."}},
+//	    {"type": "syslog", "address": "unix:///dev/log", "facility": "local3"}
+//	]
+//
+// Delivery Semantics:
+// Records are buffered (up to BufferSize) and sent in batches of BatchSize or
+// every FlushIntervalMs, whichever comes first. Failed batches are retried
+// MaxRetries times with exponential backoff starting at RetryBackoffMs. Records
+// that overflow the buffer or exhaust their retries are dropped and counted.
+type LogSink struct {
+	// Type selects the protocol: "syslog", "gelf" or "otlp".
+	Type string `json:"type" yaml:"type"`
+
+	// Address is the collector location. Its form depends on Type:
+	// - syslog: "udp://host:514", "tcp://host:601", "unix:///dev/log", "unixstream:///path"
+	// - gelf: "host:12201" (UDP)
+	// - otlp: "http://host:4318/v1/logs" or "https://..."
+	Address string `json:"address" yaml:"address"`
+
+	// Level is the minimum level shipped to this sink. Empty inherits Logger.Level.
+	Level string `json:"level" yaml:"level"`
+
+	// Facility is the syslog facility name (e.g. "local0", "daemon"). Syslog only.
+	// Default: "local0"
+	Facility string `json:"facility" yaml:"facility"`
+
+	// AppName identifies the application to the collector (syslog APP-NAME,
+	// GELF "_app" field, OTLP service.name resource attribute).
+	// Default: "goedu-theta"
+	AppName string `json:"app_name" yaml:"app_name"`
+
+	// Headers are extra HTTP headers sent with each OTLP export, e.g. for authentication.
+	Headers map[string]string `json:"headers" yaml:"headers"`
+
+	// BatchSize is the maximum number of records per delivery. Default: 100
+	BatchSize int `json:"batch_size" yaml:"batch_size"`
+
+	// BufferSize is the maximum number of records waiting for delivery before
+	// new records are dropped. Default: 10000
+	BufferSize int `json:"buffer_size" yaml:"buffer_size"`
+
+	// FlushIntervalMs is the maximum time a partial batch waits before delivery.
+	// Default: 2000 milliseconds
+	FlushIntervalMs int `json:"flush_interval_ms" yaml:"flush_interval_ms"`
+
+	// MaxRetries is the number of retries after a failed delivery; negative disables retries.
+	// Default: 3
+	MaxRetries int `json:"max_retries" yaml:"max_retries"`
+
+	// RetryBackoffMs is the delay before the first retry, doubled for each further attempt.
+	// Default: 200 milliseconds
+	RetryBackoffMs int `json:"retry_backoff_ms" yaml:"retry_backoff_ms"`
+
+	// TimeoutMs bounds each connection attempt and delivery. Default: 5000 milliseconds
+	TimeoutMs int `json:"timeout_ms" yaml:"timeout_ms"`
+}
+
+// LoggerRedaction configures the redacting handler that the logger package wraps
+// around every handler built by logger.ConfigureLogger.
+//
+// Redaction is opt-out: the zero value enables masking with the built-in key
+// patterns and value matchers, so forgetting this section never leaks secrets.
+//
+// Example configuration:
+//
+//	"redaction": {
+//	    "mode": "hash",
+//	    "keys": ["*password*", "*token", "student_ssn"],
+//	    "matchers": ["jwt", "mongo_uri"]
+//	}
+type LoggerRedaction struct {
+	// Disabled turns redaction off entirely. Intended only for local debugging.
+	//
+	// Environment variable: SLOG_REDACTION_DISABLED
+	// Default: false (redaction enabled)
+	Disabled bool `json:"disabled" yaml:"disabled" env:"SLOG_REDACTION_DISABLED"`
+
+	// Mode selects how sensitive values are rewritten:
+	// - "mask": Replace with the literal ***MASKED*** (default)
+	// - "hash": Replace with a truncated SHA-256 digest so equal values can be correlated
+	//
+	// Environment variable: SLOG_REDACTION_MODE
+	// Default: "mask"
+	Mode string `json:"mode" yaml:"mode" env:"SLOG_REDACTION_MODE"`
+
+	// HashSalt is prepended to values before hashing in "hash" mode. Set it from
+	// the environment in production so digests of low-entropy values such as
+	// e-mail addresses cannot be reversed with a dictionary.
+	//
+	// Environment variable: SLOG_REDACTION_HASH_SALT
+	// Default: "" (unsalted)
+	HashSalt string `json:"hash_salt" yaml:"hash_salt" env:"SLOG_REDACTION_HASH_SALT"`
+
+	// Keys lists case-insensitive glob patterns (path.Match syntax) for attribute
+	// keys whose values are always redacted. Patterns are matched against both the
+	// bare key and its dotted group path, e.g. "database.password".
+	//
+	// Default: empty (the logger package's built-in list of credential-like keys)
+	Keys []string `json:"keys" yaml:"keys"`
+
+	// Matchers names the value matchers applied to string values regardless of key:
+	// - "jwt": JSON Web Tokens
+	// - "mongo_uri": Passwords embedded in mongodb:// and mongodb+srv:// URIs
+	// - "email": E-mail addresses
+	//
+	// Default: empty (all built-in matchers)
+	Matchers []string `json:"matchers" yaml:"matchers"`
 }
 
 // Server defines the complete HTTP server configuration for the GoEdu-Theta web application.
@@ -473,6 +704,777 @@ type Server struct {
 	// Default: 30 seconds (balanced approach for most applications)
 	// Unit: seconds
 	ShutdownTimeout int `json:"shutdown_timeout" yaml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+
+	// TLS configures HTTPS termination within the application, including
+	// HTTP/2, mutual TLS and an optional plain-HTTP redirect listener.
+	// Disabled by default for deployments behind a TLS-terminating proxy.
+	TLS ServerTLS `json:"tls" yaml:"tls"`
+
+	// AdminPort starts a separate administrative listener hosting health,
+	// metrics, pprof profiling, runtime log level and configuration dump
+	// endpoints, kept off the public API port.
+	//
+	// Environment variable: SERVER_ADMIN_PORT
+	// Default: 0 (admin listener disabled)
+	AdminPort int `json:"admin_port" yaml:"admin_port" env:"SERVER_ADMIN_PORT"`
+
+	// AdminHost is the interface the admin listener binds to. Keep it on
+	// loopback or a private network; profiling endpoints expose internals.
+	//
+	// Environment variable: SERVER_ADMIN_HOST
+	// Default: "localhost"
+	AdminHost string `json:"admin_host" yaml:"admin_host" env:"SERVER_ADMIN_HOST"`
+
+	// AdminToken, when set, must be sent as "Authorization: Bearer <token>"
+	// on every admin request except /health.
+	//
+	// Environment variable: SERVER_ADMIN_TOKEN
+	// Default: "" (no authentication; rely on AdminHost for access control)
+	AdminToken string `json:"admin_token" yaml:"admin_token" env:"SERVER_ADMIN_TOKEN"`
+
+	// CORS configures cross-origin access for browser front-ends.
+	// Disabled while AllowedOrigins is empty.
+	CORS ServerCORS `json:"cors" yaml:"cors"`
+
+	// RateLimit configures per-client request rate limiting of the public API.
+	// Disabled by default.
+	RateLimit ServerRateLimit `json:"rate_limit" yaml:"rate_limit"`
+
+	// TrustedProxies lists the addresses or CIDR ranges of reverse proxies
+	// whose X-Forwarded-For and X-Real-IP headers are believed when
+	// determining the client IP for rate limiting and logging. Headers from
+	// any other peer are ignored, so clients cannot pick their own identity.
+	//
+	// Default: [] (no proxy is trusted; the client IP is the peer address)
+	// Example: ["10.0.0.0/8", "192.168.1.10"]
+	TrustedProxies []string `json:"trusted_proxies" yaml:"trusted_proxies"`
+
+	// Compression configures negotiated gzip/zstd compression of responses.
+	Compression ServerCompression `json:"compression" yaml:"compression"`
+
+	// ReadHeaderTimeout is the time allowed to read request headers. Unlike
+	// ReadTimeout it does not cover the body, so it stops slow-header
+	// (Slowloris) clients without limiting large uploads.
+	//
+	// Environment variable: SERVER_READ_HEADER_TIMEOUT
+	// Default: 10 seconds
+	// Unit: seconds
+	ReadHeaderTimeout int `json:"read_header_timeout" yaml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT"`
+
+	// IdleTimeout is how long a keep-alive connection may wait for the next
+	// request before it is closed. 0 uses ReadTimeout.
+	//
+	// Environment variable: SERVER_IDLE_TIMEOUT
+	// Default: 120 seconds
+	// Unit: seconds
+	IdleTimeout int `json:"idle_timeout" yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+
+	// MaxHeaderBytes caps the size of request headers, including the request
+	// line. Larger requests are rejected with 431.
+	//
+	// Environment variable: SERVER_MAX_HEADER_BYTES
+	// Default: 1048576 (1 MiB)
+	// Unit: bytes
+	MaxHeaderBytes int `json:"max_header_bytes" yaml:"max_header_bytes" env:"SERVER_MAX_HEADER_BYTES"`
+
+	// Limits caps request bodies and handler run time, with per-route-group overrides.
+	Limits ServerLimits `json:"limits" yaml:"limits"`
+
+	// Address replaces Host and Port as the address of the primary listener.
+	// Accepted forms:
+	// - "unix:///run/goedu.sock": Unix domain socket at an absolute path
+	// - "tcp://0.0.0.0:8080" or "0.0.0.0:8080": TCP address
+	//
+	// Environment variable: SERVER_ADDRESS
+	// Default: "" (listen on Host:Port)
+	Address string `json:"address" yaml:"address" env:"SERVER_ADDRESS"`
+
+	// SocketMode is the octal file mode applied to Unix domain sockets
+	// created by the server. A reverse proxy needs write permission to connect.
+	//
+	// Environment variable: SERVER_SOCKET_MODE
+	// Default: "0660" (owner and group may connect)
+	SocketMode string `json:"socket_mode" yaml:"socket_mode" env:"SERVER_SOCKET_MODE"`
+
+	// SocketGroup is the group name or numeric GID given ownership of Unix
+	// domain sockets, e.g. the group the reverse proxy runs as. The server
+	// process must be a member of it.
+	//
+	// Environment variable: SERVER_SOCKET_GROUP
+	// Default: "" (the process's primary group)
+	SocketGroup string `json:"socket_group" yaml:"socket_group" env:"SERVER_SOCKET_GROUP"`
+
+	// Listeners are additional addresses served by the same router and
+	// middleware as the primary listener, e.g. a Unix socket for a local
+	// reverse proxy next to a TCP port for health checks.
+	// Default: [] (primary listener only)
+	Listeners []ServerListener `json:"listeners" yaml:"listeners"`
+
+	// SocketActivation uses listening sockets passed by systemd (LISTEN_FDS)
+	// instead of binding Address, Host:Port and Listeners. Sockets named
+	// "admin" (FileDescriptorName=admin in the .socket unit) serve the admin
+	// router; all others serve the public API. Without LISTEN_FDS in the
+	// environment the server binds its configured addresses as usual.
+	//
+	// Environment variable: SERVER_SOCKET_ACTIVATION
+	// Default: true
+	SocketActivation bool `json:"socket_activation" yaml:"socket_activation" env:"SERVER_SOCKET_ACTIVATION"`
+
+	// Restart configures zero-downtime restarts on SIGUSR2. Disabled by default.
+	Restart ServerRestart `json:"restart" yaml:"restart"`
+
+	// DrainDelay is how long the server keeps serving after SIGTERM or SIGINT
+	// while /ready reports 503 and keep-alives are disabled, so load balancers
+	// stop routing to the instance before it closes its listeners. Set it
+	// above the load balancer's readiness probe interval times its failure
+	// threshold. The delay counts towards ShutdownTimeout and must be shorter
+	// than it, leaving time for the remaining shutdown phases; the
+	// configuration is rejected otherwise.
+	//
+	// Environment variable: SERVER_DRAIN_DELAY
+	// Default: 5 seconds
+	// Unit: seconds
+	DrainDelay int `json:"drain_delay" yaml:"drain_delay" env:"SERVER_DRAIN_DELAY"`
+
+	// Security configures browser security headers on public API responses.
+	Security ServerSecurity `json:"security" yaml:"security"`
+
+	// API configures version negotiation for the versioned API under /api.
+	API ServerAPI `json:"api" yaml:"api"`
+
+	// Idempotency configures replay of responses to retried requests carrying
+	// an Idempotency-Key header.
+	Idempotency ServerIdempotency `json:"idempotency" yaml:"idempotency"`
+
+	// LoadShedding configures the adaptive concurrency limit of the public API.
+	LoadShedding ServerLoadShedding `json:"load_shedding" yaml:"load_shedding"`
+}
+
+// ServerLoadShedding configures adaptive load shedding on the public API.
+//
+// The server limits how many requests it processes at once. The limit grows
+// while requests complete within LatencyTarget and shrinks when latency rises
+// above it or requests time out, so it follows the capacity the instance
+// actually has. Requests beyond the limit are rejected with 503 and
+// Retry-After before any other work is done for them. Health probes and the
+// admin listener are never shed.
+//
+// Example configuration:
+//
+//	"load_shedding": {
+//	    "enabled": true,
+//	    "latency_target": 300,
+//	    "max_limit": 2000
+//	}
+type ServerLoadShedding struct {
+	// Enabled turns load shedding on.
+	//
+	// Environment variable: SERVER_LOAD_SHEDDING_ENABLED
+	// Default: false
+	Enabled bool `json:"enabled" yaml:"enabled" env:"SERVER_LOAD_SHEDDING_ENABLED"`
+
+	// InitialLimit is the concurrency limit at startup.
+	//
+	// Environment variable: SERVER_LOAD_SHEDDING_INITIAL_LIMIT
+	// Default: 100
+	InitialLimit int `json:"initial_limit" yaml:"initial_limit" env:"SERVER_LOAD_SHEDDING_INITIAL_LIMIT"`
+
+	// MinLimit is the lowest the limit can shrink to.
+	//
+	// Environment variable: SERVER_LOAD_SHEDDING_MIN_LIMIT
+	// Default: 10
+	MinLimit int `json:"min_limit" yaml:"min_limit" env:"SERVER_LOAD_SHEDDING_MIN_LIMIT"`
+
+	// MaxLimit is the highest the limit can grow to.
+	//
+	// Environment variable: SERVER_LOAD_SHEDDING_MAX_LIMIT
+	// Default: 1000
+	MaxLimit int `json:"max_limit" yaml:"max_limit" env:"SERVER_LOAD_SHEDDING_MAX_LIMIT"`
+
+	// LatencyTarget is the smoothed request latency above which the limit
+	// shrinks. Set it above the normal latency of the slowest common route.
+	//
+	// Environment variable: SERVER_LOAD_SHEDDING_LATENCY_TARGET
+	// Default: 500 milliseconds
+	// Unit: milliseconds
+	LatencyTarget int `json:"latency_target" yaml:"latency_target" env:"SERVER_LOAD_SHEDDING_LATENCY_TARGET"`
+
+	// RetryAfter is the Retry-After sent with rejected requests.
+	//
+	// Environment variable: SERVER_LOAD_SHEDDING_RETRY_AFTER
+	// Default: 1 second
+	// Unit: seconds
+	RetryAfter int `json:"retry_after" yaml:"retry_after" env:"SERVER_LOAD_SHEDDING_RETRY_AFTER"`
+
+	// ExemptPaths are never shed nor counted, so orchestrators keep seeing
+	// the instance as alive under load.
+	// Default: ["/health", "/ready", "/metrics"]
+	ExemptPaths []string `json:"exempt_paths" yaml:"exempt_paths"`
+}
+
+// ServerIdempotency configures Idempotency-Key handling for unsafe methods.
+//
+// The first request with a given Idempotency-Key runs normally and its
+// response is stored; retries with the same key and payload receive the
+// stored response with an Idempotent-Replayed: true header. A retry with the
+// same key but a different method, path or body is rejected with 422, and one
+// arriving while the first request is still running gets 409. Responses with
+// 5xx, 408 or 429 status are not stored, so such requests can be retried.
+//
+// Example configuration:
+//
+//	"idempotency": {
+//	    "store": "mongodb",
+//	    "ttl": 86400,
+//	    "methods": ["POST", "PATCH"]
+//	}
+type ServerIdempotency struct {
+	// Enabled turns Idempotency-Key handling on.
+	//
+	// Environment variable: SERVER_IDEMPOTENCY_ENABLED
+	// Default: true
+	Enabled bool `json:"enabled" yaml:"enabled" env:"SERVER_IDEMPOTENCY_ENABLED"`
+
+	// Store selects where keys are kept: "memory" or "mongodb". Use
+	// "mongodb" with more than one instance, so a retry reaching another
+	// instance is recognised.
+	//
+	// Environment variable: SERVER_IDEMPOTENCY_STORE
+	// Default: "memory"
+	Store string `json:"store" yaml:"store" env:"SERVER_IDEMPOTENCY_STORE"`
+
+	// Collection is the MongoDB collection for the "mongodb" store.
+	//
+	// Environment variable: SERVER_IDEMPOTENCY_COLLECTION
+	// Default: "idempotency_keys"
+	Collection string `json:"collection" yaml:"collection" env:"SERVER_IDEMPOTENCY_COLLECTION"`
+
+	// TTL is how long keys and their responses are kept after the first request.
+	//
+	// Environment variable: SERVER_IDEMPOTENCY_TTL
+	// Default: 86400 seconds (24 hours)
+	// Unit: seconds
+	TTL int `json:"ttl" yaml:"ttl" env:"SERVER_IDEMPOTENCY_TTL"`
+
+	// LockTimeout is how long a request holds its key while running. A retry
+	// arriving later may take the key over, e.g. after the instance running
+	// the first request died. Keep it above the handler timeout.
+	//
+	// Environment variable: SERVER_IDEMPOTENCY_LOCK_TIMEOUT
+	// Default: 60 seconds
+	// Unit: seconds
+	LockTimeout int `json:"lock_timeout" yaml:"lock_timeout" env:"SERVER_IDEMPOTENCY_LOCK_TIMEOUT"`
+
+	// MaxResponseBytes caps the stored response body. Larger responses are
+	// sent but not stored, so retries of such requests run again.
+	//
+	// Environment variable: SERVER_IDEMPOTENCY_MAX_RESPONSE_BYTES
+	// Default: 1048576 (1 MiB)
+	MaxResponseBytes int `json:"max_response_bytes" yaml:"max_response_bytes" env:"SERVER_IDEMPOTENCY_MAX_RESPONSE_BYTES"`
+
+	// Methods lists the HTTP methods whose Idempotency-Key is honoured.
+	// Default: ["POST", "PATCH"]
+	Methods []string `json:"methods" yaml:"methods"`
+}
+
+// ServerAPI configures how clients select a version of the API under /api.
+//
+// Versioned paths (/api/v1/..., /api/v2/...) are always served. With
+// Negotiation set to "header" the server also serves the unversioned paths
+// (/api/...), answering with the version named in the Accept header:
+//
+//	Accept: application/vnd.goedu-theta.v2+json
+//
+// Requests naming no version get DefaultVersion.
+//
+// Example configuration:
+//
+//	"api": {
+//	    "negotiation": "header",
+//	    "default_version": "v2"
+//	}
+type ServerAPI struct {
+	// Negotiation is "path" (version in the path only) or "header" (also
+	// serve unversioned paths, choosing the version by Accept header). Any
+	// other value behaves as "path".
+	//
+	// Environment variable: SERVER_API_NEGOTIATION
+	// Default: "path"
+	Negotiation string `json:"negotiation" yaml:"negotiation" env:"SERVER_API_NEGOTIATION"`
+
+	// DefaultVersion answers unversioned requests whose Accept header names
+	// no version. Keep it at the oldest supported version until its clients
+	// send an Accept header.
+	//
+	// Environment variable: SERVER_API_DEFAULT_VERSION
+	// Default: "v1"
+	DefaultVersion string `json:"default_version" yaml:"default_version" env:"SERVER_API_DEFAULT_VERSION"`
+}
+
+// ServerRestart configures graceful restarts by listener handoff.
+//
+// On SIGUSR2 the process starts a new copy of its executable (which may have
+// been replaced on disk), passing the bound listening sockets to it. Once the
+// new process reports that it is serving, the old one stops accepting
+// connections, finishes in-flight requests and exits. Connections queued on
+// the sockets in between are accepted by whichever process is serving, so
+// none are refused.
+//
+// The main PID changes with every restart, which process supervisors such as
+// systemd do not follow for simple services; use socket activation with a
+// plain restart there instead.
+type ServerRestart struct {
+	// Enabled makes the process restart on SIGUSR2. When disabled SIGUSR2 is ignored.
+	//
+	// Environment variable: SERVER_RESTART_ENABLED
+	// Default: false
+	Enabled bool `json:"enabled" yaml:"enabled" env:"SERVER_RESTART_ENABLED"`
+
+	// Timeout is how long to wait for the new process to report readiness.
+	// After it the new process is killed and the old one keeps serving.
+	//
+	// Environment variable: SERVER_RESTART_TIMEOUT
+	// Default: 30 seconds
+	// Unit: seconds
+	Timeout int `json:"timeout" yaml:"timeout" env:"SERVER_RESTART_TIMEOUT"`
+}
+
+// ServerListener is an additional listener address of the public API.
+type ServerListener struct {
+	// Address in the same forms as Server.Address, e.g. "unix:///run/goedu.sock".
+	Address string `json:"address" yaml:"address"`
+
+	// SocketMode overrides Server.SocketMode for this Unix socket.
+	// Default: "" (inherit)
+	SocketMode string `json:"socket_mode" yaml:"socket_mode"`
+
+	// SocketGroup overrides Server.SocketGroup for this Unix socket.
+	// Default: "" (inherit)
+	SocketGroup string `json:"socket_group" yaml:"socket_group"`
+}
+
+// CORSPolicy describes which cross-origin browser requests are allowed.
+//
+// Origin Patterns:
+// - "https://app.example.com": Exact origin (scheme, host and port must match)
+// - "https://*.example.com": Any subdomain of example.com over HTTPS (not example.com itself)
+// - "*": Any origin; credentials are never allowed for origins matched only by "*"
+type CORSPolicy struct {
+	// AllowedOrigins lists the origins allowed to call the API. Empty disables CORS.
+	// Default: [] (same-origin only)
+	AllowedOrigins []string `json:"allowed_origins" yaml:"allowed_origins"`
+
+	// AllowedMethods lists the methods accepted in preflight requests.
+	// Default: ["GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"]
+	AllowedMethods []string `json:"allowed_methods" yaml:"allowed_methods"`
+
+	// AllowedHeaders lists the request headers accepted in preflight requests; "*" accepts any.
+	// Default: ["Accept", "Authorization", "Content-Type", "X-Request-ID"]
+	AllowedHeaders []string `json:"allowed_headers" yaml:"allowed_headers"`
+
+	// ExposedHeaders lists response headers readable by browser scripts
+	// beyond the CORS-safelisted ones (e.g. "X-Request-ID").
+	// Default: []
+	ExposedHeaders []string `json:"exposed_headers" yaml:"exposed_headers"`
+
+	// AllowCredentials lets browsers send cookies and HTTP authentication.
+	// Default: false
+	AllowCredentials bool `json:"allow_credentials" yaml:"allow_credentials"`
+
+	// MaxAge is how long browsers may cache a preflight response. 0 omits the header.
+	// Default: 600 seconds
+	// Unit: seconds
+	MaxAge int `json:"max_age" yaml:"max_age"`
+}
+
+// ServerCORS configures CORS for the main HTTP server: a default policy plus
+// optional overrides for route groups.
+//
+// Group Overrides:
+// Groups maps a path prefix to a policy used instead of the default policy for
+// requests under that prefix; the longest matching prefix wins. Empty lists
+// and a zero MaxAge in a group policy inherit the default policy's values,
+// while AllowCredentials always applies as set in the group.
+//
+// Example configuration:
+//
+//	"cors": {
+//	    "allowed_origins": ["https://app.example.com", "https://*.preview.example.com"],
+//	    "allow_credentials": true,
+//	    "exposed_headers": ["X-Request-ID"],
+//	    "groups": {
+//	        "/api/public": {"allowed_origins": ["*"], "allowed_methods": ["GET"]}
+//	    }
+//	}
+type ServerCORS struct {
+	CORSPolicy `yaml:",inline"`
+
+	// Groups overrides the default policy for route groups, keyed by path prefix.
+	// Default: {} (the default policy applies everywhere)
+	Groups map[string]CORSPolicy `json:"groups" yaml:"groups"`
+}
+
+// RateLimitPolicy describes a token bucket: clients may send Burst requests at
+// once and regain Requests tokens every Window seconds.
+type RateLimitPolicy struct {
+	// Requests is the number of requests allowed per Window. 0 disables limiting.
+	// Default: 300
+	Requests int `json:"requests" yaml:"requests"`
+
+	// Window is the period over which Requests are allowed.
+	// Default: 60 seconds
+	// Unit: seconds
+	Window int `json:"window" yaml:"window"`
+
+	// Burst is the bucket capacity. 0 uses Requests.
+	// Default: 0
+	Burst int `json:"burst" yaml:"burst"`
+}
+
+// ServerRateLimit configures rate limiting for the main HTTP server: a default
+// policy plus optional overrides for route groups.
+//
+// Group Overrides:
+// Groups maps a path prefix to a policy used instead of the default policy for
+// requests under that prefix; the longest matching prefix wins. Each group has
+// its own buckets and replaces the default policy, so a group with Requests 0
+// is not limited. A zero Window inherits the default policy's value.
+//
+// Clients:
+// Buckets are kept per client IP, taken from forwarding headers only when the
+// peer is one of the trusted proxies. The limiter runs before routing, ahead
+// of any authentication, so it cannot key buckets by API key or user.
+//
+// Stores:
+// The "memory" store keeps buckets per process. Deployments with several
+// instances behind a load balancer should use the "mongodb" store so all
+// instances share one budget per client.
+//
+// Example configuration:
+//
+//	"rate_limit": {
+//	    "enabled": true,
+//	    "store": "mongodb",
+//	    "requests": 600, "window": 60, "burst": 100,
+//	    "groups": {
+//	        "/api/v1/auth": {"requests": 10, "window": 60}
+//	    }
+//	}
+type ServerRateLimit struct {
+	// Enabled turns rate limiting on.
+	//
+	// Environment variable: SERVER_RATE_LIMIT_ENABLED
+	// Default: false
+	Enabled bool `json:"enabled" yaml:"enabled" env:"SERVER_RATE_LIMIT_ENABLED"`
+
+	// Store selects where buckets are kept: "memory" or "mongodb".
+	//
+	// Environment variable: SERVER_RATE_LIMIT_STORE
+	// Default: "memory"
+	Store string `json:"store" yaml:"store" env:"SERVER_RATE_LIMIT_STORE"`
+
+	// Collection is the MongoDB collection for the "mongodb" store.
+	//
+	// Environment variable: SERVER_RATE_LIMIT_COLLECTION
+	// Default: "rate_limits"
+	Collection string `json:"collection" yaml:"collection" env:"SERVER_RATE_LIMIT_COLLECTION"`
+
+	// FailClosed rejects requests with 503 when the store cannot be reached.
+	// By default such requests are allowed, so a store outage does not take
+	// the API down.
+	//
+	// Environment variable: SERVER_RATE_LIMIT_FAIL_CLOSED
+	// Default: false
+	FailClosed bool `json:"fail_closed" yaml:"fail_closed" env:"SERVER_RATE_LIMIT_FAIL_CLOSED"`
+
+	RateLimitPolicy `yaml:",inline"`
+
+	// Groups overrides the default policy for route groups, keyed by path prefix.
+	// Default: {} (the default policy applies everywhere)
+	Groups map[string]RateLimitPolicy `json:"groups" yaml:"groups"`
+
+	// ExemptPaths are never limited, so orchestrators keep reaching the probes
+	// and metrics however busy the instance is.
+	// Default: ["/health", "/ready", "/metrics"]
+	ExemptPaths []string `json:"exempt_paths" yaml:"exempt_paths"`
+}
+
+// RouteLimits bounds the resources a single request may use.
+//
+// In route group overrides a zero value inherits the default and -1 removes
+// the limit for the group.
+type RouteLimits struct {
+	// MaxBodyBytes caps the request body. Requests declaring a larger
+	// Content-Length are rejected with 413 before the handler runs; streamed
+	// bodies fail with 413 once the handler reads past the limit.
+	//
+	// Environment variable: SERVER_MAX_BODY_BYTES
+	// Default: 1048576 (1 MiB)
+	// Unit: bytes
+	MaxBodyBytes int `json:"max_body_bytes" yaml:"max_body_bytes" env:"SERVER_MAX_BODY_BYTES"`
+
+	// HandlerTimeout is the deadline of the request context passed to handlers.
+	// When it expires the context is canceled and, unless the handler already
+	// responded, the client receives 503 with a timeout problem. Keep it below
+	// WriteTimeout so the error response can still be written.
+	//
+	// Environment variable: SERVER_HANDLER_TIMEOUT
+	// Default: 25 seconds
+	// Unit: seconds
+	HandlerTimeout int `json:"handler_timeout" yaml:"handler_timeout" env:"SERVER_HANDLER_TIMEOUT"`
+}
+
+// ServerLimits configures request limits: defaults plus optional overrides
+// for route groups keyed by path prefix, where the longest matching prefix wins.
+//
+// Example configuration:
+//
+//	"limits": {
+//	    "max_body_bytes": 1048576,
+//	    "handler_timeout": 25,
+//	    "groups": {
+//	        "/api/v1/uploads": {"max_body_bytes": 104857600, "handler_timeout": 300},
+//	        "/api/v1/events": {"handler_timeout": -1}
+//	    }
+//	}
+type ServerLimits struct {
+	RouteLimits `yaml:",inline"`
+
+	// Groups overrides the default limits for route groups, keyed by path prefix.
+	// Default: {} (the default limits apply everywhere)
+	Groups map[string]RouteLimits `json:"groups" yaml:"groups"`
+}
+
+// ServerCompression configures response compression.
+//
+// The encoding is negotiated from the request's Accept-Encoding header, using
+// the first entry of Algorithms the client accepts. Responses are compressed
+// only when their Content-Type matches ContentTypes and the body reaches
+// MinSize; Server-Sent Events, already encoded responses and bodyless
+// statuses are always sent as is.
+//
+// Example configuration:
+//
+//	"compression": {
+//	    "enabled": true,
+//	    "algorithms": ["zstd", "gzip"],
+//	    "min_size": 1024,
+//	    "content_types": ["application/json", "text/"]
+//	}
+type ServerCompression struct {
+	// Enabled turns response compression on.
+	//
+	// Environment variable: SERVER_COMPRESSION_ENABLED
+	// Default: true
+	Enabled bool `json:"enabled" yaml:"enabled" env:"SERVER_COMPRESSION_ENABLED"`
+
+	// Algorithms lists the supported encodings ("zstd", "gzip") in order of preference.
+	// Default: ["zstd", "gzip"]
+	Algorithms []string `json:"algorithms" yaml:"algorithms"`
+
+	// MinSize is the smallest body compressed; smaller bodies gain little and cost CPU.
+	//
+	// Environment variable: SERVER_COMPRESSION_MIN_SIZE
+	// Default: 1024 bytes
+	// Unit: bytes
+	MinSize int `json:"min_size" yaml:"min_size" env:"SERVER_COMPRESSION_MIN_SIZE"`
+
+	// GzipLevel is the gzip compression level from 1 (fastest) to 9 (smallest).
+	//
+	// Environment variable: SERVER_COMPRESSION_GZIP_LEVEL
+	// Default: 5
+	GzipLevel int `json:"gzip_level" yaml:"gzip_level" env:"SERVER_COMPRESSION_GZIP_LEVEL"`
+
+	// ZstdLevel is the zstd compression level on the standard zstd scale (1-22),
+	// mapped to the nearest level the encoder implements.
+	//
+	// Environment variable: SERVER_COMPRESSION_ZSTD_LEVEL
+	// Default: 3
+	ZstdLevel int `json:"zstd_level" yaml:"zstd_level" env:"SERVER_COMPRESSION_ZSTD_LEVEL"`
+
+	// ContentTypes lists the compressible media types. Entries ending in "/"
+	// match a whole type family (e.g. "text/").
+	// Default: ["application/json", "application/problem+json", "application/javascript", "application/xml", "image/svg+xml", "text/"]
+	ContentTypes []string `json:"content_types" yaml:"content_types"`
+}
+
+// ServerSecurity configures the security headers sent on every public API
+// response, including error responses. Empty header values omit the header.
+//
+// Content Security Policy:
+// Every occurrence of "{nonce}" in CSP is replaced with a fresh random nonce
+// per request, which handlers rendering HTML read with handlers.CSPNonce to
+// mark their inline scripts and styles. With CSPReportPath set, browsers
+// report violations to that endpoint, where they are logged.
+//
+// Example configuration:
+//
+//	"security": {
+//	    "csp": "default-src 'self'; script-src 'self' 'nonce-{nonce}'",
+//	    "csp_report_only": true,
+//	    "hsts_preload": true,
+//	    "frame_options": "SAMEORIGIN"
+//	}
+type ServerSecurity struct {
+	// Enabled turns the security headers on.
+	//
+	// Environment variable: SERVER_SECURITY_ENABLED
+	// Default: true
+	Enabled bool `json:"enabled" yaml:"enabled" env:"SERVER_SECURITY_ENABLED"`
+
+	// CSP is the Content-Security-Policy, with "{nonce}" placeholders for
+	// the per-request nonce.
+	//
+	// Environment variable: SERVER_SECURITY_CSP
+	// Default: "default-src 'none'; script-src 'self' 'nonce-{nonce}'; style-src 'self' 'nonce-{nonce}'; img-src 'self' data:; connect-src 'self'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'"
+	CSP string `json:"csp" yaml:"csp" env:"SERVER_SECURITY_CSP"`
+
+	// CSPReportOnly sends the policy as Content-Security-Policy-Report-Only,
+	// so violations are reported but not blocked. Use it to trial a policy.
+	//
+	// Environment variable: SERVER_SECURITY_CSP_REPORT_ONLY
+	// Default: false
+	CSPReportOnly bool `json:"csp_report_only" yaml:"csp_report_only" env:"SERVER_SECURITY_CSP_REPORT_ONLY"`
+
+	// CSPReportPath is the path of the violation report endpoint, added to
+	// the policy as report-uri and report-to. Empty disables reporting.
+	//
+	// Environment variable: SERVER_SECURITY_CSP_REPORT_PATH
+	// Default: "/csp-report"
+	CSPReportPath string `json:"csp_report_path" yaml:"csp_report_path" env:"SERVER_SECURITY_CSP_REPORT_PATH"`
+
+	// HSTSMaxAge is the Strict-Transport-Security max-age, sent only on
+	// requests received over TLS. 0 omits the header.
+	//
+	// Environment variable: SERVER_SECURITY_HSTS_MAX_AGE
+	// Default: 31536000 seconds (one year)
+	// Unit: seconds
+	HSTSMaxAge int `json:"hsts_max_age" yaml:"hsts_max_age" env:"SERVER_SECURITY_HSTS_MAX_AGE"`
+
+	// HSTSIncludeSubdomains extends HSTS to all subdomains.
+	//
+	// Environment variable: SERVER_SECURITY_HSTS_INCLUDE_SUBDOMAINS
+	// Default: true
+	HSTSIncludeSubdomains bool `json:"hsts_include_subdomains" yaml:"hsts_include_subdomains" env:"SERVER_SECURITY_HSTS_INCLUDE_SUBDOMAINS"`
+
+	// HSTSPreload requests inclusion in browser HSTS preload lists. Hard to
+	// undo; enable only once every subdomain serves HTTPS.
+	//
+	// Environment variable: SERVER_SECURITY_HSTS_PRELOAD
+	// Default: false
+	HSTSPreload bool `json:"hsts_preload" yaml:"hsts_preload" env:"SERVER_SECURITY_HSTS_PRELOAD"`
+
+	// ReferrerPolicy is the Referrer-Policy header.
+	//
+	// Environment variable: SERVER_SECURITY_REFERRER_POLICY
+	// Default: "no-referrer"
+	ReferrerPolicy string `json:"referrer_policy" yaml:"referrer_policy" env:"SERVER_SECURITY_REFERRER_POLICY"`
+
+	// PermissionsPolicy is the Permissions-Policy header.
+	//
+	// Environment variable: SERVER_SECURITY_PERMISSIONS_POLICY
+	// Default: "camera=(), microphone=(), geolocation=(), payment=()"
+	PermissionsPolicy string `json:"permissions_policy" yaml:"permissions_policy" env:"SERVER_SECURITY_PERMISSIONS_POLICY"`
+
+	// FrameOptions is the X-Frame-Options header ("DENY" or "SAMEORIGIN"),
+	// for browsers that ignore CSP frame-ancestors.
+	//
+	// Environment variable: SERVER_SECURITY_FRAME_OPTIONS
+	// Default: "DENY"
+	FrameOptions string `json:"frame_options" yaml:"frame_options" env:"SERVER_SECURITY_FRAME_OPTIONS"`
+}
+
+// ServerTLS configures HTTPS for the main HTTP server.
+//
+// Certificate Sources:
+// - CertFile/KeyFile: PEM files, re-read when they change on disk (e.g. after a renewal)
+// - SelfSigned: An in-memory certificate generated at startup for local development
+//
+// HTTP/2 is negotiated automatically via ALPN whenever TLS is enabled.
+//
+// Example configuration:
+//
+//	"tls": {
+//	    "enabled": true,
+//	    "cert_file": "/etc/goedu/tls.crt",
+//	    "key_file": "/etc/goedu/tls.key",
+//	    "client_ca_file": "/etc/goedu/clients-ca.pem",
+//	    "redirect_port": 8080
+//	}
+//
+// Security Notes:
+// - Key files should be readable only by the service account (mode 0600)
+// - Never enable SelfSigned in production; clients cannot verify its identity
+type ServerTLS struct {
+	// Enabled switches the main listener from plain HTTP to HTTPS.
+	//
+	// Environment variable: SERVER_TLS_ENABLED
+	// Default: false
+	Enabled bool `json:"enabled" yaml:"enabled" env:"SERVER_TLS_ENABLED"`
+
+	// CertFile is the PEM certificate chain (leaf first). Required unless SelfSigned is set.
+	//
+	// Environment variable: SERVER_TLS_CERT_FILE
+	CertFile string `json:"cert_file" yaml:"cert_file" env:"SERVER_TLS_CERT_FILE"`
+
+	// KeyFile is the PEM private key matching CertFile.
+	//
+	// Environment variable: SERVER_TLS_KEY_FILE
+	KeyFile string `json:"key_file" yaml:"key_file" env:"SERVER_TLS_KEY_FILE"`
+
+	// MinVersion is the lowest accepted protocol version: "1.2" or "1.3".
+	//
+	// Environment variable: SERVER_TLS_MIN_VERSION
+	// Default: "1.2"
+	MinVersion string `json:"min_version" yaml:"min_version" env:"SERVER_TLS_MIN_VERSION"`
+
+	// CipherPolicy selects the accepted cipher suites:
+	// - "default": Go's built-in secure defaults
+	// - "intermediate": Only ECDHE key exchange with AEAD ciphers for TLS 1.2
+	//   (Mozilla "intermediate" profile); TLS 1.3 suites are always enabled
+	// - "modern": TLS 1.3 only, regardless of MinVersion
+	//
+	// Environment variable: SERVER_TLS_CIPHER_POLICY
+	// Default: "default"
+	CipherPolicy string `json:"cipher_policy" yaml:"cipher_policy" env:"SERVER_TLS_CIPHER_POLICY"`
+
+	// ClientCAFile enables mutual TLS: clients must present a certificate
+	// signed by one of the PEM certificates in this file.
+	//
+	// Environment variable: SERVER_TLS_CLIENT_CA_FILE
+	// Default: "" (client certificates are not requested)
+	ClientCAFile string `json:"client_ca_file" yaml:"client_ca_file" env:"SERVER_TLS_CLIENT_CA_FILE"`
+
+	// ClientAuth controls client certificate checking when ClientCAFile is set:
+	// - "require": Reject clients without a valid certificate (default)
+	// - "optional": Verify a certificate if one is presented, allow clients without one
+	//
+	// Environment variable: SERVER_TLS_CLIENT_AUTH
+	// Default: "require"
+	ClientAuth string `json:"client_auth" yaml:"client_auth" env:"SERVER_TLS_CLIENT_AUTH"`
+
+	// SelfSigned generates a throwaway certificate for localhost and Host at
+	// startup instead of reading CertFile/KeyFile. Development only.
+	//
+	// Environment variable: SERVER_TLS_SELF_SIGNED
+	// Default: false
+	SelfSigned bool `json:"self_signed" yaml:"self_signed" env:"SERVER_TLS_SELF_SIGNED"`
+
+	// ReloadInterval is the minimum time between checks of CertFile and
+	// KeyFile for changes. Checks happen during TLS handshakes, so an idle
+	// server picks up a renewed certificate on its next connection.
+	//
+	// Environment variable: SERVER_TLS_RELOAD_INTERVAL
+	// Default: 10 seconds
+	// Unit: seconds
+	ReloadInterval int `json:"reload_interval" yaml:"reload_interval" env:"SERVER_TLS_RELOAD_INTERVAL"`
+
+	// RedirectPort starts an additional plain-HTTP listener on Host that
+	// permanently redirects every request to the HTTPS listener.
+	//
+	// Environment variable: SERVER_TLS_REDIRECT_PORT
+	// Default: 0 (no redirect listener)
+	RedirectPort int `json:"redirect_port" yaml:"redirect_port" env:"SERVER_TLS_REDIRECT_PORT"`
 }
 
 // Database defines the complete database connection configuration for the GoEdu-Theta application.
@@ -627,3 +1629,47 @@ type Test struct {
 	// Default: Typically set in environment-specific or local config files
 	Label_override string `json:"label_override" yaml:"label_override" env:"TEST_LABEL_OVERRIDE"`
 }
+
+// Audit configures where the audit trail of administrative actions is stored.
+//
+// Storage Backends:
+// - "file": Append-only JSON Lines file; suitable for a single instance
+// - "mongodb": Dedicated collection in the application database; required for multiple instances
+// - "disabled": No audit trail; administrative actions that require auditing fail
+//
+// Example configuration:
+//
+//	"audit": {"backend": "mongodb", "collection": "audit_log"}
+//
+// Verification:
+// Run the audit-verify subcommand against the same configuration to check
+// the hash chain for gaps and modified entries.
+type Audit struct {
+	// Backend selects the storage: "file", "mongodb" or "disabled".
+	//
+	// Environment variable: AUDIT_BACKEND
+	// Default: "file"
+	Backend string `json:"backend" yaml:"backend" env:"AUDIT_BACKEND"`
+
+	// FilePath is the audit file location for the "file" backend. Missing
+	// directories are created; the file itself is created with mode 0600.
+	//
+	// Environment variable: AUDIT_FILE_PATH
+	// Default: "audit/audit.log"
+	FilePath string `json:"file_path" yaml:"file_path" env:"AUDIT_FILE_PATH"`
+
+	// Collection is the MongoDB collection for the "mongodb" backend.
+	//
+	// Environment variable: AUDIT_COLLECTION
+	// Default: "audit_log"
+	Collection string `json:"collection" yaml:"collection" env:"AUDIT_COLLECTION"`
+
+	// HMACSecret keys the hash chain, so entries cannot be edited and re-hashed
+	// by anyone without it. Required unless the backend is "disabled"; at least
+	// 32 bytes. Set it from the environment or a secret store, never in the
+	// audit store itself, and keep it for as long as the trail must be verifiable.
+	//
+	// Environment variable: AUDIT_HMAC_SECRET
+	// Default: "" (must be configured)
+	HMACSecret string `json:"hmac_secret" yaml:"hmac_secret" env:"AUDIT_HMAC_SECRET"`
+}
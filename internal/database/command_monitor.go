@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+
+	"go.mongodb.org/mongo-driver/event"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/logger"
+)
+
+// newCommandMonitor returns a driver command monitor that logs every MongoDB
+// command at logger.LevelTrace.
+//
+// Only command names, database names, request IDs and durations are logged;
+// command and reply documents are never included because they may carry
+// credentials or personal data. The Enabled check keeps the monitor free when
+// trace logging is off.
+//
+// Example:
+//
+//	SLOG_LEVEL=trace go run ./cmd/server
+func newCommandMonitor(log *slog.Logger) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			if !log.Enabled(ctx, logger.LevelTrace) {
+				return
+			}
+			log.LogAttrs(ctx, logger.LevelTrace, "🍃 MongoDB command started",
+				slog.String("command", e.CommandName),   // Command name (e.g. "find", "insert")
+				slog.String("database", e.DatabaseName), // Target database
+				slog.Int64("request_id", e.RequestID),   // Driver request ID correlating start and finish
+			)
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			if !log.Enabled(ctx, logger.LevelTrace) {
+				return
+			}
+			log.LogAttrs(ctx, logger.LevelTrace, "🍃 MongoDB command succeeded",
+				slog.String("command", e.CommandName),
+				slog.Int64("request_id", e.RequestID),
+				slog.Duration("duration", e.Duration), // Round-trip time reported by the driver
+			)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			if !log.Enabled(ctx, logger.LevelTrace) {
+				return
+			}
+			log.LogAttrs(ctx, logger.LevelTrace, "🍃 MongoDB command failed",
+				slog.String("command", e.CommandName),
+				slog.Int64("request_id", e.RequestID),
+				slog.Duration("duration", e.Duration),
+				slog.String("failure", e.Failure), // Server or network error message
+			)
+		},
+	}
+}
@@ -202,6 +202,7 @@ func NewMongoDBManager(cfg config.Database, logger *slog.Logger) (*MongoDBManage
 	// Configure heartbeat and monitoring intervals for connection health tracking.
 	// Regular health checks ensure connection reliability and automatic recovery.
 	clientOptions.SetHeartbeatInterval(10 * time.Second) // Connection health check interval
+	clientOptions.SetMonitor(newCommandMonitor(logger))  // Command names and durations at TRACE level
 
 	// Step 4: Create MongoDB client instance with configured options
 	// The client manages the connection pool and provides the interface for database operations
@@ -3,12 +3,14 @@ package database_test
 import (
 	"context"
 	"log/slog"
+	"net/url"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
 	"github.com/radek-zitek-cloud/goedu-theta/internal/database"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/logger/logtest"
 )
 
 // TestNewMongoDBManager_ValidConfig tests the MongoDB manager creation with valid configuration.
@@ -556,3 +558,61 @@ func TestNewMongoDBManager_AtlasConfig(t *testing.T) {
 		})
 	}
 }
+
+// TestNewMongoDBManager_NeverLogsPassword verifies that no log record emitted while
+// connecting contains the database password, neither verbatim nor URL-encoded as it
+// appears inside the connection string.
+//
+// Testing Strategy:
+//   - Records all log output (including debug) with logtest
+//   - Uses unreachable servers so every connection attempt fails and logs its error path
+//   - Covers both standard and Atlas (SRV) connection string construction
+func TestNewMongoDBManager_NeverLogsPassword(t *testing.T) {
+	const password = "p@ss:w0rd/s3cr3t"
+
+	testCases := []struct {
+		name   string
+		config config.Database
+	}{
+		{
+			name: "Standard",
+			config: config.Database{
+				Host:     "127.0.0.1",
+				Port:     1, // Nothing listens here: ping fails after server selection
+				User:     "testuser",
+				Password: password,
+				Name:     "testdb",
+			},
+		},
+		{
+			name: "Atlas",
+			config: config.Database{
+				Host:     "cluster.invalid", // Reserved TLD: SRV lookup fails
+				Port:     27017,
+				User:     "testuser",
+				Password: password,
+				Name:     "testdb",
+				IsAtlas:  true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			rec := logtest.New(t)
+
+			manager, err := database.NewMongoDBManager(tc.config, rec.Logger())
+			if err == nil {
+				manager.Close()
+				t.Skip("Skipping test - unexpectedly connected to a MongoDB server")
+			}
+
+			rec.AssertLogged(logtest.MessageContains("Initializing MongoDB connection manager"))
+			rec.AssertNotLogged(logtest.AnyOf(
+				logtest.Contains(password),
+				logtest.Contains(url.QueryEscape(password)),
+			))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apperror"
+)
+
+// WriteError renders err as an RFC 7807 problem details response and aborts
+// the handler chain. Errors that are not *apperror.Error are classified by
+// classifyError; anything unrecognised is answered as an internal error
+// without exposing its text.
+//
+// Handlers should use h.fail, which also logs the error; WriteError is for
+// middleware that has no Handler.
+func WriteError(c *gin.Context, err error) {
+	appErr := classifyError(c, err)
+	// Set before rendering; gin keeps an existing Content-Type
+	c.Header("Content-Type", apperror.ContentType)
+	c.AbortWithStatusJSON(appErr.Status, appErr.Problem(c.Request.URL.Path, RequestID(c)))
+}
+
+// classifyError converts err into an *apperror.Error, mapping the errors
+// produced by the request limits so every handler reports them the same way:
+//   - *http.MaxBytesError (body read past the limit): 413 payload_too_large
+//   - context.DeadlineExceeded after the request deadline expired: 503 request_timeout
+//   - context.DeadlineExceeded while the request still had time, i.e. an upstream call's own timeout: 504 upstream_timeout
+func classifyError(c *gin.Context, err error) *apperror.Error {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return apperror.PayloadTooLarge(maxBytesErr.Limit).Wrap(err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		if c.Request.Context().Err() != nil {
+			return apperror.Timeout().Wrap(err)
+		}
+		return apperror.GatewayTimeout("upstream service did not respond in time").Wrap(err)
+	}
+	return apperror.Internal(err)
+}
+
+// fail logs err and answers the request with a problem details response.
+//
+// Server errors (5xx) are logged at Error level with the underlying cause;
+// client errors at Debug level, since they are already visible in the access
+// log by status code.
+//
+// Example:
+//
+//	if err := c.ShouldBindJSON(&req); err != nil {
+//	    h.fail(c, apperror.BadRequest("request body is not valid JSON").Wrap(err))
+//	    return
+//	}
+func (h *Handler) fail(c *gin.Context, err error) {
+	appErr := classifyError(c, err)
+
+	level := slog.LevelDebug
+	if appErr.Status >= 500 {
+		level = slog.LevelError
+	}
+	attrs := []any{
+		slog.String("code", appErr.Code),        // Machine-readable error code
+		slog.Int("status", appErr.Status),       // HTTP status sent to the client
+		slog.String("request_id", RequestID(c)), // Correlates with the response body
+		slog.String("path", c.Request.URL.Path), // Request path
+		slog.String("message", appErr.Message),  // Client-facing message
+	}
+	if appErr.Err != nil {
+		attrs = append(attrs, slog.Any("error", appErr.Err)) // Underlying cause, never sent to clients
+	}
+	h.logger.Log(context.Background(), level, "❌ Request failed", attrs...)
+
+	WriteError(c, appErr)
+}
+
+// HandleNoRoute answers requests for unknown paths with a 404 problem response.
+func (h *Handler) HandleNoRoute(c *gin.Context) {
+	h.fail(c, apperror.NotFound("no resource at "+c.Request.URL.Path))
+}
+
+// HandleNoMethod answers requests using a method the path does not support
+// with a 405 problem response listing the allowed methods. The router sets
+// the Allow header before calling it.
+func (h *Handler) HandleNoMethod(c *gin.Context) {
+	var allowed []string
+	if allow := c.Writer.Header().Get("Allow"); allow != "" {
+		allowed = strings.Split(allow, ", ")
+	}
+	h.fail(c, apperror.MethodNotAllowed(c.Request.Method, allowed))
+}
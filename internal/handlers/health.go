@@ -9,6 +9,44 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// HealthResponse is the body of GET /health.
+type HealthResponse struct {
+	Status     string           `json:"status"`    // Always "healthy"
+	Timestamp  string           `json:"timestamp"` // Current time in RFC3339, UTC
+	Runtime    HealthRuntime    `json:"runtime"`
+	Memory     HealthMemory     `json:"memory"`
+	Goroutines HealthGoroutines `json:"goroutines"`
+	Service    HealthService    `json:"service"`
+}
+
+// HealthRuntime describes the Go runtime environment.
+type HealthRuntime struct {
+	GoVersion string `json:"go_version"` // Go version the service was built with
+	Platform  string `json:"platform"`   // GOOS/GOARCH
+	CPUCores  int    `json:"cpu_cores"`  // Logical CPUs available to the process
+}
+
+// HealthMemory summarizes Go runtime memory statistics.
+type HealthMemory struct {
+	AllocatedBytes uint64 `json:"allocated_bytes"`   // Heap bytes allocated to live objects
+	HeapInUseBytes uint64 `json:"heap_in_use_bytes"` // Bytes in in-use heap spans
+	GCCycles       uint32 `json:"gc_cycles"`         // Completed garbage collection cycles
+	AllocatedMB    uint64 `json:"allocated_mb"`      // AllocatedBytes in whole megabytes
+	NextGCBytes    uint64 `json:"next_gc_bytes"`     // Heap size of the next collection
+}
+
+// HealthGoroutines reports goroutine counts.
+type HealthGoroutines struct {
+	Count int `json:"count"` // Current goroutines, including runtime ones
+}
+
+// HealthService identifies the service instance.
+type HealthService struct {
+	Name        string `json:"name"`
+	Environment string `json:"environment"`
+	Version     string `json:"version"`
+}
+
 // HandleHealth handles GET /health requests and provides comprehensive health status.
 //
 // This endpoint implements a comprehensive health check following industry best practices
@@ -45,73 +83,73 @@ func (h *Handler) HandleHealth(c *gin.Context) {
 
 	// Construct comprehensive health status response
 	// Each field provides specific operational insights for monitoring systems
-	response := gin.H{
+	response := HealthResponse{
 		// Primary health indicator - always "healthy" if endpoint is reachable
 		// This is the main field that load balancers and orchestrators check
-		"status": "healthy",
+		Status: "healthy",
 
 		// Current UTC timestamp in RFC3339 format for request timing analysis
 		// Helps identify stale responses and measure response times
-		"timestamp": time.Now().UTC().Format(time.RFC3339),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
 
 		// Runtime environment information for debugging and compatibility verification
-		"runtime": gin.H{
+		Runtime: HealthRuntime{
 			// Go version used to build the service - critical for compatibility tracking
-			"go_version": runtime.Version(),
+			GoVersion: runtime.Version(),
 
 			// Operating system and architecture - important for deployment verification
-			"platform": runtime.GOOS + "/" + runtime.GOARCH,
+			Platform: runtime.GOOS + "/" + runtime.GOARCH,
 
 			// Number of logical CPUs available to the process
 			// Useful for understanding available parallelism and resource allocation
-			"cpu_cores": runtime.NumCPU(),
+			CPUCores: runtime.NumCPU(),
 		},
 
 		// Memory usage statistics from Go runtime - essential for resource monitoring
-		"memory": gin.H{
+		Memory: HealthMemory{
 			// Current heap memory allocated to objects (bytes)
 			// This is memory actively used by the application
-			"allocated_bytes": memStats.Alloc,
+			AllocatedBytes: memStats.Alloc,
 
 			// Total number of bytes allocated and still in use
 			// Different from Alloc due to garbage collection timing
-			"heap_in_use_bytes": memStats.HeapInuse,
+			HeapInUseBytes: memStats.HeapInuse,
 
 			// Number of completed garbage collection cycles
 			// High values may indicate memory pressure or allocation patterns
-			"gc_cycles": memStats.NumGC,
+			GCCycles: memStats.NumGC,
 
 			// Human-readable memory allocation for easier interpretation
 			// Provides quick visual assessment without byte-to-MB conversion
-			"allocated_mb": bToMb(memStats.Alloc),
+			AllocatedMB: bToMb(memStats.Alloc),
 
 			// Next garbage collection target size (bytes)
 			// Helps understand GC behavior and memory management efficiency
-			"next_gc_bytes": memStats.NextGC,
+			NextGCBytes: memStats.NextGC,
 		},
 
 		// Go runtime concurrency metrics - crucial for performance monitoring
-		"goroutines": gin.H{
+		Goroutines: HealthGoroutines{
 			// Current number of goroutines in the Go runtime
 			// High values may indicate goroutine leaks or high concurrency
-			"count": runtime.NumGoroutine(),
+			Count: runtime.NumGoroutine(),
 
 			// Note: This count includes system goroutines (GC, scheduler, etc.)
 			// Typical baseline is 2-5 goroutines for minimal applications
 		},
 
 		// Service-specific operational metrics
-		"service": gin.H{
+		Service: HealthService{
 			// Service identifier for multi-service environments
-			"name": "goedu-theta",
+			Name: "goedu-theta",
 
 			// Environment indicator - useful for distinguishing deployment stages
 			// TODO: Consider loading from configuration or environment variables
-			"environment": "development",
+			Environment: "development",
 
 			// Service version for deployment tracking and compatibility verification
 			// TODO: Consider loading from build-time variables or git tags
-			"version": "1.0.0",
+			Version: "1.0.0",
 		},
 	}
 
@@ -7,6 +7,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apiversion"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/logger"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/ratelimit"
 )
 
 // HandleMetrics handles GET /metrics requests and provides comprehensive application metrics.
@@ -183,7 +186,22 @@ func (h *Handler) HandleMetrics(c *gin.Context) {
 			"requests": gin.H{
 				// TODO: Implement request counting and timing
 				"note": "request_metrics_not_implemented",
+
+				// Handler panics recovered by the server since startup
+				"panics_total": panicsTotal.Load(),
 			},
+
+			// Logging pipeline health: async queue depth and dropped records
+			"logging": loggingMetrics(),
+
+			// Rate limiter decisions per policy
+			"rate_limit": rateLimitMetrics(),
+
+			// Calls to deprecated API routes, to tell when they can be removed
+			"deprecated_routes": deprecatedRouteMetrics(),
+
+			// Adaptive concurrency limit and the load it sheds
+			"load_shedding": h.loadSheddingMetrics(),
 		},
 
 		// System resource utilization (basic Go runtime view)
@@ -344,3 +362,111 @@ func calculateGCOverhead(memStats runtime.MemStats) float64 {
 	// Real GC overhead depends on allocation patterns and GC tuning
 	return (avgPauseNs / 1e9) * 100.0 // Convert to seconds and percentage
 }
+
+// loggingMetrics reports the state of the logging pipeline.
+//
+// The async section exposes the asynchronous output queue (depth, capacity and
+// dropped records) and the sinks section the per-sink delivery counters of remote
+// log shipping. Rising dropped counts indicate the output or a collector cannot
+// keep up with the log volume.
+//
+// Returns:
+//   - gin.H with "async" and "sinks" subsections
+func loggingMetrics() gin.H {
+	asyncSection := gin.H{"enabled": false}
+	if stats, ok := logger.QueueStats(); ok {
+		asyncSection = gin.H{
+			"enabled":         true,
+			"queue_depth":     stats.QueueDepth,
+			"queue_capacity":  stats.Capacity,
+			"overflow_policy": string(stats.Policy),
+			"processed_total": stats.Processed,
+			"dropped_total":   stats.Dropped,
+			"blocked_total":   stats.Blocked,
+		}
+	}
+
+	sinks := []gin.H{}
+	for _, stats := range logger.SinkStats() {
+		sinks = append(sinks, gin.H{
+			"type":          stats.Type,
+			"queued":        stats.Queued,
+			"sent_total":    stats.Sent,
+			"dropped_total": stats.Dropped,
+			"failed_total":  stats.Failed,
+			"retries_total": stats.Retries,
+		})
+	}
+
+	return gin.H{
+		"async": asyncSection,
+		"sinks": sinks,
+	}
+}
+
+// rateLimitMetrics reports the rate limiter decisions of every policy that
+// has seen traffic. A rising limited_total shows clients hitting their budget;
+// a rising errors_total shows the bucket store failing.
+//
+// Returns:
+//   - gin.H with a "policies" list of per-policy counters
+func rateLimitMetrics() gin.H {
+	policies := []gin.H{}
+	for _, stats := range ratelimit.Stats() {
+		policies = append(policies, gin.H{
+			"policy":        stats.Policy,
+			"allowed_total": stats.Allowed,
+			"limited_total": stats.Limited,
+			"errors_total":  stats.Errors,
+		})
+	}
+	return gin.H{"policies": policies}
+}
+
+// deprecatedRouteMetrics reports the calls to every deprecated API route since
+// startup. A route still at zero calls close to its sunset date can be removed
+// without breaking clients.
+//
+// Returns:
+//   - []gin.H with one entry per deprecated route
+func deprecatedRouteMetrics() []gin.H {
+	result := []gin.H{}
+	for _, stats := range apiversion.DeprecatedCalls() {
+		entry := gin.H{
+			"route":       stats.Route,
+			"calls_total": stats.Calls,
+			"sunset":      nil,
+			"last_call":   nil,
+		}
+		if !stats.Sunset.IsZero() {
+			entry["sunset"] = stats.Sunset.UTC().Format(time.RFC3339)
+		}
+		if !stats.LastCall.IsZero() {
+			entry["last_call"] = stats.LastCall.UTC().Format(time.RFC3339)
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// loadSheddingMetrics reports the adaptive concurrency limit. A limit close
+// to min_limit with rising rejected_total means the instance is saturated and
+// needs more capacity; a falling limit with low in_flight points at a slow
+// dependency.
+//
+// Returns:
+//   - gin.H with the limit, in_flight and latency gauges and the accepted and rejected counters
+func (h *Handler) loadSheddingMetrics() gin.H {
+	if h.shedder == nil {
+		return gin.H{"enabled": false}
+	}
+	stats := h.shedder.Stats()
+	return gin.H{
+		"enabled":        true,
+		"limit":          stats.Limit,
+		"in_flight":      stats.InFlight,
+		"latency_ms":     float64(stats.Latency.Microseconds()) / 1e3,
+		"accepted_total": stats.Accepted,
+		"rejected_total": stats.Rejected,
+	}
+}
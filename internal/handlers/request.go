@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader is the header carrying the request ID, accepted from
+// clients and proxies and echoed on every response.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the Gin context key under which the server's request ID
+// middleware stores the ID of the current request.
+const RequestIDKey = "request_id"
+
+// RepanicKey is the Gin context key that makes the server's recovery
+// middleware re-panic after logging and counting a panic, instead of
+// answering with 500. Handlers set it in tests so a panic fails the test with
+// its original value and stack:
+//
+//	c.Set(handlers.RepanicKey, true)
+const RepanicKey = "recovery.repanic"
+
+// RequestID returns the ID of the current request, or "" when the request ID
+// middleware did not run (e.g. when a handler is called directly in tests).
+func RequestID(c *gin.Context) string {
+	return c.GetString(RequestIDKey)
+}
+
+// CSPNonceKey is the Gin context key under which the server's security
+// headers middleware stores the Content-Security-Policy nonce of the request.
+const CSPNonceKey = "csp_nonce"
+
+// CSPNonce returns the Content-Security-Policy nonce of the current request,
+// for the nonce attribute of inline <script> and <style> elements. It is ""
+// when the policy has no "{nonce}" placeholder or the middleware did not run.
+//
+// Example:
+//
+//	fmt.Fprintf(w, `<script nonce="%s">...</script>`, handlers.CSPNonce(c))
+func CSPNonce(c *gin.Context) string {
+	return c.GetString(CSPNonceKey)
+}
+
+// panicsTotal counts panics recovered from request handlers.
+var panicsTotal atomic.Uint64
+
+// RecordPanic counts a recovered handler panic for the /metrics endpoint.
+func RecordPanic() {
+	panicsTotal.Add(1)
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/loadshed"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/routes"
 )
 
 // Handler holds dependencies needed by HTTP handlers.
@@ -15,6 +17,8 @@ import (
 //
 // Dependencies:
 //   - Logger: Structured logger for request/response logging and debugging
+//   - Routes: Registry of the server's routes, listed by the root endpoint
+//   - Load shedder: Adaptive concurrency limiter, reported by the metrics endpoint
 //   - Config: Application configuration (can be added later if needed)
 //   - Services: Business logic services (can be added later if needed)
 //
@@ -24,7 +28,9 @@ import (
 //   - Consistency: All handlers use the same dependency injection pattern
 //   - Maintainability: Dependencies are explicit and centralized
 type Handler struct {
-	logger *slog.Logger // Structured logger instance for HTTP request logging
+	logger  *slog.Logger      // Structured logger instance for HTTP request logging
+	routes  *routes.Registry  // Registered routes listed by HandleRoot; nil lists none
+	shedder *loadshed.Limiter // Load shedding limiter reported by HandleMetrics; nil when disabled
 }
 
 // NewHandler creates a new Handler instance with the provided dependencies.
@@ -45,6 +51,29 @@ func NewHandler(logger *slog.Logger) *Handler {
 	}
 }
 
+// RootResponse is the body of GET /.
+type RootResponse struct {
+	Message   string         `json:"message"`   // Human-readable welcome message
+	Status    string         `json:"status"`    // Always "running"
+	Version   string         `json:"version"`   // API version
+	Timestamp string         `json:"timestamp"` // Current time in RFC3339, UTC
+	Endpoints []string       `json:"endpoints"` // Available endpoints, e.g. "GET /health"
+	Routes    []routes.Route `json:"routes"`    // Available endpoints with description, auth and deprecation
+}
+
+// SetRoutes sets the registry whose public routes HandleRoot lists. The
+// server passes the registry its routes are recorded in, so the listing
+// always matches the routing table.
+func (h *Handler) SetRoutes(registry *routes.Registry) {
+	h.routes = registry
+}
+
+// SetLoadShedder sets the load shedding limiter whose gauges HandleMetrics
+// reports. nil reports load shedding as disabled.
+func (h *Handler) SetLoadShedder(limiter *loadshed.Limiter) {
+	h.shedder = limiter
+}
+
 // HandleRoot handles GET / requests and provides API discovery information.
 //
 // This endpoint serves as the main entry point for API consumers, providing:
@@ -63,31 +92,40 @@ func NewHandler(logger *slog.Logger) *Handler {
 // Performance: <50ms response time (no external dependencies)
 // Caching: Safe to cache for short periods (30s-1min)
 func (h *Handler) HandleRoot(c *gin.Context) {
+	// List the public routes from the registry so new routes appear automatically
+	var listed []routes.Route
+	if h.routes != nil {
+		listed = h.routes.Routes(routes.ListenerPublic)
+	}
+	endpoints := make([]string, 0, len(listed))
+	for _, r := range listed {
+		endpoints = append(endpoints, r.String())
+	}
+
 	// Construct the response payload with comprehensive service information
-	// Using gin.H (map[string]interface{}) for flexible JSON serialization
-	response := gin.H{
+	// Using RootResponse so the OpenAPI document describes exactly what is sent
+	response := RootResponse{
 		// Human-readable welcome message for API consumers
-		"message": "Welcome to GoEdu-Theta API Server",
+		Message: "Welcome to GoEdu-Theta API Server",
 
 		// Current operational status - always "running" if endpoint is accessible
 		// This provides immediate feedback that the service is operational
-		"status": "running",
+		Status: "running",
 
 		// Semantic version following semver.org conventions
 		// TODO: Consider loading from build-time variables or config
-		"version": "1.0.0",
+		Version: "1.0.0",
 
 		// Current timestamp in UTC using RFC3339 format (ISO 8601 compliant)
 		// Useful for: timezone consistency, request timing, cache validation
-		"timestamp": time.Now().UTC().Format(time.RFC3339),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
 
 		// Complete list of available endpoints for API discovery
 		// Helps clients understand the available functionality without documentation
-		"endpoints": []string{
-			"GET /",        // This endpoint (self-reference)
-			"GET /health",  // Health check endpoint
-			"GET /metrics", // Metrics endpoint
-		},
+		Endpoints: endpoints,
+
+		// The same routes with description, authentication requirement and deprecation status
+		Routes: listed,
 	}
 
 	// Log the access for debugging and monitoring purposes
@@ -102,3 +140,39 @@ func (h *Handler) HandleRoot(c *gin.Context) {
 	// The response is serialized to JSON and sent to the client
 	c.JSON(http.StatusOK, response)
 }
+
+// APIInfoResponse is the body of GET /api/v2/. It replaces the v1 response
+// (RootResponse) and lists routes only as structured entries.
+type APIInfoResponse struct {
+	Version   string         `json:"version"`   // API version serving the request, e.g. "v2"
+	Timestamp string         `json:"timestamp"` // Current time in RFC3339, UTC
+	Routes    []routes.Route `json:"routes"`    // Routes of every API version, with deprecation and sunset
+}
+
+// APIInfoHandler returns the handler of the API information endpoint of
+// version. It lists the routes under /api, so clients see which versions
+// offer a route and when deprecated routes are removed.
+//
+// Parameters:
+//   - version: API version the endpoint belongs to, e.g. "v2"
+//
+// Returns:
+//   - gin.HandlerFunc: Handler answering with an APIInfoResponse
+func (h *Handler) APIInfoHandler(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		listed := []routes.Route{}
+		if h.routes != nil {
+			for _, r := range h.routes.Routes(routes.ListenerPublic) {
+				if r.Version != "" {
+					listed = append(listed, r)
+				}
+			}
+		}
+
+		c.JSON(http.StatusOK, APIInfoResponse{
+			Version:   version,
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Routes:    listed,
+		})
+	}
+}
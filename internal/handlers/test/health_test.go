@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/logger/logtest"
 )
 
 // TestHandleHealth tests the health check endpoint handler with comprehensive validation.
@@ -366,3 +367,24 @@ func TestHandleHealthResponseTime(t *testing.T) {
 		t.Errorf("Expected status code %d, got %d", http.StatusOK, w.Code)
 	}
 }
+
+// TestHandleHealth_LogsAccess verifies the health endpoint records a debug entry
+// with the client details used for access pattern analysis.
+func TestHandleHealth_LogsAccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := logtest.New(t)
+
+	router := gin.New()
+	router.GET("/health", handlers.NewHandler(rec.Logger()).HandleHealth)
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	req.Header.Set("User-Agent", "probe/1.0")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	rec.AssertLogged(
+		logtest.Level(slog.LevelDebug),
+		logtest.MessageContains("Health check endpoint accessed"),
+		logtest.Attr("user_agent", "probe/1.0"),
+		logtest.HasAttr("client_ip"),
+	)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/routes"
 )
 
 // TestHandleRoot tests the root endpoint handler with comprehensive validation.
@@ -36,8 +37,15 @@ func TestHandleRoot(t *testing.T) {
 		Level: slog.LevelDebug,
 	}))
 
-	// Create a new handler instance with the test logger
+	// Create a new handler instance with the test logger and a route registry
+	// holding the routes the listing is generated from
 	h := handlers.NewHandler(logger)
+	registry := routes.NewRegistry()
+	for _, path := range []string{"/", "/health", "/metrics"} {
+		registry.Add(routes.Route{Listener: routes.ListenerPublic, Method: http.MethodGet, Path: path, Auth: routes.AuthNone})
+	}
+	registry.Add(routes.Route{Listener: routes.ListenerAdmin, Method: http.MethodGet, Path: "/config", Auth: routes.AuthAdminToken})
+	h.SetRoutes(registry)
 
 	// Create a new Gin router for testing
 	router := gin.New()
@@ -109,7 +117,7 @@ func TestHandleRoot(t *testing.T) {
 	} else if endpointsList, ok := endpoints.([]interface{}); !ok {
 		t.Error("Field 'endpoints' should be an array")
 	} else {
-		// Verify expected endpoints are present
+		// Verify expected endpoints are present; admin routes are not listed
 		expectedEndpoints := []string{"GET /", "GET /health", "GET /metrics"}
 		if len(endpointsList) != len(expectedEndpoints) {
 			t.Errorf("Expected %d endpoints, got %d", len(expectedEndpoints), len(endpointsList))
@@ -0,0 +1,161 @@
+// Package idempotency makes retried requests safe by remembering the first
+// response sent for each Idempotency-Key.
+//
+// A client sends the same Idempotency-Key with every attempt of one logical
+// request. The first attempt claims the key and, once it has a response,
+// stores it; retries receive the stored response instead of running the
+// handler again. A retry with a different payload is a client error, and a
+// retry arriving while the first attempt is still running is told to try
+// again later. Keys are held in a Store, either in process memory
+// (MemoryStore) or in a shared MongoDB collection (MongoStore) so that all
+// application instances recognise a retry.
+//
+// Usage Examples:
+//
+//	manager := idempotency.New(idempotency.NewMemoryStore(), 24*time.Hour, time.Minute)
+//
+//	claim, record, err := manager.Begin(ctx, key, idempotency.Fingerprint(method, uri, body))
+//	switch {
+//	case err != nil:
+//	    // Store unavailable
+//	case record == nil:
+//	    // First attempt: run the handler, then claim.Complete(ctx, response) or claim.Release(ctx)
+//	case record.Fingerprint != fingerprint:
+//	    // Key reused for a different request
+//	case !record.Completed:
+//	    // First attempt still running
+//	default:
+//	    // Replay record.Response
+//	}
+package idempotency
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+)
+
+// ErrLockLost is returned by Claim.Complete when the claim expired and
+// another attempt took over the key.
+var ErrLockLost = errors.New("idempotency key claim expired")
+
+// Response is a response stored for replay.
+type Response struct {
+	Status int         // HTTP status code
+	Header http.Header // Headers set by the handler
+	Body   []byte      // Response body, uncompressed
+}
+
+// Record is the state of a known key.
+type Record struct {
+	Fingerprint string    // Fingerprint of the request that claimed the key
+	Completed   bool      // False while the first attempt is running
+	Response    Response  // Stored response; set when Completed
+	LockedUntil time.Time // When an unfinished claim may be taken over
+}
+
+// Store keeps idempotency keys. Implementations must be safe for concurrent use.
+type Store interface {
+	// Acquire claims key for the request with fingerprint under token. It
+	// returns nil when the claim succeeded: the key was unknown, or its
+	// previous claim with the same fingerprint ended without completing
+	// before lockTimeout. Otherwise it returns the key's record.
+	Acquire(ctx context.Context, key, fingerprint, token string, lockTimeout, ttl time.Duration) (*Record, error)
+
+	// Complete stores resp for key if token still holds the claim, and
+	// returns ErrLockLost otherwise.
+	Complete(ctx context.Context, key, token string, resp Response) error
+
+	// Release forgets key if token still holds the claim, so the request can
+	// be retried from scratch.
+	Release(ctx context.Context, key, token string) error
+}
+
+// OpenStore creates the store selected by cfg.Store. The MongoDB store needs
+// db; the memory store ignores it.
+func OpenStore(ctx context.Context, cfg config.ServerIdempotency, db *mongo.Database) (Store, error) {
+	switch strings.ToLower(cfg.Store) {
+	case "memory", "":
+		return NewMemoryStore(), nil
+	case "mongodb", "mongo":
+		if db == nil {
+			return nil, errors.New("idempotency store mongodb requires a database connection")
+		}
+		return NewMongoStore(ctx, db, cfg.Collection)
+	default:
+		return nil, fmt.Errorf("unknown idempotency store %q", cfg.Store)
+	}
+}
+
+// Fingerprint identifies a request by its method, request URI and body, so a
+// key reused for a different request can be told apart from a retry.
+func Fingerprint(method, requestURI string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + requestURI + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Manager claims keys in a Store with the configured lifetimes.
+type Manager struct {
+	store       Store
+	ttl         time.Duration
+	lockTimeout time.Duration
+}
+
+// New creates a Manager. Keys are remembered for ttl after their first
+// attempt; an attempt that neither completes nor releases its key within
+// lockTimeout (e.g. because the instance died) loses its claim to the next retry.
+func New(store Store, ttl, lockTimeout time.Duration) *Manager {
+	return &Manager{store: store, ttl: ttl, lockTimeout: lockTimeout}
+}
+
+// Claim is a successful claim of a key by one attempt.
+type Claim struct {
+	store Store
+	key   string
+	token string
+}
+
+// Begin claims key for the request with fingerprint. It returns a Claim when
+// this is the first attempt, or the key's record otherwise.
+func (m *Manager) Begin(ctx context.Context, key, fingerprint string) (*Claim, *Record, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	record, err := m.store.Acquire(ctx, key, fingerprint, token, m.lockTimeout, m.ttl)
+	if err != nil || record != nil {
+		return nil, record, err
+	}
+	return &Claim{store: m.store, key: key, token: token}, nil, nil
+}
+
+// Complete stores resp for replay to later attempts.
+func (c *Claim) Complete(ctx context.Context, resp Response) error {
+	return c.store.Complete(ctx, c.key, c.token, resp)
+}
+
+// Release gives the key up without storing a response.
+func (c *Claim) Release(ctx context.Context) error {
+	return c.store.Release(ctx, c.key, c.token)
+}
+
+// newToken returns a random claim token.
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate idempotency claim token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,93 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore discards expired keys.
+const sweepInterval = time.Minute
+
+// MemoryStore keeps keys in process memory. Each application instance knows
+// only the keys it has seen, so behind a load balancer a retry reaching
+// another instance runs again; use MongoStore there.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+}
+
+// memoryEntry is a key with its claim and expiry.
+type memoryEntry struct {
+	record  Record
+	token   string
+	expires time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]*memoryEntry), lastSweep: time.Now()}
+}
+
+// Acquire implements Store.
+func (s *MemoryStore) Acquire(_ context.Context, key, fingerprint, token string, lockTimeout, ttl time.Duration) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	e, ok := s.entries[key]
+	switch {
+	case !ok || now.After(e.expires):
+		s.entries[key] = &memoryEntry{
+			record:  Record{Fingerprint: fingerprint, LockedUntil: now.Add(lockTimeout)},
+			token:   token,
+			expires: now.Add(ttl),
+		}
+		return nil, nil
+	case !e.record.Completed && e.record.Fingerprint == fingerprint && now.After(e.record.LockedUntil):
+		// The previous attempt ran out of time; this retry takes over
+		e.token = token
+		e.record.LockedUntil = now.Add(lockTimeout)
+		return nil, nil
+	}
+	record := e.record
+	return &record, nil
+}
+
+// Complete implements Store.
+func (s *MemoryStore) Complete(_ context.Context, key, token string, resp Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok || e.token != token {
+		return ErrLockLost
+	}
+	e.record.Completed = true
+	e.record.Response = Response{Status: resp.Status, Header: resp.Header.Clone(), Body: append([]byte(nil), resp.Body...)}
+	return nil
+}
+
+// Release implements Store.
+func (s *MemoryStore) Release(_ context.Context, key, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok && e.token == token && !e.record.Completed {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// sweep removes expired keys. Callers must hold s.mu.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, key)
+		}
+	}
+	s.lastSweep = now
+}
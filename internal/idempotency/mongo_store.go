@@ -0,0 +1,148 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultCollection is the MongoDB collection used when none is configured.
+const DefaultCollection = "idempotency_keys"
+
+// Key states stored in MongoDB.
+const (
+	stateInProgress = "in_progress"
+	stateCompleted  = "completed"
+)
+
+// MongoStore keeps keys in a MongoDB collection shared by all application
+// instances, one document per key.
+//
+// Acquire is a single findAndModify with an update pipeline (MongoDB 4.2+),
+// so claiming a key is atomic on the server and lock expiry is judged by the
+// server clock ($$NOW). A TTL index removes keys once they expire.
+type MongoStore struct {
+	coll *mongo.Collection
+}
+
+// NewMongoStore prepares the idempotency collection in db, creating the TTL
+// index if needed. An empty collection name selects DefaultCollection.
+func NewMongoStore(ctx context.Context, db *mongo.Database, collection string) (*MongoStore, error) {
+	if collection == "" {
+		collection = DefaultCollection
+	}
+	coll := db.Collection(collection)
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0).SetName("expires_at_ttl"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create idempotency key expiry index: %w", err)
+	}
+	return &MongoStore{coll: coll}, nil
+}
+
+// mongoKey is a key document.
+type mongoKey struct {
+	Fingerprint string         `bson:"fingerprint"`
+	Token       string         `bson:"token"`
+	State       string         `bson:"state"`
+	LockedUntil time.Time      `bson:"locked_until"`
+	Response    *mongoResponse `bson:"response,omitempty"`
+}
+
+// mongoResponse is a stored response.
+type mongoResponse struct {
+	Status int                 `bson:"status"`
+	Header map[string][]string `bson:"header"`
+	Body   []byte              `bson:"body"`
+}
+
+// Acquire implements Store.
+func (s *MongoStore) Acquire(ctx context.Context, key, fingerprint, token string, lockTimeout, ttl time.Duration) (*Record, error) {
+	// A key can be claimed when it is new or expired, or when an earlier
+	// attempt of the same request ran past its lock without finishing
+	claimable := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$type", Value: "$state"}}, "missing"}}},
+		bson.D{{Key: "$lt", Value: bson.A{"$expires_at", "$$NOW"}}},
+		bson.D{{Key: "$and", Value: bson.A{
+			bson.D{{Key: "$eq", Value: bson.A{"$state", stateInProgress}}},
+			bson.D{{Key: "$eq", Value: bson.A{"$fingerprint", fingerprint}}},
+			bson.D{{Key: "$lt", Value: bson.A{"$locked_until", "$$NOW"}}},
+		}}},
+	}}}
+	ifClaimed := func(claimed, kept any) bson.D {
+		return bson.D{{Key: "$cond", Value: bson.A{"$claimed", claimed, kept}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{{Key: "claimed", Value: claimable}}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "fingerprint", Value: ifClaimed(fingerprint, "$fingerprint")},
+			{Key: "token", Value: ifClaimed(token, "$token")},
+			{Key: "state", Value: ifClaimed(stateInProgress, "$state")},
+			{Key: "locked_until", Value: ifClaimed(bson.D{{Key: "$add", Value: bson.A{"$$NOW", lockTimeout.Milliseconds()}}}, "$locked_until")},
+			{Key: "expires_at", Value: ifClaimed(bson.D{{Key: "$add", Value: bson.A{"$$NOW", ttl.Milliseconds()}}}, "$expires_at")},
+			{Key: "response", Value: ifClaimed("$$REMOVE", "$response")},
+		}}},
+		{{Key: "$unset", Value: "claimed"}},
+	}
+
+	var doc mongoKey
+	var err error
+	for attempt := 0; attempt < 3; attempt++ {
+		err = s.coll.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: key}}, pipeline,
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&doc)
+		if !mongo.IsDuplicateKeyError(err) {
+			break
+		}
+		// A concurrent attempt inserted the key first; evaluate its document instead
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+	if doc.Token == token {
+		return nil, nil
+	}
+
+	record := &Record{Fingerprint: doc.Fingerprint, Completed: doc.State == stateCompleted, LockedUntil: doc.LockedUntil}
+	if doc.Response != nil {
+		record.Response = Response{Status: doc.Response.Status, Header: http.Header(doc.Response.Header), Body: doc.Response.Body}
+	}
+	return record, nil
+}
+
+// Complete implements Store.
+func (s *MongoStore) Complete(ctx context.Context, key, token string, resp Response) error {
+	result, err := s.coll.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: key}, {Key: "token", Value: token}, {Key: "state", Value: stateInProgress}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "state", Value: stateCompleted},
+			{Key: "response", Value: mongoResponse{Status: resp.Status, Header: resp.Header, Body: resp.Body}},
+		}}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// Release implements Store.
+func (s *MongoStore) Release(ctx context.Context, key, token string) error {
+	_, err := s.coll.DeleteOne(ctx,
+		bson.D{{Key: "_id", Value: key}, {Key: "token", Value: token}, {Key: "state", Value: stateInProgress}},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
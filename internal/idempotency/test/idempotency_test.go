@@ -0,0 +1,125 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/idempotency"
+)
+
+// TestFingerprint tests that requests differing in method, URI or body get different fingerprints.
+func TestFingerprint(t *testing.T) {
+	base := idempotency.Fingerprint("POST", "/api/v1/enrollments", []byte(`{"course":1}`))
+	if base != idempotency.Fingerprint("POST", "/api/v1/enrollments", []byte(`{"course":1}`)) {
+		t.Error("Expected identical requests to share a fingerprint")
+	}
+	for _, other := range []string{
+		idempotency.Fingerprint("PATCH", "/api/v1/enrollments", []byte(`{"course":1}`)),
+		idempotency.Fingerprint("POST", "/api/v1/enrollments?dry_run=1", []byte(`{"course":1}`)),
+		idempotency.Fingerprint("POST", "/api/v1/enrollments", []byte(`{"course":2}`)),
+	} {
+		if other == base {
+			t.Error("Expected a different fingerprint")
+		}
+	}
+}
+
+// TestMemoryStore_Lifecycle tests claiming, completing, replaying and releasing keys.
+func TestMemoryStore_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	m := idempotency.New(idempotency.NewMemoryStore(), time.Hour, time.Minute)
+
+	claim, record, err := m.Begin(ctx, "k1", "fp")
+	if err != nil || claim == nil || record != nil {
+		t.Fatalf("Expected the first attempt to claim the key, got %v, %+v, %v", claim, record, err)
+	}
+
+	// A concurrent retry sees the claim in progress
+	if claim2, record, _ := m.Begin(ctx, "k1", "fp"); claim2 != nil || record == nil || record.Completed {
+		t.Fatalf("Expected an in-progress record, got %v, %+v", claim2, record)
+	}
+
+	resp := idempotency.Response{Status: http.StatusCreated, Header: http.Header{"Location": {"/x/1"}}, Body: []byte(`{"id":1}`)}
+	if err := claim.Complete(ctx, resp); err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+	_, record, _ = m.Begin(ctx, "k1", "fp")
+	if record == nil || !record.Completed || record.Response.Status != http.StatusCreated ||
+		string(record.Response.Body) != `{"id":1}` || record.Response.Header.Get("Location") != "/x/1" {
+		t.Fatalf("Expected the stored response, got %+v", record)
+	}
+
+	// Released keys can be claimed again
+	claim, _, _ = m.Begin(ctx, "k2", "fp")
+	if err := claim.Release(ctx); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	if claim, _, _ := m.Begin(ctx, "k2", "fp"); claim == nil {
+		t.Error("Expected a released key to be claimable")
+	}
+}
+
+// TestMemoryStore_LockTimeout tests that a stalled attempt loses its key to a retry.
+func TestMemoryStore_LockTimeout(t *testing.T) {
+	ctx := context.Background()
+	m := idempotency.New(idempotency.NewMemoryStore(), time.Hour, 10*time.Millisecond)
+
+	stalled, _, _ := m.Begin(ctx, "k", "fp")
+	time.Sleep(20 * time.Millisecond)
+
+	// Another request reusing the key does not take it over
+	if claim, record, _ := m.Begin(ctx, "k", "other"); claim != nil || record.Fingerprint != "fp" {
+		t.Fatalf("Expected the key to stay with its request, got %v, %+v", claim, record)
+	}
+	retry, _, _ := m.Begin(ctx, "k", "fp")
+	if retry == nil {
+		t.Fatal("Expected the retry to take over the stalled claim")
+	}
+	if err := stalled.Complete(ctx, idempotency.Response{Status: http.StatusOK}); !errors.Is(err, idempotency.ErrLockLost) {
+		t.Errorf("Expected ErrLockLost for the stalled attempt, got %v", err)
+	}
+	if err := retry.Complete(ctx, idempotency.Response{Status: http.StatusOK}); err != nil {
+		t.Errorf("Expected the retry to complete, got %v", err)
+	}
+}
+
+// TestMemoryStore_TTL tests that keys are forgotten after their TTL.
+func TestMemoryStore_TTL(t *testing.T) {
+	ctx := context.Background()
+	m := idempotency.New(idempotency.NewMemoryStore(), 10*time.Millisecond, time.Minute)
+
+	claim, _, _ := m.Begin(ctx, "k", "fp")
+	_ = claim.Complete(ctx, idempotency.Response{Status: http.StatusOK})
+	time.Sleep(20 * time.Millisecond)
+	if claim, _, _ := m.Begin(ctx, "k", "other"); claim == nil {
+		t.Error("Expected an expired key to be claimable")
+	}
+}
+
+// TestMemoryStore_Concurrent tests that exactly one of many simultaneous attempts claims a key.
+func TestMemoryStore_Concurrent(t *testing.T) {
+	m := idempotency.New(idempotency.NewMemoryStore(), time.Hour, time.Minute)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	claims := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if claim, _, _ := m.Begin(context.Background(), "k", "fp"); claim != nil {
+				mu.Lock()
+				claims++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if claims != 1 {
+		t.Errorf("Expected exactly one claim, got %d", claims)
+	}
+}
@@ -0,0 +1,115 @@
+// Package lifecycle coordinates graceful shutdown: ordered shutdown phases
+// sharing one deadline, and background workers that are stopped and awaited
+// as one of those phases.
+//
+// Phases are registered as resources are acquired and run in reverse order,
+// like deferred calls, so everything is released before what it depends on:
+// the database connection opened first is closed last.
+//
+// Usage Examples:
+//
+//	shutdown := lifecycle.NewShutdown(logger)
+//	shutdown.Add("database", func(ctx context.Context) error { return db.Close() })
+//	shutdown.Add("requests", httpServer.Shutdown)
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+//	defer cancel()
+//	err := shutdown.Run(ctx) // "requests", then "database"
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Phase releases one resource. It should return once ctx is done; a phase
+// that does not is abandoned, so later phases still get to run.
+type Phase func(ctx context.Context) error
+
+// namedPhase is a registered phase with the name used in logs and errors.
+type namedPhase struct {
+	name string
+	fn   Phase
+}
+
+// Shutdown runs registered phases in reverse order of registration, logging
+// the duration of each.
+type Shutdown struct {
+	logger *slog.Logger
+
+	mu     sync.Mutex   // Guards phases
+	phases []namedPhase // Registered phases, in registration order
+}
+
+// NewShutdown creates an empty shutdown sequence.
+func NewShutdown(logger *slog.Logger) *Shutdown {
+	return &Shutdown{logger: logger}
+}
+
+// Add registers a phase. It runs before every phase registered earlier.
+func (s *Shutdown) Add(name string, fn Phase) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.phases = append(s.phases, namedPhase{name: name, fn: fn})
+}
+
+// Run runs the registered phases, last registered first, and forgets them, so
+// a second call does nothing. Every phase runs even if an earlier one failed;
+// all share the deadline of ctx. Once ctx is done, phases still running are
+// abandoned and the remaining ones are started but not waited for.
+//
+// Returns:
+//   - error: The first phase error, prefixed with the phase name
+func (s *Shutdown) Run(ctx context.Context) error {
+	s.mu.Lock()
+	phases := s.phases
+	s.phases = nil
+	s.mu.Unlock()
+
+	start := time.Now()
+	var firstErr error
+	for i := len(phases) - 1; i >= 0; i-- {
+		p := phases[i]
+		phaseStart := time.Now()
+		err := runPhase(ctx, p.fn)
+		if err != nil {
+			s.logger.Error("❌ Shutdown phase failed",
+				slog.String("phase", p.name),                      // Resource being released
+				slog.Duration("duration", time.Since(phaseStart)), // Time spent before giving up
+				slog.String("error", err.Error()),                 // Why the phase did not complete
+			)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", p.name, err)
+			}
+			continue
+		}
+		s.logger.Info("⏱️ Shutdown phase completed",
+			slog.String("phase", p.name),                      // Resource released
+			slog.Duration("duration", time.Since(phaseStart)), // Time the phase took
+		)
+	}
+
+	if len(phases) > 0 {
+		s.logger.Info("⏱️ Shutdown sequence finished",
+			slog.Int("phases", len(phases)),              // Number of phases run
+			slog.Duration("duration", time.Since(start)), // Total time across all phases
+			slog.Bool("clean", firstErr == nil),          // False if any phase failed or timed out
+		)
+	}
+	return firstErr
+}
+
+// runPhase runs fn and waits for it until ctx is done.
+func runPhase(ctx context.Context, fn Phase) error {
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("abandoned: %w", ctx.Err())
+	}
+}
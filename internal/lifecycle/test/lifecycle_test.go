@@ -0,0 +1,123 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/lifecycle"
+)
+
+// newLogger returns a logger discarding all output.
+func newLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// TestShutdown_ReverseOrder tests that phases run last registered first and that failures do not stop later phases.
+func TestShutdown_ReverseOrder(t *testing.T) {
+	shutdown := lifecycle.NewShutdown(newLogger())
+	var order []string
+	phase := func(name string, err error) lifecycle.Phase {
+		return func(context.Context) error {
+			order = append(order, name)
+			return err
+		}
+	}
+	shutdown.Add("database", phase("database", nil))
+	shutdown.Add("audit", phase("audit", errors.New("disk full")))
+	shutdown.Add("requests", phase("requests", nil))
+
+	err := shutdown.Run(context.Background())
+	if want := []string{"requests", "audit", "database"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Expected phases in order %v, got %v", want, order)
+	}
+	if err == nil || err.Error() != "audit: disk full" {
+		t.Errorf("Expected the failed phase named in the error, got %v", err)
+	}
+
+	order = nil
+	if err := shutdown.Run(context.Background()); err != nil || order != nil {
+		t.Errorf("Expected a second Run to do nothing, got %v after %v", err, order)
+	}
+}
+
+// TestShutdown_Deadline tests that a phase ignoring its context is abandoned at the shared deadline.
+func TestShutdown_Deadline(t *testing.T) {
+	shutdown := lifecycle.NewShutdown(newLogger())
+	lastRan := make(chan struct{})
+	shutdown.Add("database", func(context.Context) error {
+		close(lastRan)
+		return nil
+	})
+	shutdown.Add("stuck", func(context.Context) error {
+		time.Sleep(time.Minute)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := shutdown.Run(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.HasPrefix(err.Error(), "stuck: ") {
+		t.Errorf("Expected the stuck phase to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected Run to return at the deadline, took %v", elapsed)
+	}
+	select {
+	case <-lastRan:
+	case <-time.After(time.Second):
+		t.Error("Expected later phases to be started after a phase was abandoned")
+	}
+}
+
+// TestWorkers_Stop tests that Stop cancels workers, waits for them and refuses new ones.
+func TestWorkers_Stop(t *testing.T) {
+	workers := lifecycle.NewWorkers(newLogger())
+	finished := make(chan struct{})
+	if err := workers.Go("cleanup", func(ctx context.Context) {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond) // Finishing the current batch
+		close(finished)
+	}); err != nil {
+		t.Fatalf("Go failed: %v", err)
+	}
+	if n := workers.Running(); n != 1 {
+		t.Errorf("Expected 1 running worker, got %d", n)
+	}
+
+	if err := workers.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Error("Expected Stop to wait for the worker to return")
+	}
+	if n := workers.Running(); n != 0 {
+		t.Errorf("Expected no running workers after Stop, got %d", n)
+	}
+	if err := workers.Go("late", func(context.Context) {}); !errors.Is(err, lifecycle.ErrStopped) {
+		t.Errorf("Expected ErrStopped after Stop, got %v", err)
+	}
+}
+
+// TestWorkers_StopTimeout tests that Stop names workers still running at the deadline.
+func TestWorkers_StopTimeout(t *testing.T) {
+	workers := lifecycle.NewWorkers(newLogger())
+	release := make(chan struct{})
+	defer close(release)
+	workers.Go("reindex", func(context.Context) { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := workers.Stop(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "reindex") {
+		t.Errorf("Expected a timeout naming the reindex worker, got %v", err)
+	}
+}
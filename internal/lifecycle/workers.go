@@ -0,0 +1,120 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+)
+
+// ErrStopped is returned by Workers.Go once Stop has been called.
+var ErrStopped = errors.New("workers are stopping")
+
+// Workers tracks background goroutines so shutdown can cancel them and wait
+// until they have returned, instead of cutting them off mid-operation when
+// the process exits.
+type Workers struct {
+	logger *slog.Logger
+	ctx    context.Context    // Passed to every worker; canceled by Stop
+	cancel context.CancelFunc // Cancels ctx
+
+	mu      sync.Mutex     // Guards running and stopped
+	running map[string]int // Number of running workers per name
+	stopped bool           // Set by Stop; no workers are started afterwards
+	wg      sync.WaitGroup // Counts running workers
+}
+
+// NewWorkers creates an empty worker group.
+func NewWorkers(logger *slog.Logger) *Workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Workers{
+		logger:  logger,
+		ctx:     ctx,
+		cancel:  cancel,
+		running: make(map[string]int),
+	}
+}
+
+// Go runs fn in a new goroutine. fn must return soon after its context is
+// canceled, which happens when shutdown reaches the workers phase.
+//
+// Parameters:
+//   - name: Identifies the worker in logs, e.g. "session-cleanup"
+//   - fn: Worker body
+//
+// Returns:
+//   - error: ErrStopped if shutdown has already begun; fn is not run
+func (w *Workers) Go(name string, fn func(ctx context.Context)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stopped {
+		return ErrStopped
+	}
+	w.running[name]++
+	w.wg.Add(1)
+	go func() {
+		defer func() {
+			w.mu.Lock()
+			w.running[name]--
+			if w.running[name] == 0 {
+				delete(w.running, name)
+			}
+			w.mu.Unlock()
+			w.wg.Done()
+		}()
+		fn(w.ctx)
+	}()
+	return nil
+}
+
+// Running returns the number of workers that have not returned yet.
+func (w *Workers) Running() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	total := 0
+	for _, n := range w.running {
+		total += n
+	}
+	return total
+}
+
+// Stop cancels the context of every worker and waits until all have
+// returned or ctx is done. It has the signature of a shutdown Phase.
+//
+// Returns:
+//   - error: Names of the workers still running when ctx was done
+func (w *Workers) Stop(ctx context.Context) error {
+	w.mu.Lock()
+	w.stopped = true
+	count := 0
+	for _, n := range w.running {
+		count += n
+	}
+	w.mu.Unlock()
+
+	w.logger.Info("🧵 Stopping background workers",
+		slog.Int("running", count), // Workers being canceled
+	)
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		w.mu.Lock()
+		names := make([]string, 0, len(w.running))
+		for name := range w.running {
+			names = append(names, name)
+		}
+		w.mu.Unlock()
+		sort.Strings(names)
+		return fmt.Errorf("workers still running %v: %w", names, ctx.Err())
+	}
+}
@@ -0,0 +1,134 @@
+// Package loadshed limits the number of requests processed concurrently,
+// adapting the limit to the latency the server achieves.
+//
+// The Limiter follows additive-increase/multiplicative-decrease (AIMD), the
+// scheme TCP uses to find the capacity of a network path:
+//   - While the smoothed latency stays within the target and at least half of
+//     the limit is in use, each completed request raises the limit by
+//     1/limit, i.e. by one per "round" of requests
+//   - When the smoothed latency exceeds the target, or a request ends
+//     overloaded (e.g. it timed out), the limit is multiplied by 0.9, at most
+//     once per target interval so one burst of slow requests counts once
+//
+// Requests arriving while the limit is reached are rejected immediately, so
+// excess load is turned away cheaply instead of queueing until every request
+// is slow.
+//
+// Usage Examples:
+//
+//	limiter := loadshed.New(loadshed.Config{InitialLimit: 100, MinLimit: 10, MaxLimit: 1000, LatencyTarget: 500 * time.Millisecond})
+//
+//	if !limiter.Acquire() {
+//	    // Reply 503 with Retry-After
+//	}
+//	start := time.Now()
+//	serve()
+//	limiter.Release(time.Since(start), timedOut)
+package loadshed
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Algorithm constants.
+const (
+	backoffRatio  = 0.9 // Multiplicative decrease
+	latencyWeight = 0.1 // Weight of a new sample in the smoothed latency
+)
+
+// Config bounds and tunes a Limiter.
+type Config struct {
+	InitialLimit  int           // Starting concurrency limit
+	MinLimit      int           // Lowest limit; keeps the server serving under sustained overload
+	MaxLimit      int           // Highest limit
+	LatencyTarget time.Duration // Smoothed latency above which the limit shrinks
+}
+
+// Stats are the gauges and counters of a Limiter.
+type Stats struct {
+	Limit    int           // Current concurrency limit
+	InFlight int           // Requests being processed
+	Accepted uint64        // Requests admitted since startup
+	Rejected uint64        // Requests shed since startup
+	Latency  time.Duration // Smoothed request latency
+}
+
+// Limiter is an adaptive concurrency limit. It is safe for concurrent use.
+type Limiter struct {
+	cfg Config
+
+	mu           sync.Mutex
+	limit        float64
+	inFlight     int
+	accepted     uint64
+	rejected     uint64
+	latency      time.Duration // Exponentially weighted moving average
+	lastDecrease time.Time
+}
+
+// New creates a Limiter. Limits are clamped so that
+// 1 <= MinLimit <= InitialLimit <= MaxLimit.
+func New(cfg Config) *Limiter {
+	cfg.MinLimit = max(cfg.MinLimit, 1)
+	cfg.MaxLimit = max(cfg.MaxLimit, cfg.MinLimit)
+	cfg.InitialLimit = min(max(cfg.InitialLimit, cfg.MinLimit), cfg.MaxLimit)
+	return &Limiter{cfg: cfg, limit: float64(cfg.InitialLimit)}
+}
+
+// Acquire admits a request if fewer than limit requests are in flight. Every
+// admitted request must be followed by exactly one Release.
+func (l *Limiter) Acquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight >= int(l.limit) {
+		l.rejected++
+		return false
+	}
+	l.inFlight++
+	l.accepted++
+	return true
+}
+
+// Release ends an admitted request that took latency, and adapts the limit.
+// overloaded reports that the request failed because the server was
+// overloaded, such as a handler timeout.
+func (l *Limiter) Release(latency time.Duration, overloaded bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	inFlight := l.inFlight
+	l.inFlight--
+
+	if l.latency == 0 {
+		l.latency = latency
+	} else {
+		l.latency = time.Duration(float64(l.latency)*(1-latencyWeight) + float64(latency)*latencyWeight)
+	}
+
+	now := time.Now()
+	switch {
+	case overloaded || l.latency > l.cfg.LatencyTarget:
+		if now.Sub(l.lastDecrease) >= l.cfg.LatencyTarget {
+			l.limit = math.Max(float64(l.cfg.MinLimit), l.limit*backoffRatio)
+			l.lastDecrease = now
+		}
+	case inFlight*2 >= int(l.limit):
+		// Grow only while the limit is actually used, so idle periods do not inflate it
+		l.limit = math.Min(float64(l.cfg.MaxLimit), l.limit+1/l.limit)
+	}
+}
+
+// Stats returns the current gauges and counters.
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return Stats{
+		Limit:    int(l.limit),
+		InFlight: l.inFlight,
+		Accepted: l.accepted,
+		Rejected: l.rejected,
+		Latency:  l.latency,
+	}
+}
@@ -0,0 +1,115 @@
+package loadshed_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/loadshed"
+)
+
+// TestLimiter_RejectsAtLimit tests that requests beyond the limit are rejected and counted.
+func TestLimiter_RejectsAtLimit(t *testing.T) {
+	l := loadshed.New(loadshed.Config{InitialLimit: 2, MinLimit: 1, MaxLimit: 10, LatencyTarget: time.Second})
+
+	if !l.Acquire() || !l.Acquire() {
+		t.Fatal("Expected requests within the limit to be admitted")
+	}
+	if l.Acquire() {
+		t.Fatal("Expected a request beyond the limit to be rejected")
+	}
+	l.Release(time.Millisecond, false)
+	if !l.Acquire() {
+		t.Error("Expected a request to be admitted after a release")
+	}
+
+	stats := l.Stats()
+	if stats.InFlight != 2 || stats.Accepted != 3 || stats.Rejected != 1 {
+		t.Errorf("Expected 2 in flight, 3 accepted and 1 rejected, got %+v", stats)
+	}
+}
+
+// TestLimiter_GrowsUnderFastLoad tests that the limit rises while it is used and latency is low.
+func TestLimiter_GrowsUnderFastLoad(t *testing.T) {
+	l := loadshed.New(loadshed.Config{InitialLimit: 4, MinLimit: 1, MaxLimit: 5, LatencyTarget: time.Second})
+
+	for range 100 {
+		for l.Acquire() {
+		}
+		l.Release(time.Millisecond, false)
+	}
+	if got := l.Stats().Limit; got != 5 {
+		t.Errorf("Expected the limit to grow to the maximum 5, got %d", got)
+	}
+}
+
+// TestLimiter_IdleDoesNotGrow tests that a mostly idle limiter keeps its limit.
+func TestLimiter_IdleDoesNotGrow(t *testing.T) {
+	l := loadshed.New(loadshed.Config{InitialLimit: 10, MinLimit: 1, MaxLimit: 100, LatencyTarget: time.Second})
+
+	for range 100 {
+		l.Acquire()
+		l.Release(time.Millisecond, false)
+	}
+	if got := l.Stats().Limit; got != 10 {
+		t.Errorf("Expected the limit to stay at 10, got %d", got)
+	}
+}
+
+// TestLimiter_BacksOff tests that slow or overloaded requests shrink the limit down to the minimum.
+func TestLimiter_BacksOff(t *testing.T) {
+	tests := []struct {
+		name       string
+		latency    time.Duration
+		overloaded bool
+	}{
+		{"slow", 50 * time.Millisecond, false},
+		{"overloaded", time.Microsecond, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := loadshed.New(loadshed.Config{InitialLimit: 20, MinLimit: 15, MaxLimit: 100, LatencyTarget: time.Millisecond})
+
+			l.Acquire()
+			l.Release(tt.latency, tt.overloaded)
+			if got := l.Stats().Limit; got != 18 {
+				t.Fatalf("Expected the limit to drop to 18, got %d", got)
+			}
+
+			// Further slow requests within the same target interval count once
+			l.Acquire()
+			l.Release(tt.latency, tt.overloaded)
+			if got := l.Stats().Limit; got != 18 {
+				t.Fatalf("Expected one decrease per interval, got limit %d", got)
+			}
+
+			for range 10 {
+				time.Sleep(2 * time.Millisecond)
+				l.Acquire()
+				l.Release(tt.latency, tt.overloaded)
+			}
+			if got := l.Stats().Limit; got != 15 {
+				t.Errorf("Expected the limit to stop at the minimum 15, got %d", got)
+			}
+		})
+	}
+}
+
+// TestNew_ClampsLimits tests that inconsistent limits are corrected.
+func TestNew_ClampsLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  loadshed.Config
+		want int
+	}{
+		{"zero", loadshed.Config{}, 1},
+		{"below minimum", loadshed.Config{InitialLimit: 2, MinLimit: 5, MaxLimit: 10}, 5},
+		{"above maximum", loadshed.Config{InitialLimit: 50, MinLimit: 5, MaxLimit: 10}, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loadshed.New(tt.cfg).Stats().Limit; got != tt.want {
+				t.Errorf("Expected initial limit %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OverflowPolicy decides what an AsyncHandler does when its queue is full.
+type OverflowPolicy string
+
+const (
+	// OverflowBlock makes the logging goroutine wait for free space. No record is
+	// lost, but a slow output slows down request handling (backpressure).
+	OverflowBlock OverflowPolicy = "block"
+
+	// OverflowDropNewest discards the incoming record.
+	OverflowDropNewest OverflowPolicy = "drop_newest"
+
+	// OverflowDropDebugFirst discards debug records first: an incoming debug record
+	// is dropped, otherwise the oldest queued debug record is evicted to make room.
+	// When no debug record is queued the incoming record is dropped.
+	OverflowDropDebugFirst OverflowPolicy = "drop_debug_first"
+)
+
+// defaultAsyncBufferSize is the queue capacity used when none is configured.
+const defaultAsyncBufferSize = 4096
+
+// AsyncStats reports the state of an AsyncHandler queue.
+type AsyncStats struct {
+	QueueDepth int            // Records currently waiting to be written
+	Capacity   int            // Maximum queue length
+	Policy     OverflowPolicy // Active overflow policy
+	Processed  uint64         // Records written by the worker
+	Dropped    uint64         // Records discarded by the overflow policy
+	Blocked    uint64         // Times a caller had to wait for space (OverflowBlock)
+}
+
+// asyncItem is a queued record together with the handler clone that produced it,
+// so WithAttrs/WithGroup state is preserved when the worker writes it.
+type asyncItem struct {
+	handler slog.Handler
+	ctx     context.Context
+	record  slog.Record
+}
+
+// asyncQueue is the bounded queue and worker shared by an AsyncHandler and its clones.
+type asyncQueue struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	items    []asyncItem
+	capacity int
+	policy   OverflowPolicy
+	closed   bool
+	done     chan struct{}
+	progress chan struct{} // Closed and replaced each time the worker finishes a batch
+
+	enqueued  uint64
+	processed uint64
+	dropped   uint64
+	blocked   uint64
+}
+
+// AsyncHandler is a slog.Handler wrapper that moves writes off the calling
+// goroutine. Records are placed in a bounded queue and written in order by a
+// single background worker.
+//
+// Enabled is evaluated synchronously, so filtered records cost nothing. After
+// Close the handler writes synchronously, so shutdown logging is never lost.
+type AsyncHandler struct {
+	next slog.Handler
+	q    *asyncQueue
+}
+
+// NewAsyncHandler wraps next with an asynchronous bounded queue.
+//
+// Args:
+//
+//	next: Handler that performs the actual write
+//	bufferSize: Queue capacity (defaults to 4096 when <= 0)
+//	policy: Overflow policy (defaults to OverflowBlock when empty or unknown)
+//
+// Example:
+//
+//	h := NewAsyncHandler(slog.NewJSONHandler(os.Stdout, nil), 8192, OverflowDropDebugFirst)
+//	defer h.Close(context.Background())
+func NewAsyncHandler(next slog.Handler, bufferSize int, policy OverflowPolicy) *AsyncHandler {
+	if bufferSize <= 0 {
+		bufferSize = defaultAsyncBufferSize
+	}
+	switch policy {
+	case OverflowDropNewest, OverflowDropDebugFirst:
+	default:
+		policy = OverflowBlock
+	}
+	q := &asyncQueue{
+		items:    make([]asyncItem, 0, bufferSize),
+		capacity: bufferSize,
+		policy:   policy,
+		done:     make(chan struct{}),
+		progress: make(chan struct{}),
+	}
+	q.notEmpty = sync.NewCond(&q.mu)
+	q.notFull = sync.NewCond(&q.mu)
+	go q.run()
+	return &AsyncHandler{next: next, q: q}
+}
+
+// ParseOverflowPolicy converts a configured policy name, accepting "-" or "_"
+// separators. Unknown names yield OverflowBlock, the only lossless policy.
+func ParseOverflowPolicy(name string) OverflowPolicy {
+	switch p := OverflowPolicy(strings.ReplaceAll(strings.ToLower(name), "-", "_")); p {
+	case OverflowDropNewest, OverflowDropDebugFirst:
+		return p
+	default:
+		return OverflowBlock
+	}
+}
+
+// Enabled implements slog.Handler by delegating to the wrapped handler.
+func (h *AsyncHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+// Enqueues the record according to the overflow policy and returns immediately
+// (or once space is available under OverflowBlock).
+func (h *AsyncHandler) Handle(ctx context.Context, r slog.Record) error {
+	item := asyncItem{handler: h.next, ctx: context.WithoutCancel(ctx), record: r.Clone()}
+	if !h.q.enqueue(item) {
+		// Queue closed: write synchronously so late shutdown logs still appear.
+		return h.next.Handle(ctx, r)
+	}
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *AsyncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &AsyncHandler{next: h.next.WithAttrs(attrs), q: h.q}
+}
+
+// WithGroup implements slog.Handler.
+func (h *AsyncHandler) WithGroup(name string) slog.Handler {
+	return &AsyncHandler{next: h.next.WithGroup(name), q: h.q}
+}
+
+// Stats returns a snapshot of the queue counters.
+func (h *AsyncHandler) Stats() AsyncStats {
+	q := h.q
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return AsyncStats{
+		QueueDepth: len(q.items),
+		Capacity:   q.capacity,
+		Policy:     q.policy,
+		Processed:  q.processed,
+		Dropped:    q.dropped,
+		Blocked:    q.blocked,
+	}
+}
+
+// Flush waits until every record queued before the call has been written.
+func (h *AsyncHandler) Flush(ctx context.Context) error {
+	q := h.q
+	q.mu.Lock()
+	target := q.enqueued
+	for q.processed < target {
+		progress := q.progress
+		q.mu.Unlock()
+		select {
+		case <-progress:
+		case <-q.done:
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("async log queue not flushed: %w", ctx.Err())
+		}
+		q.mu.Lock()
+	}
+	q.mu.Unlock()
+	return nil
+}
+
+// Close drains the queue and stops the worker. Subsequent records are written
+// synchronously. Close is safe to call more than once.
+func (h *AsyncHandler) Close(ctx context.Context) error {
+	q := h.q
+	q.mu.Lock()
+	q.closed = true
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("async log queue not drained: %w", ctx.Err())
+	}
+}
+
+// enqueue applies the overflow policy. It returns false if the queue is closed.
+func (q *asyncQueue) enqueue(item asyncItem) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return false
+	}
+	if len(q.items) >= q.capacity {
+		switch q.policy {
+		case OverflowBlock:
+			q.blocked++
+			for len(q.items) >= q.capacity && !q.closed {
+				q.notFull.Wait()
+			}
+			if q.closed {
+				return false
+			}
+		case OverflowDropDebugFirst:
+			if item.record.Level < slog.LevelInfo || !q.evictDebug() {
+				q.dropped++
+				return true
+			}
+		default: // OverflowDropNewest
+			q.dropped++
+			return true
+		}
+	}
+
+	q.items = append(q.items, item)
+	q.enqueued++
+	q.notEmpty.Signal()
+	return true
+}
+
+// evictDebug removes the oldest queued debug record. Callers must hold q.mu.
+func (q *asyncQueue) evictDebug() bool {
+	for i, it := range q.items {
+		if it.record.Level < slog.LevelInfo {
+			q.items = append(q.items[:i], q.items[i+1:]...)
+			q.dropped++
+			// The evicted record will never be processed; keep Flush targets reachable.
+			q.enqueued--
+			return true
+		}
+	}
+	return false
+}
+
+// run writes queued records in FIFO order until the queue is closed and empty.
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	var batch []asyncItem
+	for {
+		q.mu.Lock()
+		for len(q.items) == 0 && !q.closed {
+			q.notEmpty.Wait()
+		}
+		if len(q.items) == 0 && q.closed {
+			q.mu.Unlock()
+			return
+		}
+		// Swap the queue out so producers can continue while the batch is written.
+		batch, q.items = q.items, batch[:0]
+		q.notFull.Broadcast()
+		q.mu.Unlock()
+
+		for _, it := range batch {
+			_ = it.handler.Handle(it.ctx, it.record)
+		}
+
+		q.mu.Lock()
+		q.processed += uint64(len(batch))
+		close(q.progress)
+		q.progress = make(chan struct{})
+		q.mu.Unlock()
+	}
+}
+
+// asyncCloseTimeout bounds how long a replaced async handler may take to drain.
+const asyncCloseTimeout = 5 * time.Second
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+)
+
+// Custom log levels extending slog's debug/info/warn/error scale.
+//
+// slog leaves gaps of four between its levels, so TRACE and FATAL sit one
+// step beyond DEBUG and ERROR and compare naturally with the built-in levels.
+const (
+	// LevelTrace is for wire-level detail (e.g. individual MongoDB commands)
+	// that is too noisy even for debug output.
+	LevelTrace = slog.Level(-8)
+
+	// LevelFatal is for unrecoverable conditions. Records at this level are
+	// normally emitted through Fatal, which flushes all outputs and exits.
+	LevelFatal = slog.Level(12)
+)
+
+// fatalExitCode is the process exit status used by Fatal.
+const fatalExitCode = 1
+
+// fatalFlushTimeout bounds how long Fatal waits for async queues and sinks to drain.
+const fatalFlushTimeout = 5 * time.Second
+
+// LevelName returns the display name of level, including the custom TRACE and
+// FATAL names. Levels between the named ones are shown relative to the nearest
+// lower name (e.g. "TRACE+2", "FATAL+4"), matching slog's convention.
+func LevelName(level slog.Level) string {
+	switch {
+	case level < slog.LevelDebug:
+		return relativeLevelName("TRACE", level-LevelTrace)
+	case level >= LevelFatal:
+		return relativeLevelName("FATAL", level-LevelFatal)
+	default:
+		return level.String()
+	}
+}
+
+func relativeLevelName(name string, offset slog.Level) string {
+	if offset == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s%+d", name, offset)
+}
+
+// replaceLevelName is a slog.HandlerOptions.ReplaceAttr function that writes
+// TRACE and FATAL level names in the JSON and text handlers instead of
+// "DEBUG-4" and "ERROR+4".
+func replaceLevelName(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.LevelKey {
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(LevelName(level))
+		}
+	}
+	return a
+}
+
+// Level returns the minimum level of the logger configured by ConfigureLogger.
+func Level() slog.Level {
+	return levelVar.Level()
+}
+
+// SetLevel changes the minimum level of the configured logger at runtime,
+// including remote sinks without their own level. The change lasts until the
+// next ConfigureLogger call.
+//
+// Example:
+//
+//	logger.SetLevel(logger.ParseLevel("debug")) // Temporarily investigate an incident
+func SetLevel(level slog.Level) {
+	levelVar.Set(level)
+}
+
+// Trace logs msg at LevelTrace through the singleton logger.
+//
+// Example:
+//
+//	logger.Trace("🍃 MongoDB command started", "command", "find", "request_id", 42)
+func Trace(msg string, args ...any) {
+	logAt(context.Background(), LevelTrace, msg, args...)
+}
+
+// TraceContext logs msg at LevelTrace with ctx through the singleton logger.
+func TraceContext(ctx context.Context, msg string, args ...any) {
+	logAt(ctx, LevelTrace, msg, args...)
+}
+
+// Fatal logs msg at LevelFatal, flushes the async queue and remote sinks, and
+// terminates the process with exit status 1. Deferred functions do not run.
+//
+// Example:
+//
+//	if err := httpServer.Start(); err != nil {
+//	    logger.Fatal("❌ Failed to start HTTP server", slog.Any("error", err))
+//	}
+func Fatal(msg string, args ...any) {
+	FatalContext(context.Background(), msg, args...)
+}
+
+// FatalContext is Fatal with a context for the log record.
+func FatalContext(ctx context.Context, msg string, args ...any) {
+	logAt(ctx, LevelFatal, msg, args...)
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), fatalFlushTimeout)
+	defer cancel()
+	if err := Shutdown(flushCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to flush logs before exit: %v\n", err)
+	}
+	os.Exit(fatalExitCode)
+}
+
+// logAt emits a record through the singleton logger with the source location
+// of the helper's caller rather than of the helper itself.
+func logAt(ctx context.Context, level slog.Level, msg string, args ...any) {
+	l := GetLogger()
+	if !l.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // Skip runtime.Callers, logAt and the exported helper
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(args...)
+	_ = l.Handler().Handle(ctx, r)
+}
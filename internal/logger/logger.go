@@ -26,9 +26,14 @@
 package logger
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
 )
@@ -45,8 +50,26 @@ var (
 	// Uses RWMutex to allow concurrent reads (GetLogger) while serializing writes (configuration).
 	// This design optimizes for the common case of frequent logger access with infrequent reconfiguration.
 	mu sync.RWMutex
+
+	// sinks holds the remote shipping handlers created by the last ConfigureLogger call.
+	// They are flushed and closed by Shutdown or when the logger is reconfigured.
+	// Access is protected by mu.
+	sinks []*ShippingHandler
+
+	// async holds the asynchronous wrapper around the local output handler when
+	// config.Async.Enabled is set, so it can be drained on reconfiguration and Shutdown.
+	// Access is protected by mu.
+	async *AsyncHandler
+
+	// levelVar holds the minimum level of the configured logger. Handlers built by
+	// ConfigureLogger read it on every record, so SetLevel takes effect immediately
+	// without rebuilding the handler chain.
+	levelVar = new(slog.LevelVar)
 )
 
+// sinkCloseTimeout bounds how long retired sinks may spend draining after reconfiguration.
+const sinkCloseTimeout = 10 * time.Second
+
 // InitializeBootstrapLogger creates and configures the initial logger instance for early
 // application startup before full configuration is available. This bootstrap logger provides
 // essential logging capabilities during the critical application initialization phase.
@@ -117,6 +140,12 @@ func InitializeBootstrapLogger() *slog.Logger {
 	mu.Lock()
 	defer mu.Unlock()
 
+	return initializeBootstrapLocked()
+}
+
+// initializeBootstrapLocked builds the bootstrap logger and installs it as the
+// singleton. Callers must hold mu for writing.
+func initializeBootstrapLocked() *slog.Logger {
 	// Detect current environment to determine appropriate bootstrap configuration
 	// This allows the bootstrap logger to adapt its behavior based on deployment context
 	isProduction := os.Getenv("ENVIRONMENT") == "production"
@@ -137,6 +166,9 @@ func InitializeBootstrapLogger() *slog.Logger {
 			// This reduces log volume and focuses on actionable issues
 			// Lower-level logs (debug, info, warn) are filtered out for performance
 			Level: slog.LevelError,
+
+			// ReplaceAttr: Use the TRACE/FATAL names for custom levels
+			ReplaceAttr: replaceLevelName,
 		}
 	} else {
 		// Development/other environments: Optimize for visibility and debugging
@@ -150,6 +182,9 @@ func InitializeBootstrapLogger() *slog.Logger {
 			// This ensures all log messages are visible during development
 			// Includes debug, info, warn, and error level messages
 			Level: slog.LevelDebug,
+
+			// ReplaceAttr: Use the TRACE/FATAL names for custom levels
+			ReplaceAttr: replaceLevelName,
 		}
 	}
 
@@ -196,10 +231,12 @@ func InitializeBootstrapLogger() *slog.Logger {
 // the transition from bootstrap to configured logging.
 //
 // Supported Log Levels:
-// - "debug": Detailed debugging information (highest verbosity)
+// - "trace": Wire-level detail such as individual MongoDB commands (highest verbosity)
+// - "debug": Detailed debugging information
 // - "info": General informational messages (default, balanced approach)
 // - "warn": Warning messages about potentially problematic conditions
-// - "error": Error messages about failures (lowest verbosity, highest performance)
+// - "error": Error messages about failures
+// - "fatal": Unrecoverable conditions only (lowest verbosity); see Fatal
 //
 // Supported Output Formats:
 // - "json": Structured JSON output for log aggregation systems and automated processing
@@ -214,6 +251,24 @@ func InitializeBootstrapLogger() *slog.Logger {
 //   - Best for: Local development, debugging, enhanced readability
 //   - Characteristics: Color-coded, well-formatted, maximum developer experience
 //
+// Sensitive Data Redaction:
+// Whatever the format, the handler is wrapped in a RedactingHandler (see
+// redact_handler.go) configured from config.Redaction. Credential-like keys and
+// values such as JWTs, Mongo URI passwords and e-mail addresses are masked or
+// hashed before output. Redaction is only skipped when config.Redaction.Disabled is set.
+//
+// Remote Log Sinks:
+// Each entry in config.Sinks adds an asynchronous shipping handler (syslog, GELF or
+// OTLP) alongside the local handler. Sinks from a previous configuration are drained
+// and closed in the background; call Shutdown before exit to flush the current ones.
+// A sink that cannot be created is skipped with a warning rather than failing startup.
+//
+// Asynchronous Output:
+// With config.Async.Enabled the local handler is wrapped in an AsyncHandler whose
+// bounded queue is written by a background goroutine. config.Async.OverflowPolicy
+// chooses between blocking, dropping the newest record or dropping debug records first
+// when the queue is full. Shutdown drains the queue.
+//
 // Thread Safety:
 // The function acquires a write lock on the singleton mutex to ensure thread-safe
 // reconfiguration. This prevents race conditions during logger replacement and
@@ -266,38 +321,25 @@ func ConfigureLogger(config config.Logger) {
 
 	// Convert string log level to slog.Level enum with safe fallback
 	// This mapping provides type safety and validation for configuration values
-	var level slog.Level
-	switch config.Level {
-	case "debug":
-		// Debug level: Maximum verbosity for development and troubleshooting
-		// Includes all log messages (debug, info, warn, error)
-		level = slog.LevelDebug
-	case "warn":
-		// Warning level: Moderate verbosity focusing on potential issues
-		// Includes warn and error messages, excludes debug and info
-		level = slog.LevelWarn
-	case "error":
-		// Error level: Minimum verbosity for production performance
-		// Includes only error messages, excludes debug, info, and warn
-		level = slog.LevelError
-	default:
-		// Default to info level for balanced visibility and performance
-		// Includes info, warn, and error messages, excludes debug
-		// This is the recommended level for most production environments
-		level = slog.LevelInfo
-	}
+	level := ParseLevel(config.Level)
+	levelVar.Set(level)
 
 	// Configure handler options based on loaded configuration
 	// These options control logging behavior and output characteristics
 	opts := &slog.HandlerOptions{
 		// Level: Set the minimum log level for message filtering
 		// Messages below this level are discarded for performance
-		Level: level,
+		// The shared LevelVar lets SetLevel change it at runtime
+		Level: levelVar,
 
 		// AddSource: Include source file and line number information
 		// Valuable for debugging but has slight performance impact
 		// Configuration allows per-environment tuning
 		AddSource: config.AddSource,
+
+		// ReplaceAttr: Write the custom TRACE and FATAL level names
+		// instead of slog's "DEBUG-4" and "ERROR+4" in JSON and text output
+		ReplaceAttr: replaceLevelName,
 	}
 
 	// Select and create the appropriate log handler based on configured format
@@ -321,8 +363,8 @@ func ConfigureLogger(config config.Logger) {
 		// - Formatted layout optimized for terminal display
 		// - Enhanced readability for debugging sessions
 		// - Improved developer experience during development
-		// - May include additional formatting features
-		handler = NewPrettyConsoleHandler(os.Stdout, opts)
+		// - Colors only on terminals (NO_COLOR/FORCE_COLOR honored), themes and compact layout via config.Pretty
+		handler = NewPrettyConsoleHandlerWithOptions(os.Stdout, opts, prettyOptionsFromConfig(config.Pretty))
 
 	default:
 		// Text Handler: Standard structured text output (default fallback)
@@ -335,6 +377,48 @@ func ConfigureLogger(config config.Logger) {
 		handler = slog.NewTextHandler(os.Stdout, opts)
 	}
 
+	// Move local output writes off the calling goroutine when asynchronous logging is enabled
+	// The previous queue is drained first so records keep their order across reconfiguration
+	if async != nil {
+		drainCtx, cancel := context.WithTimeout(context.Background(), asyncCloseTimeout)
+		if err := async.Close(drainCtx); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to drain previous async log queue: %v\n", err)
+		}
+		cancel()
+		async = nil
+	}
+	if config.Async.Enabled {
+		async = NewAsyncHandler(handler, config.Async.BufferSize, ParseOverflowPolicy(config.Async.OverflowPolicy))
+		handler = async
+	}
+
+	// Attach remote log sinks (syslog, GELF, OTLP) next to the local handler
+	// Sinks that cannot be created are reported once the new logger exists
+	previousSinks := sinks
+	sinks = nil
+	var sinkErrors []error
+	for _, sinkCfg := range config.Sinks {
+		sink, err := NewSinkHandler(sinkCfg, levelVar)
+		if err != nil {
+			sinkErrors = append(sinkErrors, err)
+			continue
+		}
+		sinks = append(sinks, sink)
+	}
+	if len(sinks) > 0 {
+		children := []slog.Handler{handler}
+		for _, sink := range sinks {
+			children = append(children, sink)
+		}
+		handler = newFanoutHandler(children...)
+	}
+
+	// Wrap the format handler with attribute redaction unless explicitly disabled
+	// This guarantees passwords, tokens and similar values are masked in every format
+	if !config.Redaction.Disabled {
+		handler = NewRedactingHandler(handler, redactOptionsFromConfig(config.Redaction))
+	}
+
 	// Create new logger instance with selected handler and configuration
 	// This completely replaces the previous logger instance
 	logger := slog.New(handler)
@@ -351,11 +435,25 @@ func ConfigureLogger(config config.Logger) {
 	// This provides operational visibility into logger configuration changes
 	// Helps with troubleshooting and configuration verification
 	logger.Debug("🔄 Logger successfully reconfigured with new settings",
-		slog.String("level", config.Level),        // Active log level
-		slog.String("format", config.Format),      // Active output format
-		slog.String("output", config.Output),      // Output destination
-		slog.Bool("add_source", config.AddSource), // Source tracking status
+		slog.String("level", config.Level),                 // Active log level
+		slog.String("format", config.Format),               // Active output format
+		slog.String("output", config.Output),               // Output destination
+		slog.Bool("add_source", config.AddSource),          // Source tracking status
+		slog.Bool("redaction", !config.Redaction.Disabled), // Sensitive attribute masking status
+		slog.Int("sinks", len(sinks)),                      // Active remote log sinks
+		slog.Bool("async", config.Async.Enabled),           // Asynchronous local output status
 	)
+
+	// Report sinks that could not be created; the application keeps running with local output
+	for _, err := range sinkErrors {
+		logger.Warn("📡 Remote log sink disabled", slog.Any("error", err))
+	}
+
+	// Retire sinks from the previous configuration without blocking the caller
+	// Their buffered records are still delivered before the connections close
+	if len(previousSinks) > 0 {
+		go closeSinks(previousSinks, sinkCloseTimeout)
+	}
 }
 
 // NewPrettyLogger creates a new standalone logger instance with enhanced pretty console output.
@@ -543,10 +641,169 @@ func GetLogger() *slog.Logger {
 	if instance == nil {
 		// Initialize bootstrap logger and return it
 		// This ensures the application always has access to logging functionality
-		return InitializeBootstrapLogger()
+		// The locked variant is used because mu is already held here
+		return initializeBootstrapLocked()
 	}
 
 	// Instance was initialized by another goroutine while we waited
 	// Return the initialized instance
 	return instance
 }
+
+// ReplaceLogger installs l as the singleton logger and slog default, returning
+// the previous singleton (nil if none was initialized yet).
+//
+// It is intended for tests and tooling that need to observe application logging,
+// such as the logtest package. Passing nil clears the singleton so the next
+// GetLogger call bootstraps a fresh logger; slog.Default is left unchanged in
+// that case. Sinks and the async queue of the previous configuration are not
+// touched, so restoring the returned logger resumes normal operation.
+//
+// Example:
+//
+//	previous := logger.ReplaceLogger(slog.New(recorder))
+//	defer logger.ReplaceLogger(previous)
+func ReplaceLogger(l *slog.Logger) *slog.Logger {
+	mu.Lock()
+	defer mu.Unlock()
+
+	previous := instance
+	instance = l
+	if l != nil {
+		slog.SetDefault(l)
+	}
+	return previous
+}
+
+// ParseLevel converts a configured level name into a slog.Level.
+//
+// Supported names are "trace", "debug", "info", "warn", "error" and "fatal"
+// (case-insensitive); any other value falls back to info so a typo never
+// silences logging entirely.
+//
+// Example:
+//
+//	level := logger.ParseLevel(cfg.Logger.Level)
+func ParseLevel(name string) slog.Level {
+	switch strings.ToLower(name) {
+	case "trace":
+		// Trace level: Wire-level detail such as individual database commands
+		// Includes every message the application emits
+		return LevelTrace
+	case "debug":
+		// Debug level: Maximum verbosity for development and troubleshooting
+		// Includes all log messages (debug, info, warn, error)
+		return slog.LevelDebug
+	case "warn":
+		// Warning level: Moderate verbosity focusing on potential issues
+		// Includes warn and error messages, excludes debug and info
+		return slog.LevelWarn
+	case "error":
+		// Error level: Minimum verbosity for production performance
+		// Includes only error messages, excludes debug, info, and warn
+		return slog.LevelError
+	case "fatal":
+		// Fatal level: Only records emitted immediately before the process exits
+		return LevelFatal
+	default:
+		// Default to info level for balanced visibility and performance
+		// Includes info, warn, and error messages, excludes debug
+		// This is the recommended level for most production environments
+		return slog.LevelInfo
+	}
+}
+
+// Shutdown drains the asynchronous output queue and flushes and closes every
+// remote log sink created by ConfigureLogger.
+//
+// It should be called once during application shutdown, after the last log
+// record worth shipping has been written. Records logged afterwards still reach
+// the local output but are no longer shipped.
+//
+// Parameters:
+//   - ctx: Bounds how long buffered records may take to deliver
+//
+// Returns:
+//   - error: Joined errors from sinks that failed to drain or close in time
+func Shutdown(ctx context.Context) error {
+	mu.Lock()
+	active := sinks
+	sinks = nil
+	queue := async
+	async = nil
+	mu.Unlock()
+
+	var errs []error
+	// Drain the local queue first; it keeps writing synchronously afterwards
+	if queue != nil {
+		if err := queue.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, sink := range active {
+		if err := sink.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Flush waits until records queued by the asynchronous output handler and the
+// remote log sinks so far have been written, without closing anything.
+func Flush(ctx context.Context) error {
+	mu.RLock()
+	queue := async
+	active := sinks
+	mu.RUnlock()
+
+	var errs []error
+	if queue != nil {
+		if err := queue.Flush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, sink := range active {
+		if err := sink.Flush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// QueueStats returns the asynchronous output queue counters. The second result
+// is false when asynchronous logging is not enabled.
+func QueueStats() (AsyncStats, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if async == nil {
+		return AsyncStats{}, false
+	}
+	return async.Stats(), true
+}
+
+// SinkStats returns delivery counters for each active remote log sink, in
+// configuration order. It returns nil when no sinks are configured.
+func SinkStats() []ShipperStats {
+	mu.RLock()
+	defer mu.RUnlock()
+	if len(sinks) == 0 {
+		return nil
+	}
+	stats := make([]ShipperStats, len(sinks))
+	for i, sink := range sinks {
+		stats[i] = sink.Stats()
+	}
+	return stats
+}
+
+// closeSinks closes retired sinks, reporting failures on stderr because the
+// logger that owned them has already been replaced.
+func closeSinks(retired []*ShippingHandler, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	for _, sink := range retired {
+		if err := sink.Close(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to close retired log sink: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+// Package logtest records slog output so tests can assert what was logged.
+//
+// A Recorder is a slog.Handler that keeps every record in memory with group
+// names folded into dotted attribute keys ("req.path"). It can be used in two
+// ways:
+//
+//   - Injected: pass rec.Logger() to the component under test (e.g.
+//     handlers.NewHandler or database.NewMongoDBManager). Fully isolated and
+//     safe for t.Parallel.
+//   - Captured: Capture swaps a routing logger into logger.GetLogger() and
+//     slog.Default for the duration of the test, for code that logs through
+//     the package-level logger.
+//
+// Captures are reference counted, so parallel tests can capture at the same
+// time and the original logger is restored only when the last one finishes.
+// Records logged with a context from rec.Context are delivered to that recorder
+// only; other records are delivered to every active capture. Components that
+// obtained their logger before Capture was called keep writing to the old one.
+//
+// Example:
+//
+//	rec := logtest.New(t)
+//	manager, _ := database.NewMongoDBManager(cfg, rec.Logger())
+//	rec.AssertNotLogged(logtest.Contains(cfg.Password))
+package logtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/logger"
+)
+
+// Entry is a recorded log record.
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   []slog.Attr // Resolved attributes with dotted keys for groups, in logging order
+}
+
+// Attr returns the value of the attribute with the given dotted key.
+func (e Entry) Attr(key string) (slog.Value, bool) {
+	for _, a := range e.Attrs {
+		if a.Key == key {
+			return a.Value, true
+		}
+	}
+	return slog.Value{}, false
+}
+
+// String renders the entry on one line for failure messages.
+func (e Entry) String() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%s %q", e.Level, e.Message)
+	for _, a := range e.Attrs {
+		fmt.Fprintf(b, " %s=%q", a.Key, a.Value.String())
+	}
+	return b.String()
+}
+
+// Recorder stores log records for inspection. It is safe for concurrent use.
+type Recorder struct {
+	tb      testing.TB
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// New creates a Recorder that records every level, including debug.
+func New(tb testing.TB) *Recorder {
+	return &Recorder{tb: tb}
+}
+
+// Handler returns a slog.Handler that records into r.
+func (r *Recorder) Handler() slog.Handler {
+	return &recordingHandler{targets: func(context.Context) []*Recorder { return []*Recorder{r} }}
+}
+
+// Logger returns a logger that records into r.
+func (r *Recorder) Logger() *slog.Logger {
+	return slog.New(r.Handler())
+}
+
+// Context returns a copy of ctx that routes records logged through a captured
+// global logger to r only, so parallel captures do not see each other's output.
+func (r *Recorder) Context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, recorderKey{}, r)
+}
+
+// Entries returns a copy of all records logged so far.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// Find returns the records matching all of the given matchers.
+func (r *Recorder) Find(matchers ...Matcher) []Entry {
+	match := All(matchers...)
+	var found []Entry
+	for _, e := range r.Entries() {
+		if match.Match(e) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// Count returns the number of records matching all of the given matchers.
+func (r *Recorder) Count(matchers ...Matcher) int {
+	return len(r.Find(matchers...))
+}
+
+// Reset discards all recorded entries.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// AssertLogged fails the test unless at least one record matches all matchers.
+func (r *Recorder) AssertLogged(matchers ...Matcher) {
+	r.tb.Helper()
+	if r.Count(matchers...) == 0 {
+		r.tb.Errorf("Expected a log record matching %s, got:\n%s", describe(matchers), r.dump())
+	}
+}
+
+// AssertNotLogged fails the test if any record matches all matchers.
+func (r *Recorder) AssertNotLogged(matchers ...Matcher) {
+	r.tb.Helper()
+	if found := r.Find(matchers...); len(found) > 0 {
+		r.tb.Errorf("Expected no log record matching %s, got %d:\n%s", describe(matchers), len(found), dumpEntries(found))
+	}
+}
+
+func (r *Recorder) add(e Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+}
+
+func (r *Recorder) dump() string {
+	return dumpEntries(r.Entries())
+}
+
+func dumpEntries(entries []Entry) string {
+	if len(entries) == 0 {
+		return "    (no records)"
+	}
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = "    " + e.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// recorderKey is the context key used by Recorder.Context.
+type recorderKey struct{}
+
+// Global capture state, guarded by captureMu.
+var (
+	captureMu       sync.Mutex
+	captures        = map[*Recorder]struct{}{}
+	previous        *slog.Logger // Singleton logger in place before the first active capture
+	previousDefault *slog.Logger // slog.Default before the first active capture
+	previousOutput  io.Writer    // Standard log package output, redirected by slog.SetDefault
+	previousFlags   int          // Standard log package flags, cleared by slog.SetDefault
+)
+
+// Capture creates a Recorder and routes logger.GetLogger() and slog.Default to
+// it until the test ends. It is safe to call from parallel tests.
+func Capture(tb testing.TB) *Recorder {
+	tb.Helper()
+	r := New(tb)
+
+	captureMu.Lock()
+	if len(captures) == 0 {
+		previousDefault = slog.Default()
+		previousOutput, previousFlags = log.Writer(), log.Flags()
+		previous = logger.ReplaceLogger(slog.New(&recordingHandler{targets: activeCaptures}))
+	}
+	captures[r] = struct{}{}
+	captureMu.Unlock()
+
+	tb.Cleanup(func() {
+		captureMu.Lock()
+		defer captureMu.Unlock()
+		delete(captures, r)
+		if len(captures) == 0 {
+			logger.ReplaceLogger(previous)
+			slog.SetDefault(previousDefault)
+			// SetDefault does not undo its redirection of the standard log
+			// package when restoring slog's built-in handler, so restore it here.
+			log.SetOutput(previousOutput)
+			log.SetFlags(previousFlags)
+			previous, previousDefault, previousOutput = nil, nil, nil
+		}
+	})
+	return r
+}
+
+// activeCaptures selects the recorders that receive a record logged through
+// the captured global logger.
+func activeCaptures(ctx context.Context) []*Recorder {
+	captureMu.Lock()
+	defer captureMu.Unlock()
+	if ctx != nil {
+		if r, ok := ctx.Value(recorderKey{}).(*Recorder); ok {
+			if _, active := captures[r]; active {
+				return []*Recorder{r}
+			}
+		}
+	}
+	targets := make([]*Recorder, 0, len(captures))
+	for r := range captures {
+		targets = append(targets, r)
+	}
+	return targets
+}
+
+// recordingHandler flattens records and delivers them to the recorders chosen by targets.
+type recordingHandler struct {
+	targets func(context.Context) []*Recorder
+	attrs   []slog.Attr // Pre-flattened attributes from WithAttrs
+	prefix  string      // Current group prefix ("a.b.") from WithGroup
+}
+
+// Enabled implements slog.Handler. Every level is recorded.
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle implements slog.Handler.
+func (h *recordingHandler) Handle(ctx context.Context, rec slog.Record) error {
+	attrs := append([]slog.Attr(nil), h.attrs...)
+	rec.Attrs(func(a slog.Attr) bool {
+		attrs = flatten(attrs, h.prefix, a)
+		return true
+	})
+	e := Entry{Time: rec.Time, Level: rec.Level, Message: rec.Message, Attrs: attrs}
+	for _, r := range h.targets(ctx) {
+		r.add(e)
+	}
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		clone.attrs = flatten(clone.attrs, h.prefix, a)
+	}
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *recordingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// flatten resolves a and appends it to dst, folding groups into dotted keys.
+func flatten(dst []slog.Attr, prefix string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return dst
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = prefix + a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			dst = flatten(dst, groupPrefix, ga)
+		}
+		return dst
+	}
+	return append(dst, slog.Attr{Key: prefix + a.Key, Value: a.Value})
+}
@@ -0,0 +1,142 @@
+package logtest
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Matcher selects recorded entries. Matchers are combined with All, AnyOf and
+// Not, and describe themselves in assertion failure messages.
+type Matcher struct {
+	desc  string
+	match func(Entry) bool
+}
+
+// Match reports whether e satisfies the matcher.
+func (m Matcher) Match(e Entry) bool {
+	return m.match(e)
+}
+
+// String returns the matcher description.
+func (m Matcher) String() string {
+	return m.desc
+}
+
+// Func builds a custom matcher from a predicate and a description.
+func Func(desc string, match func(Entry) bool) Matcher {
+	return Matcher{desc: desc, match: match}
+}
+
+// Level matches entries logged at exactly level.
+func Level(level slog.Level) Matcher {
+	return Func("level="+level.String(), func(e Entry) bool { return e.Level == level })
+}
+
+// MinLevel matches entries logged at level or above.
+func MinLevel(level slog.Level) Matcher {
+	return Func("level>="+level.String(), func(e Entry) bool { return e.Level >= level })
+}
+
+// Message matches entries whose message equals msg.
+func Message(msg string) Matcher {
+	return Func(fmt.Sprintf("message=%q", msg), func(e Entry) bool { return e.Message == msg })
+}
+
+// MessageContains matches entries whose message contains substr.
+func MessageContains(substr string) Matcher {
+	return Func(fmt.Sprintf("message contains %q", substr), func(e Entry) bool {
+		return strings.Contains(e.Message, substr)
+	})
+}
+
+// HasAttr matches entries carrying an attribute with the given dotted key.
+func HasAttr(key string) Matcher {
+	return Func("has "+key, func(e Entry) bool {
+		_, ok := e.Attr(key)
+		return ok
+	})
+}
+
+// Attr matches entries whose attribute key equals value. Numeric values match
+// regardless of the integer type they were logged with (8080 matches slog.Int).
+func Attr(key string, value any) Matcher {
+	want := slog.AnyValue(value).Resolve()
+	return Func(fmt.Sprintf("%s=%v", key, value), func(e Entry) bool {
+		got, ok := e.Attr(key)
+		if !ok {
+			return false
+		}
+		return got.Equal(want) || (got.Kind() == slog.KindAny && got.String() == want.String())
+	})
+}
+
+// AttrContains matches entries whose attribute key, rendered as text, contains substr.
+func AttrContains(key, substr string) Matcher {
+	return Func(fmt.Sprintf("%s contains %q", key, substr), func(e Entry) bool {
+		got, ok := e.Attr(key)
+		return ok && strings.Contains(got.String(), substr)
+	})
+}
+
+// Contains matches entries whose message or any attribute value, rendered as
+// text, contains substr. Use it with AssertNotLogged to prove a secret never
+// reaches the logs.
+func Contains(substr string) Matcher {
+	return Func(fmt.Sprintf("any text contains %q", substr), func(e Entry) bool {
+		if strings.Contains(e.Message, substr) {
+			return true
+		}
+		for _, a := range e.Attrs {
+			if strings.Contains(a.Value.String(), substr) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// All matches entries satisfying every matcher. All() matches everything.
+func All(matchers ...Matcher) Matcher {
+	return Func(describe(matchers), func(e Entry) bool {
+		for _, m := range matchers {
+			if !m.Match(e) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// AnyOf matches entries satisfying at least one matcher.
+func AnyOf(matchers ...Matcher) Matcher {
+	parts := make([]string, len(matchers))
+	for i, m := range matchers {
+		parts[i] = m.String()
+	}
+	return Func("("+strings.Join(parts, " or ")+")", func(e Entry) bool {
+		for _, m := range matchers {
+			if m.Match(e) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Not inverts a matcher.
+func Not(m Matcher) Matcher {
+	return Func("not "+m.String(), func(e Entry) bool { return !m.Match(e) })
+}
+
+// describe joins matcher descriptions for failure messages.
+func describe(matchers []Matcher) string {
+	if len(matchers) == 0 {
+		return "(any)"
+	}
+	parts := make([]string, len(matchers))
+	for i, m := range matchers {
+		parts[i] = m.String()
+	}
+	return strings.Join(parts, " and ")
+}
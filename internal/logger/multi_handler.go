@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// fanoutHandler is a slog.Handler that forwards each record to several handlers,
+// e.g. the local stdout handler plus any configured remote log sinks.
+//
+// Each child keeps its own level filter; a record is offered to every child
+// whose Enabled method accepts it.
+type fanoutHandler struct {
+	handlers []slog.Handler
+}
+
+// newFanoutHandler combines handlers. A single handler is returned unwrapped.
+func newFanoutHandler(handlers ...slog.Handler) slog.Handler {
+	if len(handlers) == 1 {
+		return handlers[0]
+	}
+	return &fanoutHandler{handlers: handlers}
+}
+
+// Enabled implements slog.Handler.
+// Returns true if any child handler accepts the level.
+func (h *fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, child := range h.handlers {
+		if child.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle implements slog.Handler.
+// Every enabled child receives its own clone of the record; errors are joined.
+func (h *fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, child := range h.handlers {
+		if child.Enabled(ctx, r.Level) {
+			if err := child.Handle(ctx, r.Clone()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	children := make([]slog.Handler, len(h.handlers))
+	for i, child := range h.handlers {
+		children[i] = child.WithAttrs(attrs)
+	}
+	return &fanoutHandler{handlers: children}
+}
+
+// WithGroup implements slog.Handler.
+func (h *fanoutHandler) WithGroup(name string) slog.Handler {
+	children := make([]slog.Handler, len(h.handlers))
+	for i, child := range h.handlers {
+		children[i] = child.WithGroup(name)
+	}
+	return &fanoutHandler{handlers: children}
+}
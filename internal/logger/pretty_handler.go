@@ -2,24 +2,53 @@ package logger
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
 )
 
 // PrettyConsoleHandler is a slog.Handler that pretty-prints logs to the console with colors and alignment.
 //
-// This handler is intended for human-friendly development output. It colorizes log levels,
-// aligns fields, and supports optional source location display. It is not suitable for machine parsing.
+// This handler is intended for human-friendly development output. It colorizes log levels
+// (only when writing to a terminal, honoring NO_COLOR and FORCE_COLOR), aligns attribute
+// keys, renders groups, structs, multi-line errors and stack traces as indented blocks,
+// and switches to a single-line compact layout on narrow terminals. It is not suitable
+// for machine parsing.
 type PrettyConsoleHandler struct {
-	out    io.Writer  // Output destination (e.g., os.Stdout)
-	level  slog.Level // Minimum log level to output
-	source bool       // Whether to print source location
+	out     io.Writer    // Output destination (e.g., os.Stdout)
+	mu      *sync.Mutex  // Serializes writes so multi-line records never interleave
+	level   slog.Leveler // Minimum log level to output
+	source  bool         // Whether to print source location
+	color   bool         // Whether to emit ANSI sequences
+	theme   prettyTheme  // Styles applied when color is enabled
+	compact bool         // Single-line layout
+	attrs   []slog.Attr  // Attributes from WithAttrs, nested under the groups open at the time
+	groups  []string     // Groups opened by WithGroup that record attributes belong to
+}
+
+// PrettyOptions configures the presentation of a PrettyConsoleHandler.
+// The zero value auto-detects colors and layout and uses the default theme.
+type PrettyOptions struct {
+	Color        ColorMode    // Color mode (defaults to ColorAuto)
+	Theme        string       // Theme name: "default", "light", "high-contrast" or "mono"
+	Layout       PrettyLayout // Attribute layout (defaults to LayoutAuto)
+	CompactWidth int          // Width below which LayoutAuto selects compact output (default 100)
 }
 
-// NewPrettyConsoleHandler creates a new PrettyConsoleHandler.
+// NewPrettyConsoleHandler creates a new PrettyConsoleHandler with automatic
+// color and layout detection and the default theme.
 //
 // Args:
 //
@@ -34,89 +63,396 @@ type PrettyConsoleHandler struct {
 //
 //	handler := NewPrettyConsoleHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug, AddSource: true})
 func NewPrettyConsoleHandler(w io.Writer, opts *slog.HandlerOptions) *PrettyConsoleHandler {
+	return NewPrettyConsoleHandlerWithOptions(w, opts, nil)
+}
+
+// NewPrettyConsoleHandlerWithOptions creates a PrettyConsoleHandler with explicit
+// presentation settings.
+//
+// Args:
+//
+//	w: Output writer (defaults to os.Stdout if nil)
+//	opts: slog.HandlerOptions (level and AddSource supported)
+//	pretty: Color, theme and layout settings (nil means auto-detect everything)
+//
+// Returns:
+//
+//	*PrettyConsoleHandler instance
+//
+// Example:
+//
+//	handler := NewPrettyConsoleHandlerWithOptions(os.Stdout, nil, &PrettyOptions{Theme: "light", Layout: LayoutCompact})
+func NewPrettyConsoleHandlerWithOptions(w io.Writer, opts *slog.HandlerOptions, pretty *PrettyOptions) *PrettyConsoleHandler {
 	if w == nil {
 		w = os.Stdout
 	}
-	level := slog.LevelInfo
-	if opts != nil && opts.Level != nil {
-		if l, ok := opts.Level.(slog.Level); ok {
-			level = l
-		} else {
-			level = opts.Level.Level()
-		}
-	}
+	var level slog.Leveler = slog.LevelInfo
 	source := false
 	if opts != nil {
+		if opts.Level != nil {
+			level = opts.Level
+		}
 		source = opts.AddSource
 	}
-	return &PrettyConsoleHandler{out: w, level: level, source: source}
+	if pretty == nil {
+		pretty = &PrettyOptions{}
+	}
+	return &PrettyConsoleHandler{
+		out:     w,
+		mu:      &sync.Mutex{},
+		level:   level,
+		source:  source,
+		color:   resolveColor(w, pretty.Color),
+		theme:   lookupTheme(pretty.Theme),
+		compact: resolveCompact(w, pretty.Layout, pretty.CompactWidth),
+	}
+}
+
+// prettyOptionsFromConfig translates config.LoggerPretty into PrettyOptions.
+func prettyOptionsFromConfig(cfg config.LoggerPretty) *PrettyOptions {
+	return &PrettyOptions{
+		Color:        ColorMode(strings.ToLower(cfg.Color)),
+		Theme:        cfg.Theme,
+		Layout:       PrettyLayout(strings.ToLower(cfg.Layout)),
+		CompactWidth: cfg.CompactWidth,
+	}
 }
 
 // Enabled implements slog.Handler.
 // Returns true if the log level is enabled for output.
 func (h *PrettyConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
-	return level >= h.level
+	return level >= h.level.Level()
 }
 
 // Handle implements slog.Handler.
 // Formats and writes the log record to the output in a human-friendly way.
 //
-// Example output:
+// Example output (expanded layout):
+//
+//	2024-06-01 12:34:56.789 INFO  Starting server
+//	    port = 8080
+//	    env  = dev
+//	    db:
+//	        host = localhost
 //
-//	2024-06-01 12:34:56.789 INFO  Starting server | port=8080 env=dev
+// Example output (compact layout):
+//
+//	12:34:56.789 INF Starting server port=8080 env=dev db.host=localhost
 func (h *PrettyConsoleHandler) Handle(_ context.Context, r slog.Record) error {
-	b := &strings.Builder{}
-	// Timestamp
-	timestamp := r.Time.Format("2006-01-02 15:04:05.000")
-	fmt.Fprintf(b, "%s ", timestamp)
-	// Level (colorized)
-	fmt.Fprintf(b, "%s%-5s%s ", levelColor(r.Level), r.Level.String(), resetColor())
-	// Message
-	fmt.Fprintf(b, "%s", r.Message)
-	// Key-value pairs
+	attrs := h.attrs
 	if r.NumAttrs() > 0 {
-		b.WriteString(" | ")
+		recordAttrs := make([]slog.Attr, 0, r.NumAttrs())
 		r.Attrs(func(a slog.Attr) bool {
-			fmt.Fprintf(b, "%s=%v ", a.Key, a.Value)
+			recordAttrs = append(recordAttrs, a)
 			return true
 		})
+		attrs = append(attrs[:len(attrs):len(attrs)], nestInGroups(h.groups, recordAttrs)...)
 	}
-	// Source (prints PC as hex if enabled, since slog.Frame is not public)
-	if h.source && r.PC != 0 {
-		fmt.Fprintf(b, " (pc=0x%x)", r.PC)
+	fields := buildPrettyFields(nil, attrs)
+
+	b := &strings.Builder{}
+	if h.compact {
+		h.writeCompact(b, r, fields)
+	} else {
+		h.writeExpanded(b, r, fields)
 	}
-	b.WriteString("\n")
-	_, err := h.out.Write([]byte(b.String()))
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.out, b.String())
 	return err
 }
 
 // WithAttrs implements slog.Handler.
-// Returns a handler with additional attributes (not supported in this implementation).
+// Returns a handler that prints attrs with every record, inside the currently open groups.
 func (h *PrettyConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	// TODO(2024-06-01): Implement attribute grouping if needed for advanced use cases.
-	return h
+	if len(attrs) == 0 {
+		return h
+	}
+	clone := *h
+	clone.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], nestInGroups(h.groups, attrs)...)
+	return &clone
 }
 
 // WithGroup implements slog.Handler.
-// Returns a handler with a group name (not supported in this implementation).
+// Returns a handler that nests subsequent attributes under name.
 func (h *PrettyConsoleHandler) WithGroup(name string) slog.Handler {
-	// TODO(2024-06-01): Implement group support if needed for advanced use cases.
-	return h
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &clone
+}
+
+// writeExpanded renders the header line followed by one aligned line per attribute.
+func (h *PrettyConsoleHandler) writeExpanded(b *strings.Builder, r slog.Record, fields []prettyField) {
+	b.WriteString(h.paint(h.theme.timestamp, r.Time.Format("2006-01-02 15:04:05.000")))
+	b.WriteByte(' ')
+	b.WriteString(h.paint(h.theme.levelStyle(r.Level), fmt.Sprintf("%-5s", LevelName(r.Level))))
+	b.WriteByte(' ')
+	b.WriteString(h.paint(h.theme.message, r.Message))
+	if src := h.sourceLocation(r); src != "" {
+		b.WriteString("  ")
+		b.WriteString(h.paint(h.theme.source, src))
+	}
+	b.WriteByte('\n')
+	h.writeBlock(b, fields, 1)
+}
+
+// writeBlock renders fields at the given indentation depth with keys padded to
+// a common width, recursing into groups.
+func (h *PrettyConsoleHandler) writeBlock(b *strings.Builder, fields []prettyField, depth int) {
+	indent := strings.Repeat("    ", depth)
+	width := 0
+	for _, f := range fields {
+		if n := utf8.RuneCountInString(f.key); !f.group() && n > width {
+			width = n
+		}
+	}
+	for _, f := range fields {
+		b.WriteString(indent)
+		if f.group() {
+			b.WriteString(h.paint(h.theme.group, f.key+":"))
+			b.WriteByte('\n')
+			h.writeBlock(b, f.children, depth+1)
+			continue
+		}
+
+		b.WriteString(h.paint(h.theme.key, f.key))
+		b.WriteString(strings.Repeat(" ", width-utf8.RuneCountInString(f.key)))
+		text, isErr := expandedValue(f.value)
+		style := ""
+		if isErr {
+			style = h.theme.errValue
+		}
+		if !strings.Contains(text, "\n") {
+			b.WriteString(" = ")
+			b.WriteString(h.paint(style, text))
+			b.WriteByte('\n')
+			continue
+		}
+		// Multi-line values (stack traces, joined errors, indented JSON) get their own block.
+		b.WriteString(" =\n")
+		for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+			b.WriteString(indent)
+			b.WriteString("    │ ")
+			b.WriteString(h.paint(style, line))
+			b.WriteByte('\n')
+		}
+	}
+}
+
+// writeCompact renders the record on one line with dotted keys for groups.
+func (h *PrettyConsoleHandler) writeCompact(b *strings.Builder, r slog.Record, fields []prettyField) {
+	b.WriteString(h.paint(h.theme.timestamp, r.Time.Format("15:04:05.000")))
+	b.WriteByte(' ')
+	b.WriteString(h.paint(h.theme.levelStyle(r.Level), compactLevel(r.Level)))
+	b.WriteByte(' ')
+	b.WriteString(h.paint(h.theme.message, r.Message))
+	h.writeInline(b, fields, "")
+	if src := h.sourceLocation(r); src != "" {
+		b.WriteByte(' ')
+		b.WriteString(h.paint(h.theme.source, src))
+	}
+	b.WriteByte('\n')
+}
+
+// writeInline appends " key=value" pairs, prefixing keys with their group path.
+func (h *PrettyConsoleHandler) writeInline(b *strings.Builder, fields []prettyField, prefix string) {
+	for _, f := range fields {
+		if f.group() {
+			h.writeInline(b, f.children, prefix+f.key+".")
+			continue
+		}
+		text, isErr := compactValue(f.value)
+		style := ""
+		if isErr {
+			style = h.theme.errValue
+		}
+		b.WriteByte(' ')
+		b.WriteString(h.paint(h.theme.key, prefix+f.key))
+		b.WriteByte('=')
+		b.WriteString(h.paint(style, text))
+	}
+}
+
+// sourceLocation returns "file.go:line" for the record's call site when enabled.
+func (h *PrettyConsoleHandler) sourceLocation(r slog.Record) string {
+	if !h.source || r.PC == 0 {
+		return ""
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+	if frame.File == "" {
+		return fmt.Sprintf("(pc=0x%x)", r.PC)
+	}
+	return fmt.Sprintf("(%s:%d)", filepath.Base(frame.File), frame.Line)
+}
+
+// paint wraps s in the given ANSI sequence when colors are enabled.
+func (h *PrettyConsoleHandler) paint(style, s string) string {
+	if !h.color || style == "" || s == "" {
+		return s
+	}
+	return style + s + resetColor()
+}
+
+// prettyField is one node of the attribute tree printed for a record.
+type prettyField struct {
+	key      string
+	value    slog.Value    // Resolved value (unused for groups)
+	children []prettyField // Non-nil for groups
+}
+
+func (f prettyField) group() bool { return f.children != nil }
+
+// buildPrettyFields resolves attrs into a tree, dropping empty attributes and
+// groups, inlining groups with empty keys and merging groups with equal names
+// so attributes from WithAttrs and the record share one block.
+func buildPrettyFields(dst []prettyField, attrs []slog.Attr) []prettyField {
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+		if a.Value.Kind() != slog.KindGroup {
+			dst = append(dst, prettyField{key: a.Key, value: a.Value})
+			continue
+		}
+		if a.Key == "" {
+			dst = buildPrettyFields(dst, a.Value.Group())
+			continue
+		}
+		merged := false
+		for i := range dst {
+			if dst[i].group() && dst[i].key == a.Key {
+				dst[i].children = buildPrettyFields(dst[i].children, a.Value.Group())
+				merged = true
+				break
+			}
+		}
+		if merged {
+			continue
+		}
+		if children := buildPrettyFields(nil, a.Value.Group()); len(children) > 0 {
+			dst = append(dst, prettyField{key: a.Key, children: children})
+		}
+	}
+	return dst
+}
+
+// nestInGroups wraps attrs in nested slog.Group attributes, innermost group last.
+func nestInGroups(groups []string, attrs []slog.Attr) []slog.Attr {
+	for i := len(groups) - 1; i >= 0; i-- {
+		attrs = []slog.Attr{{Key: groups[i], Value: slog.GroupValue(attrs...)}}
+	}
+	return attrs
+}
+
+// expandedValue formats a value for the expanded layout. Errors use "%+v" so
+// stack traces from wrapping libraries are shown; structs, maps and slices are
+// rendered as indented JSON. The boolean reports whether the value is an error.
+func expandedValue(v slog.Value) (string, bool) {
+	if v.Kind() != slog.KindAny {
+		return scalarValue(v), false
+	}
+	switch x := v.Any().(type) {
+	case error:
+		if _, ok := x.(fmt.Formatter); ok {
+			return fmt.Sprintf("%+v", x), true
+		}
+		return x.Error(), true
+	case []byte:
+		return string(x), false
+	case fmt.Stringer:
+		return x.String(), false
+	default:
+		if isComposite(x) {
+			if data, err := json.MarshalIndent(x, "", "  "); err == nil {
+				return string(data), false
+			}
+		}
+		return fmt.Sprintf("%+v", x), false
+	}
+}
+
+// compactValue formats a value for the single-line layout, quoting text that
+// contains spaces, quotes or line breaks. The boolean reports whether the value is an error.
+func compactValue(v slog.Value) (string, bool) {
+	if v.Kind() != slog.KindAny {
+		return quoteIfNeeded(scalarValue(v)), false
+	}
+	switch x := v.Any().(type) {
+	case error:
+		return quoteIfNeeded(x.Error()), true
+	case []byte:
+		return quoteIfNeeded(string(x)), false
+	case fmt.Stringer:
+		return quoteIfNeeded(x.String()), false
+	default:
+		if isComposite(x) {
+			if data, err := json.Marshal(x); err == nil {
+				return string(data), false
+			}
+		}
+		return quoteIfNeeded(fmt.Sprintf("%+v", x)), false
+	}
+}
+
+// scalarValue formats non-Any kinds.
+func scalarValue(v slog.Value) string {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		return v.Duration().String()
+	default:
+		return v.String()
+	}
 }
 
-// levelColor returns the ANSI color code for a given slog.Level.
-func levelColor(level slog.Level) string {
+// isComposite reports whether x is a struct, map, slice or array (or a pointer to one).
+func isComposite(x any) bool {
+	t := reflect.TypeOf(x)
+	if t == nil {
+		return false
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
+
+// quoteIfNeeded quotes s when it is empty or would be ambiguous in key=value output.
+func quoteIfNeeded(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// compactLevel returns a three-letter level label for the compact layout.
+func compactLevel(level slog.Level) string {
 	switch level {
+	case LevelTrace:
+		return "TRC"
 	case slog.LevelDebug:
-		return "\033[36m" // Cyan
+		return "DBG"
 	case slog.LevelInfo:
-		return "\033[32m" // Green
+		return "INF"
 	case slog.LevelWarn:
-		return "\033[33m" // Yellow
+		return "WRN"
 	case slog.LevelError:
-		return "\033[31m" // Red
+		return "ERR"
+	case LevelFatal:
+		return "FTL"
 	default:
-		return ""
+		return LevelName(level)
 	}
 }
 
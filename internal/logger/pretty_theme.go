@@ -0,0 +1,213 @@
+package logger
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ColorMode controls whether PrettyConsoleHandler emits ANSI color sequences.
+type ColorMode string
+
+const (
+	// ColorAuto enables colors only when the output is a terminal. The NO_COLOR
+	// and FORCE_COLOR environment variables take precedence over detection.
+	ColorAuto ColorMode = "auto"
+
+	// ColorAlways enables colors unconditionally (e.g. for CI log viewers).
+	ColorAlways ColorMode = "always"
+
+	// ColorNever disables colors unconditionally.
+	ColorNever ColorMode = "never"
+)
+
+// PrettyLayout selects how PrettyConsoleHandler arranges attributes.
+type PrettyLayout string
+
+const (
+	// LayoutAuto uses LayoutCompact on terminals narrower than
+	// PrettyOptions.CompactWidth columns and LayoutExpanded otherwise.
+	LayoutAuto PrettyLayout = "auto"
+
+	// LayoutExpanded prints one attribute per line with aligned key/value
+	// columns and indented blocks for groups, structs, errors and stack traces.
+	LayoutExpanded PrettyLayout = "expanded"
+
+	// LayoutCompact prints each record on a single line with key=value pairs.
+	LayoutCompact PrettyLayout = "compact"
+)
+
+// defaultCompactWidth is the terminal width below which LayoutAuto switches to compact output.
+const defaultCompactWidth = 100
+
+// prettyTheme holds the ANSI sequences used for each element of a record.
+// An empty sequence leaves the element unstyled.
+type prettyTheme struct {
+	timestamp string
+	trace     string
+	debug     string
+	info      string
+	warn      string
+	error     string
+	fatal     string
+	message   string
+	key       string
+	group     string
+	errValue  string
+	source    string
+}
+
+// prettyThemes lists the built-in themes selectable by name via
+// config.LoggerPretty.Theme. Unknown names fall back to "default".
+var prettyThemes = map[string]prettyTheme{
+	// default: 16-color palette tuned for dark terminal backgrounds
+	"default": {
+		timestamp: "\033[2m",
+		trace:     "\033[2;36m",
+		debug:     "\033[36m",
+		info:      "\033[32m",
+		warn:      "\033[33m",
+		error:     "\033[31m",
+		fatal:     "\033[1;97;41m",
+		message:   "\033[1m",
+		key:       "\033[34m",
+		group:     "\033[35m",
+		errValue:  "\033[31m",
+		source:    "\033[2m",
+	},
+	// light: darker 256-color shades that stay readable on light backgrounds
+	"light": {
+		timestamp: "\033[38;5;244m",
+		trace:     "\033[38;5;109m",
+		debug:     "\033[38;5;30m",
+		info:      "\033[38;5;28m",
+		warn:      "\033[38;5;130m",
+		error:     "\033[38;5;160m",
+		fatal:     "\033[1;97;48;5;160m",
+		message:   "\033[1m",
+		key:       "\033[38;5;25m",
+		group:     "\033[38;5;90m",
+		errValue:  "\033[38;5;160m",
+		source:    "\033[38;5;244m",
+	},
+	// high-contrast: bold bright colors for low-vision setups and projectors
+	"high-contrast": {
+		timestamp: "\033[97m",
+		trace:     "\033[96m",
+		debug:     "\033[1;96m",
+		info:      "\033[1;92m",
+		warn:      "\033[1;93m",
+		error:     "\033[1;97;41m",
+		fatal:     "\033[1;5;97;41m",
+		message:   "\033[1;97m",
+		key:       "\033[1;94m",
+		group:     "\033[1;95m",
+		errValue:  "\033[1;91m",
+		source:    "\033[97m",
+	},
+	// mono: no hues, only bold/dim/reverse, for terminals with poor color support
+	"mono": {
+		timestamp: "\033[2m",
+		trace:     "\033[2m",
+		debug:     "\033[2m",
+		warn:      "\033[1m",
+		error:     "\033[1;7m",
+		fatal:     "\033[1;7;4m",
+		message:   "\033[1m",
+		group:     "\033[1m",
+		errValue:  "\033[1m",
+		source:    "\033[2m",
+	},
+}
+
+// lookupTheme returns the named theme, accepting "_" in place of "-".
+func lookupTheme(name string) prettyTheme {
+	name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	if theme, ok := prettyThemes[name]; ok {
+		return theme
+	}
+	return prettyThemes["default"]
+}
+
+// levelStyle returns the theme sequence for a level. Custom levels use the
+// style of the nearest standard level below them.
+func (t prettyTheme) levelStyle(level slog.Level) string {
+	switch {
+	case level >= LevelFatal:
+		return t.fatal
+	case level >= slog.LevelError:
+		return t.error
+	case level >= slog.LevelWarn:
+		return t.warn
+	case level >= slog.LevelInfo:
+		return t.info
+	case level >= slog.LevelDebug:
+		return t.debug
+	default:
+		return t.trace
+	}
+}
+
+// resolveColor decides whether output written to w should be colorized.
+//
+// Precedence: an explicit ColorAlways/ColorNever mode wins; otherwise a
+// non-empty NO_COLOR disables colors (https://no-color.org), a non-empty
+// FORCE_COLOR other than "0" or "false" enables them, TERM=dumb disables
+// them, and finally colors are used only when w is a terminal.
+func resolveColor(w io.Writer, mode ColorMode) bool {
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("FORCE_COLOR"); force != "" && force != "0" && !strings.EqualFold(force, "false") {
+		return true
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// isTerminal reports whether w is a file attached to a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// terminalWidth returns the column count of the terminal behind w, falling back
+// to the COLUMNS environment variable. It returns 0 when the width is unknown.
+func terminalWidth(w io.Writer) int {
+	if f, ok := w.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil && width > 0 {
+			return width
+		}
+	}
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return width
+	}
+	return 0
+}
+
+// resolveCompact decides whether the compact layout applies to output written to w.
+func resolveCompact(w io.Writer, layout PrettyLayout, compactWidth int) bool {
+	switch layout {
+	case LayoutCompact:
+		return true
+	case LayoutExpanded:
+		return false
+	}
+	if compactWidth <= 0 {
+		compactWidth = defaultCompactWidth
+	}
+	width := terminalWidth(w)
+	return width > 0 && width < compactWidth
+}
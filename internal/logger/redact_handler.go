@@ -0,0 +1,566 @@
+package logger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+)
+
+// RedactedMask is the placeholder written in place of redacted values when the
+// redaction mode is RedactModeMask. It matches the literal the database package
+// already uses for hand-masked credentials so both sources look identical in logs.
+const RedactedMask = "***MASKED***"
+
+// RedactMode selects how a matching value is rewritten.
+type RedactMode string
+
+const (
+	// RedactModeMask replaces the sensitive value with RedactedMask.
+	RedactModeMask RedactMode = "mask"
+
+	// RedactModeHash replaces the sensitive value with a truncated SHA-256 digest
+	// ("sha256:<16 hex chars>"), so equal secrets can still be correlated across
+	// log lines without revealing them.
+	RedactModeHash RedactMode = "hash"
+)
+
+// ValueMatcher detects sensitive data inside string values regardless of the
+// attribute key they are logged under.
+//
+// If Pattern contains a capturing group, only the text of the first group is
+// redacted (e.g. the password inside a connection URI); otherwise the whole
+// match is redacted.
+type ValueMatcher struct {
+	Name    string         // Identifier used in configuration (e.g. "jwt")
+	Pattern *regexp.Regexp // Expression locating the sensitive data
+}
+
+// Built-in value matchers, selectable by name from config.LoggerRedaction.Matchers.
+var (
+	// JWTMatcher finds JSON Web Tokens (three base64url segments, header starting with "eyJ").
+	JWTMatcher = ValueMatcher{
+		Name:    "jwt",
+		Pattern: regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	}
+
+	// MongoURIMatcher finds the password component of mongodb:// and mongodb+srv:// URIs.
+	MongoURIMatcher = ValueMatcher{
+		Name:    "mongo_uri",
+		Pattern: regexp.MustCompile(`mongodb(?:\+srv)?://[^\s:/@]+:([^\s@/]+)@`),
+	}
+
+	// EmailMatcher finds e-mail addresses.
+	EmailMatcher = ValueMatcher{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	}
+)
+
+// DefaultRedactKeys lists the attribute key patterns redacted out of the box.
+// Patterns use path.Match syntax and are compared case-insensitively against
+// both the attribute key and its dotted group path (e.g. "database.password").
+var DefaultRedactKeys = []string{
+	"*password*",
+	"*passwd*",
+	"*secret*",
+	"*token",
+	"*api_key*",
+	"*apikey*",
+	"*private_key*",
+	"*salt*",
+	"authorization",
+	"cookie",
+	"set-cookie",
+	"credentials",
+}
+
+// DefaultValueMatchers lists the value matchers applied when none are configured.
+var DefaultValueMatchers = []ValueMatcher{JWTMatcher, MongoURIMatcher, EmailMatcher}
+
+// RedactOptions configures a RedactingHandler.
+type RedactOptions struct {
+	// Keys are path.Match patterns for attribute keys whose values are always redacted.
+	Keys []string
+
+	// Matchers detect sensitive substrings inside string, error and fmt.Stringer values.
+	Matchers []ValueMatcher
+
+	// Mode selects masking or hashing. Defaults to RedactModeMask.
+	Mode RedactMode
+
+	// HashSalt is prepended to values before hashing in RedactModeHash, preventing
+	// dictionary lookups of low-entropy values such as e-mail addresses.
+	HashSalt string
+}
+
+// RedactingHandler is a slog.Handler wrapper that masks or hashes sensitive
+// attributes before they reach the wrapped handler.
+//
+// Redaction is applied to record attributes, attributes added through WithAttrs,
+// nested groups and the resolved values of slog.LogValuer implementations, so it
+// works the same for the JSON, text and pretty formats. Structs, maps and slices
+// logged with slog.Any are walked by field, with fields named by their JSON
+// keys, so a configuration struct logged whole has its secret fields redacted
+// like attributes.
+type RedactingHandler struct {
+	next     slog.Handler
+	keys     []string // lower-cased key patterns
+	matchers []ValueMatcher
+	mode     RedactMode
+	salt     string
+	groups   []string // groups opened via WithGroup, used for dotted key paths
+}
+
+// NewRedactingHandler wraps next with attribute redaction.
+//
+// Args:
+//
+//	next: Handler receiving the redacted records
+//	opts: Redaction options (nil uses DefaultRedactKeys, DefaultValueMatchers and mask mode)
+//
+// Example:
+//
+//	h := NewRedactingHandler(slog.NewJSONHandler(os.Stdout, nil), nil)
+//	slog.New(h).Info("login", "password", "hunter2") // password=***MASKED***
+func NewRedactingHandler(next slog.Handler, opts *RedactOptions) *RedactingHandler {
+	if opts == nil {
+		opts = &RedactOptions{}
+	}
+	keys := opts.Keys
+	if keys == nil {
+		keys = DefaultRedactKeys
+	}
+	lowered := make([]string, len(keys))
+	for i, k := range keys {
+		lowered[i] = strings.ToLower(k)
+	}
+	matchers := opts.Matchers
+	if matchers == nil {
+		matchers = DefaultValueMatchers
+	}
+	mode := opts.Mode
+	if mode != RedactModeHash {
+		mode = RedactModeMask
+	}
+	return &RedactingHandler{
+		next:     next,
+		keys:     lowered,
+		matchers: matchers,
+		mode:     mode,
+		salt:     opts.HashSalt,
+	}
+}
+
+// Enabled implements slog.Handler by delegating to the wrapped handler.
+func (h *RedactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+// Rebuilds the record with redacted attributes and forwards it.
+func (h *RedactingHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(h.redactAttr(h.groups, a))
+		return true
+	})
+	return h.next.Handle(ctx, nr)
+}
+
+// WithAttrs implements slog.Handler.
+// Attributes are redacted once here instead of on every record.
+func (h *RedactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactAttr(h.groups, a)
+	}
+	clone := *h
+	clone.next = h.next.WithAttrs(redacted)
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *RedactingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	clone.groups = append(append([]string(nil), h.groups...), name)
+	return &clone
+}
+
+// redactAttr returns a with sensitive content replaced. LogValuers are resolved
+// first so their output is subject to the same rules as plain values.
+func (h *RedactingHandler) redactAttr(groups []string, a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	if h.keyMatches(groups, a.Key) {
+		return slog.String(a.Key, h.redact(a.Value.String()))
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		inner := a.Value.Group()
+		// Inline groups (empty key) share the parent's path.
+		path := groups
+		if a.Key != "" {
+			path = append(append([]string(nil), groups...), a.Key)
+		}
+		out := make([]slog.Attr, len(inner))
+		for i, ga := range inner {
+			out[i] = h.redactAttr(path, ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(out...)}
+
+	case slog.KindString:
+		if s, changed := h.redactString(a.Value.String()); changed {
+			return slog.String(a.Key, s)
+		}
+
+	case slog.KindAny:
+		// Errors and Stringers are rendered as text by every handler, so scan that text.
+		var text string
+		switch v := a.Value.Any().(type) {
+		case error:
+			text = v.Error()
+		case fmt.Stringer:
+			text = v.String()
+		default:
+			if tree, ok := h.redactComposite(groups, a.Key, v); ok {
+				return slog.Any(a.Key, tree)
+			}
+			return a
+		}
+		if s, changed := h.redactString(text); changed {
+			return slog.String(a.Key, s)
+		}
+	}
+	return a
+}
+
+// maxRedactDepth bounds how deep redactComposite descends, so values with
+// pointer cycles terminate.
+const maxRedactDepth = 32
+
+// redactComposite redacts a struct, map, slice or array value v logged under
+// key. Struct fields are named by their JSON keys, which also appear in
+// configuration files. It reports false, and v is logged unchanged with its
+// own types and marshalers, unless something inside v was redacted; only the
+// containers on the way to a redacted value are rebuilt, as map[string]any
+// and []any holding the original values of the untouched fields.
+func (h *RedactingHandler) redactComposite(groups []string, key string, v any) (any, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Array:
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return nil, false // Raw bytes carry no keys
+		}
+	default:
+		return nil, false
+	}
+
+	path := groups
+	if key != "" {
+		path = append(append([]string(nil), groups...), key)
+	}
+	return h.redactReflect(path, rv, 0)
+}
+
+// redactReflect returns a redacted copy of rv and true when anything inside
+// matched, or false when rv can be logged as it is; path is the dotted key
+// path of rv.
+func (h *RedactingHandler) redactReflect(path []string, rv reflect.Value, depth int) (any, bool) {
+	if !rv.IsValid() || depth > maxRedactDepth {
+		return nil, false
+	}
+	if (rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return nil, false // Value receivers must not be called on nil
+	}
+	if rv.CanInterface() {
+		switch v := rv.Interface().(type) {
+		case slog.LogValuer:
+			// Log what the type chose to reveal, as for top-level values.
+			parent, key := path, ""
+			if len(path) > 0 {
+				parent, key = path[:len(path)-1], path[len(path)-1]
+			}
+			return valueToAny(h.redactAttr(parent, slog.Any(key, v)).Value), true
+		case encoding.TextMarshaler:
+			text, err := v.MarshalText()
+			if err != nil {
+				return nil, false
+			}
+			if s, changed := h.redactString(string(text)); changed {
+				return s, true
+			}
+			return nil, false
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return h.redactReflect(path, rv.Elem(), depth+1)
+
+	case reflect.String:
+		if s, changed := h.redactString(rv.String()); changed {
+			return s, true
+		}
+
+	case reflect.Struct:
+		fields := cachedStructFields(rv.Type())
+		redacted := make(map[int]any)
+		for i, f := range fields {
+			fv, err := rv.FieldByIndexErr(f.index)
+			if err != nil {
+				continue // Field of a nil embedded pointer
+			}
+			if value, changed := h.redactMember(path, f.name, fv, depth); changed {
+				redacted[i] = value
+			}
+		}
+		if len(redacted) == 0 {
+			return nil, false
+		}
+		out := make(map[string]any, len(fields))
+		for i, f := range fields {
+			if value, ok := redacted[i]; ok {
+				out[f.name] = value
+			} else if fv, err := rv.FieldByIndexErr(f.index); err == nil {
+				out[f.name] = fv.Interface()
+			}
+		}
+		return out, true
+
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		var out map[string]any
+		iter := rv.MapRange()
+		for iter.Next() {
+			if value, changed := h.redactMember(path, iter.Key().String(), iter.Value(), depth); changed {
+				if out == nil {
+					out = make(map[string]any, rv.Len())
+				}
+				out[iter.Key().String()] = value
+			}
+		}
+		if out == nil {
+			return nil, false
+		}
+		for iter = rv.MapRange(); iter.Next(); {
+			if _, done := out[iter.Key().String()]; !done && iter.Value().CanInterface() {
+				out[iter.Key().String()] = iter.Value().Interface()
+			}
+		}
+		return out, true
+
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return nil, false
+		}
+		var out []any
+		for i := 0; i < rv.Len(); i++ {
+			if value, changed := h.redactReflect(path, rv.Index(i), depth+1); changed {
+				if out == nil {
+					out = make([]any, rv.Len())
+					for j := 0; j < rv.Len(); j++ {
+						if rv.Index(j).CanInterface() {
+							out[j] = rv.Index(j).Interface()
+						}
+					}
+				}
+				out[i] = value
+			}
+		}
+		if out == nil {
+			return nil, false
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// redactMember redacts the struct field or map entry named key of the value at path.
+func (h *RedactingHandler) redactMember(path []string, key string, v reflect.Value, depth int) (any, bool) {
+	if h.keyMatches(path, key) {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return nil, false
+			}
+			v = v.Elem()
+		}
+		if v.Kind() == reflect.String && v.Len() == 0 {
+			return nil, false // Show that an optional secret is unset
+		}
+		if !v.CanInterface() {
+			return nil, false
+		}
+		return h.redact(fmt.Sprint(v.Interface())), true
+	}
+	return h.redactReflect(append(append([]string(nil), path...), key), v, depth+1)
+}
+
+// structField is an exported struct field as encoding/json names it.
+type structField struct {
+	name  string
+	index []int // Index sequence for reflect.Value.FieldByIndexErr
+}
+
+// structFieldCache maps a reflect.Type to its []structField.
+var structFieldCache sync.Map
+
+// cachedStructFields returns the JSON-visible fields of struct type t. Fields
+// of untagged embedded structs are promoted, and fields tagged "-" skipped.
+func cachedStructFields(t reflect.Type) []structField {
+	if fields, ok := structFieldCache.Load(t); ok {
+		return fields.([]structField)
+	}
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, inner := range cachedStructFields(ft) {
+					index := append([]int{i}, inner.index...)
+					fields = append(fields, structField{name: inner.name, index: index})
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, structField{name: name, index: []int{i}})
+	}
+	structFieldCache.Store(t, fields)
+	return fields
+}
+
+// valueToAny converts a resolved slog.Value into a plain value that nests
+// inside rebuilt maps and slices; groups become map[string]any.
+func valueToAny(v slog.Value) any {
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+	out := make(map[string]any, len(v.Group()))
+	for _, a := range v.Group() {
+		out[a.Key] = valueToAny(a.Value)
+	}
+	return out
+}
+
+// keyMatches reports whether key, or its dotted path under groups, matches a key pattern.
+func (h *RedactingHandler) keyMatches(groups []string, key string) bool {
+	if key == "" || len(h.keys) == 0 {
+		return false
+	}
+	lkey := strings.ToLower(key)
+	full := lkey
+	if len(groups) > 0 {
+		full = strings.ToLower(strings.Join(groups, ".")) + "." + lkey
+	}
+	for _, p := range h.keys {
+		// Malformed patterns simply never match.
+		if ok, _ := path.Match(p, lkey); ok {
+			return true
+		}
+		if full != lkey {
+			if ok, _ := path.Match(p, full); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactString applies every value matcher to s and reports whether anything changed.
+func (h *RedactingHandler) redactString(s string) (string, bool) {
+	changed := false
+	for _, m := range h.matchers {
+		if m.Pattern == nil {
+			continue
+		}
+		idx := m.Pattern.FindAllStringSubmatchIndex(s, -1)
+		if idx == nil {
+			continue
+		}
+		var b strings.Builder
+		last := 0
+		for _, loc := range idx {
+			start, end := loc[0], loc[1]
+			// Prefer the first capturing group when the pattern defines one.
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			b.WriteString(s[last:start])
+			b.WriteString(h.redact(s[start:end]))
+			last = end
+		}
+		b.WriteString(s[last:])
+		s = b.String()
+		changed = true
+	}
+	return s, changed
+}
+
+// redact produces the replacement text for a single sensitive value.
+func (h *RedactingHandler) redact(v string) string {
+	if h.mode == RedactModeHash {
+		sum := sha256.Sum256([]byte(h.salt + v))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	}
+	return RedactedMask
+}
+
+// redactOptionsFromConfig translates config.LoggerRedaction into RedactOptions.
+// Unknown matcher names are ignored so a typo cannot disable the remaining matchers.
+func redactOptionsFromConfig(cfg config.LoggerRedaction) *RedactOptions {
+	opts := &RedactOptions{
+		Mode:     RedactMode(cfg.Mode),
+		HashSalt: cfg.HashSalt,
+	}
+	if len(cfg.Keys) > 0 {
+		opts.Keys = cfg.Keys
+	}
+	if len(cfg.Matchers) > 0 {
+		opts.Matchers = []ValueMatcher{}
+		for _, name := range cfg.Matchers {
+			for _, m := range DefaultValueMatchers {
+				if m.Name == name {
+					opts.Matchers = append(opts.Matchers, m)
+				}
+			}
+		}
+	}
+	return opts
+}
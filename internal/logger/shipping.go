@@ -0,0 +1,404 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+)
+
+// Default shipping parameters applied when a config.LogSink leaves a value at zero.
+const (
+	defaultSinkBatchSize     = 100                    // Records per outbound batch
+	defaultSinkBufferSize    = 10000                  // Records buffered before dropping
+	defaultSinkFlushInterval = 2 * time.Second        // Maximum delay before a partial batch is sent
+	defaultSinkMaxRetries    = 3                      // Delivery attempts after the first failure
+	defaultSinkRetryBackoff  = 200 * time.Millisecond // Initial backoff, doubled per attempt
+	defaultSinkTimeout       = 5 * time.Second        // Per-attempt network timeout
+	defaultSinkAppName       = "goedu-theta"          // Application name reported to collectors
+)
+
+// ErrShipperClosed is returned by Flush once the shipping handler has been closed.
+var ErrShipperClosed = errors.New("log shipper closed")
+
+// Entry is a log record flattened for remote delivery. Group names are folded
+// into attribute keys with "." separators, as collectors have no group concept.
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   []slog.Attr // Flattened, resolved attributes (no groups)
+}
+
+// ShipperStats reports delivery counters for a shipping handler.
+type ShipperStats struct {
+	Type    string // Sink type ("syslog", "gelf" or "otlp")
+	Queued  int    // Records currently waiting in the buffer
+	Sent    uint64 // Records delivered successfully
+	Dropped uint64 // Records discarded because the buffer was full or the handler closed
+	Failed  uint64 // Records discarded after exhausting retries
+	Retries uint64 // Delivery retries performed
+}
+
+// transport delivers batches to one remote collector. Implementations live in
+// sink_syslog.go, sink_gelf.go and sink_otlp.go.
+type transport interface {
+	// send delivers a batch. Errors wrapped with permanentError are not retried.
+	send(ctx context.Context, batch []Entry) error
+	close() error
+}
+
+// permanentError marks delivery failures that retrying cannot fix (e.g. HTTP 400).
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// shipper owns the bounded buffer and the background delivery goroutine shared
+// by a ShippingHandler and all handlers derived from it via WithAttrs/WithGroup.
+type shipper struct {
+	name          string
+	transport     transport
+	queue         chan Entry
+	flushReq      chan chan struct{}
+	done          chan struct{}
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	retryBackoff  time.Duration
+	timeout       time.Duration
+
+	mu     sync.RWMutex // Guards closed against concurrent enqueue
+	closed bool
+
+	sent    atomic.Uint64
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+	retries atomic.Uint64
+}
+
+// ShippingHandler is a slog.Handler that ships records to a remote collector
+// (syslog, GELF or OTLP) through a bounded buffer with batching and retries.
+//
+// Handle never blocks on the network: when the buffer is full the record is
+// dropped and counted in ShipperStats.Dropped. Call Flush to force delivery and
+// Close to flush and release the connection.
+type ShippingHandler struct {
+	s      *shipper
+	level  slog.Leveler
+	attrs  []slog.Attr // Pre-flattened attributes from WithAttrs
+	prefix string      // Current group prefix ("a.b.") from WithGroup
+}
+
+// NewSinkHandler creates a shipping handler for the sink described by cfg.
+//
+// Args:
+//
+//	cfg: Sink configuration (type, address, batching and retry parameters)
+//	defaultLevel: Minimum level used when cfg.Level is empty; a *slog.LevelVar follows runtime level changes
+//
+// Returns:
+//
+//	*ShippingHandler ready for use, or an error for unknown types or unreachable addresses
+//
+// Example:
+//
+//	h, err := NewSinkHandler(config.LogSink{Type: "gelf", Address: "graylog:12201"}, slog.LevelInfo)
+func NewSinkHandler(cfg config.LogSink, defaultLevel slog.Leveler) (*ShippingHandler, error) {
+	appName := cfg.AppName
+	if appName == "" {
+		appName = defaultSinkAppName
+	}
+	timeout := durationOr(cfg.TimeoutMs, defaultSinkTimeout)
+
+	var (
+		t   transport
+		err error
+	)
+	switch strings.ToLower(cfg.Type) {
+	case "syslog":
+		t, err = newSyslogTransport(cfg.Address, cfg.Facility, appName, timeout)
+	case "gelf":
+		t, err = newGELFTransport(cfg.Address, appName)
+	case "otlp":
+		t, err = newOTLPTransport(cfg.Address, appName, cfg.Headers, timeout)
+	default:
+		return nil, fmt.Errorf("unknown log sink type %q", cfg.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s log sink: %w", cfg.Type, err)
+	}
+
+	level := defaultLevel
+	if cfg.Level != "" {
+		level = ParseLevel(cfg.Level)
+	}
+	return newShippingHandler(cfg.Type, t, cfg, level), nil
+}
+
+// newShippingHandler starts the delivery goroutine for t.
+func newShippingHandler(name string, t transport, cfg config.LogSink, level slog.Leveler) *ShippingHandler {
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultSinkBufferSize
+	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultSinkBatchSize
+	}
+	maxRetries := cfg.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultSinkMaxRetries
+	} else if maxRetries < 0 {
+		maxRetries = 0 // Negative disables retries entirely
+	}
+
+	s := &shipper{
+		name:          name,
+		transport:     t,
+		queue:         make(chan Entry, bufferSize),
+		flushReq:      make(chan chan struct{}),
+		done:          make(chan struct{}),
+		batchSize:     batchSize,
+		flushInterval: durationOr(cfg.FlushIntervalMs, defaultSinkFlushInterval),
+		maxRetries:    maxRetries,
+		retryBackoff:  durationOr(cfg.RetryBackoffMs, defaultSinkRetryBackoff),
+		timeout:       durationOr(cfg.TimeoutMs, defaultSinkTimeout),
+	}
+	go s.run()
+	return &ShippingHandler{s: s, level: level}
+}
+
+// Enabled implements slog.Handler.
+func (h *ShippingHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle implements slog.Handler.
+// Flattens the record and enqueues it without blocking.
+func (h *ShippingHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, len(h.attrs), len(h.attrs)+r.NumAttrs())
+	copy(attrs, h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = flattenAttr(attrs, h.prefix, a)
+		return true
+	})
+	h.s.enqueue(Entry{Time: r.Time, Level: r.Level, Message: r.Message, Attrs: attrs})
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h *ShippingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		clone.attrs = flattenAttr(clone.attrs, h.prefix, a)
+	}
+	return &clone
+}
+
+// WithGroup implements slog.Handler.
+func (h *ShippingHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// Stats returns a snapshot of the delivery counters.
+func (h *ShippingHandler) Stats() ShipperStats {
+	return ShipperStats{
+		Type:    h.s.name,
+		Queued:  len(h.s.queue),
+		Sent:    h.s.sent.Load(),
+		Dropped: h.s.dropped.Load(),
+		Failed:  h.s.failed.Load(),
+		Retries: h.s.retries.Load(),
+	}
+}
+
+// Flush delivers everything buffered so far and waits for completion or ctx expiry.
+func (h *ShippingHandler) Flush(ctx context.Context) error {
+	req := make(chan struct{})
+	select {
+	case h.s.flushReq <- req:
+	case <-h.s.done:
+		return ErrShipperClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-req:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes buffered records, stops the delivery goroutine and closes the
+// connection. Records logged after Close are counted as dropped.
+func (h *ShippingHandler) Close(ctx context.Context) error {
+	s := h.s
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		return fmt.Errorf("%s log sink did not drain before deadline: %w", s.name, ctx.Err())
+	}
+	return s.transport.close()
+}
+
+// enqueue adds e to the buffer, dropping it when the buffer is full or closed.
+func (s *shipper) enqueue(e Entry) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		s.dropped.Add(1)
+		return
+	}
+	select {
+	case s.queue <- e:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// run is the delivery loop. It sends a batch when it is full, when the flush
+// interval elapses, on explicit Flush requests, and once more on Close.
+func (s *shipper) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Entry, 0, s.batchSize)
+	for {
+		select {
+		case e, ok := <-s.queue:
+			if !ok {
+				s.deliver(batch)
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) >= s.batchSize {
+				s.deliver(batch)
+				batch = batch[:0]
+			}
+
+		case <-ticker.C:
+			s.deliver(batch)
+			batch = batch[:0]
+
+		case req := <-s.flushReq:
+			// Drain whatever is buffered right now, then acknowledge.
+		drain:
+			for {
+				select {
+				case e, ok := <-s.queue:
+					if !ok {
+						break drain
+					}
+					batch = append(batch, e)
+					if len(batch) >= s.batchSize {
+						s.deliver(batch)
+						batch = batch[:0]
+					}
+				default:
+					break drain
+				}
+			}
+			s.deliver(batch)
+			batch = batch[:0]
+			close(req)
+		}
+	}
+}
+
+// deliver sends batch with exponential backoff, accounting for the outcome.
+func (s *shipper) deliver(batch []Entry) {
+	if len(batch) == 0 {
+		return
+	}
+	backoff := s.retryBackoff
+	var err error
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		if attempt > 0 {
+			s.retries.Add(1)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		err = s.transport.send(ctx, batch)
+		cancel()
+		if err == nil {
+			s.sent.Add(uint64(len(batch)))
+			return
+		}
+		var perm permanentError
+		if errors.As(err, &perm) {
+			break
+		}
+	}
+	s.failed.Add(uint64(len(batch)))
+	// The application logger may itself be shipping here, so report on stderr
+	// directly to avoid feeding the failure back into the failing sink.
+	fmt.Fprintf(os.Stderr, "log sink %s: dropped %d records: %v\n", s.name, len(batch), err)
+}
+
+// flattenAttr appends a to dst, expanding groups into prefixed keys.
+func flattenAttr(dst []slog.Attr, prefix string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		p := prefix
+		if a.Key != "" {
+			p = prefix + a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			dst = flattenAttr(dst, p, ga)
+		}
+		return dst
+	}
+	if a.Key == "" {
+		return dst
+	}
+	return append(dst, slog.Attr{Key: prefix + a.Key, Value: a.Value})
+}
+
+// durationOr converts a millisecond setting to a duration, using def when unset.
+func durationOr(ms int, def time.Duration) time.Duration {
+	if ms <= 0 {
+		return def
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// attrString renders an attribute value as plain text for text-only protocols.
+func attrString(v slog.Value) string {
+	if v.Kind() == slog.KindTime {
+		return v.Time().Format(time.RFC3339Nano)
+	}
+	return v.String()
+}
+
+// hostname returns the local host name, or "-" (the RFC 5424 NILVALUE) if unknown.
+func hostname() string {
+	h, err := os.Hostname()
+	if err != nil || h == "" {
+		return "-"
+	}
+	return h
+}
@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// GELF UDP chunking limits from the Graylog specification.
+const (
+	gelfChunkSize      = 1420 // Payload bytes per datagram, safe for typical WAN MTUs
+	gelfChunkHeaderLen = 12   // Magic (2) + message ID (8) + sequence number (1) + count (1)
+	gelfMaxChunks      = 128  // Messages needing more chunks are rejected by Graylog
+)
+
+// gelfChunkMagic prefixes every chunked GELF datagram.
+var gelfChunkMagic = []byte{0x1e, 0x0f}
+
+// gelfFieldName matches the characters Graylog accepts in additional field names.
+var gelfFieldName = regexp.MustCompile(`[^\w.\-]`)
+
+// gelfTransport sends GELF 1.1 messages over UDP, chunking payloads that do
+// not fit into a single datagram.
+type gelfTransport struct {
+	appName string
+	host    string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// newGELFTransport dials the Graylog GELF UDP input at address ("host:12201").
+func newGELFTransport(address, appName string) (*gelfTransport, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial GELF endpoint %q: %w", address, err)
+	}
+	return &gelfTransport{appName: appName, host: hostname(), conn: conn}, nil
+}
+
+func (t *gelfTransport) send(ctx context.Context, batch []Entry) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = t.conn.SetWriteDeadline(deadline)
+	}
+	for _, e := range batch {
+		payload, err := json.Marshal(t.message(e))
+		if err != nil {
+			return permanentError{fmt.Errorf("failed to encode GELF message: %w", err)}
+		}
+		if err := t.write(payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write sends payload as a single datagram or as a sequence of GELF chunks.
+func (t *gelfTransport) write(payload []byte) error {
+	if len(payload) <= gelfChunkSize {
+		_, err := t.conn.Write(payload)
+		return err
+	}
+
+	count := (len(payload) + gelfChunkSize - 1) / gelfChunkSize
+	if count > gelfMaxChunks {
+		return permanentError{fmt.Errorf("GELF message of %d bytes exceeds %d chunks", len(payload), gelfMaxChunks)}
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	chunk := make([]byte, 0, gelfChunkHeaderLen+gelfChunkSize)
+	for seq := 0; seq < count; seq++ {
+		end := (seq + 1) * gelfChunkSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		chunk = append(chunk[:0], gelfChunkMagic...)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(seq), byte(count))
+		chunk = append(chunk, payload[seq*gelfChunkSize:end]...)
+		if _, err := t.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *gelfTransport) close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.conn.Close()
+}
+
+// message builds the GELF 1.1 document for e. Attributes become additional
+// fields prefixed with "_"; "_id" is reserved by Graylog and renamed.
+func (t *gelfTransport) message(e Entry) map[string]interface{} {
+	msg := map[string]interface{}{
+		"version":       "1.1",
+		"host":          t.host,
+		"short_message": e.Message,
+		"timestamp":     float64(e.Time.UnixNano()) / float64(time.Second),
+		"level":         syslogSeverity(e.Level),
+		"_app":          t.appName,
+		"_level_name":   LevelName(e.Level),
+	}
+	for _, a := range e.Attrs {
+		name := "_" + gelfFieldName.ReplaceAllString(a.Key, "_")
+		if name == "_id" {
+			name = "_id_"
+		}
+		msg[name] = gelfValue(a.Value)
+	}
+	return msg
+}
+
+// gelfValue keeps numbers numeric (Graylog indexes them as such) and renders
+// everything else as a string.
+func gelfValue(v slog.Value) interface{} {
+	switch v.Kind() {
+	case slog.KindInt64:
+		return v.Int64()
+	case slog.KindUint64:
+		return v.Uint64()
+	case slog.KindFloat64:
+		return v.Float64()
+	case slog.KindDuration:
+		return v.Duration().Milliseconds()
+	default:
+		return attrString(v)
+	}
+}
@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// otlpScopeName identifies this package as the instrumentation scope of exported logs.
+const otlpScopeName = "github.com/radek-zitek-cloud/goedu-theta/internal/logger"
+
+// otlpTransport exports batches to an OpenTelemetry collector using OTLP/HTTP
+// with the JSON encoding (POST {endpoint}, typically http://collector:4318/v1/logs).
+type otlpTransport struct {
+	endpoint string
+	headers  map[string]string
+	resource otlpResource
+	client   *http.Client
+}
+
+// newOTLPTransport validates endpoint and prepares the HTTP client. No request
+// is made until the first batch, as collectors expose no cheap probe endpoint.
+func newOTLPTransport(endpoint, appName string, headers map[string]string, timeout time.Duration) (*otlpTransport, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: expected http(s)://host:port/v1/logs", endpoint)
+	}
+	return &otlpTransport{
+		endpoint: endpoint,
+		headers:  headers,
+		resource: otlpResource{Attributes: []otlpKeyValue{
+			{Key: "service.name", Value: otlpAnyValue{StringValue: &appName}},
+			{Key: "host.name", Value: otlpString(hostname())},
+		}},
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+func (t *otlpTransport) send(ctx context.Context, batch []Entry) error {
+	records := make([]otlpLogRecord, len(batch))
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+	for i, e := range batch {
+		records[i] = otlpRecord(e, now)
+	}
+	body, err := json.Marshal(otlpExportRequest{ResourceLogs: []otlpResourceLogs{{
+		Resource:  t.resource,
+		ScopeLogs: []otlpScopeLogs{{Scope: otlpScope{Name: otlpScopeName}, LogRecords: records}},
+	}}})
+	if err != nil {
+		return permanentError{fmt.Errorf("failed to encode OTLP request: %w", err)}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("OTLP export failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable, resp.StatusCode == http.StatusGatewayTimeout:
+		// Retryable per the OTLP/HTTP specification.
+		return fmt.Errorf("OTLP collector returned %s", resp.Status)
+	default:
+		return permanentError{fmt.Errorf("OTLP collector rejected batch: %s", resp.Status)}
+	}
+}
+
+func (t *otlpTransport) close() error {
+	t.client.CloseIdleConnections()
+	return nil
+}
+
+// otlpRecord converts e into an OTLP LogRecord.
+func otlpRecord(e Entry, observed string) otlpLogRecord {
+	attrs := make([]otlpKeyValue, 0, len(e.Attrs))
+	for _, a := range e.Attrs {
+		attrs = append(attrs, otlpKeyValue{Key: a.Key, Value: otlpValue(a.Value)})
+	}
+	return otlpLogRecord{
+		TimeUnixNano:         strconv.FormatInt(e.Time.UnixNano(), 10),
+		ObservedTimeUnixNano: observed,
+		SeverityNumber:       otlpSeverity(e.Level),
+		SeverityText:         LevelName(e.Level),
+		Body:                 otlpString(e.Message),
+		Attributes:           attrs,
+	}
+}
+
+// otlpSeverity maps slog levels onto the OpenTelemetry SeverityNumber ranges
+// (TRACE 1-4, DEBUG 5-8, INFO 9-12, WARN 13-16, ERROR 17-20, FATAL 21-24).
+func otlpSeverity(level slog.Level) int {
+	switch {
+	case level >= LevelFatal:
+		return 21
+	case level >= slog.LevelError:
+		return 17
+	case level >= slog.LevelWarn:
+		return 13
+	case level >= slog.LevelInfo:
+		return 9
+	case level >= slog.LevelDebug:
+		return 5
+	default:
+		return 1 // TRACE
+	}
+}
+
+// otlpValue converts a slog value into an OTLP AnyValue.
+func otlpValue(v slog.Value) otlpAnyValue {
+	switch v.Kind() {
+	case slog.KindBool:
+		b := v.Bool()
+		return otlpAnyValue{BoolValue: &b}
+	case slog.KindInt64:
+		s := strconv.FormatInt(v.Int64(), 10)
+		return otlpAnyValue{IntValue: &s}
+	case slog.KindUint64:
+		s := strconv.FormatUint(v.Uint64(), 10)
+		return otlpAnyValue{IntValue: &s}
+	case slog.KindFloat64:
+		f := v.Float64()
+		return otlpAnyValue{DoubleValue: &f}
+	default:
+		return otlpString(attrString(v))
+	}
+}
+
+func otlpString(s string) otlpAnyValue { return otlpAnyValue{StringValue: &s} }
+
+// OTLP/JSON wire types (opentelemetry-proto logs/v1, JSON mapping). 64-bit
+// integers are encoded as decimal strings as the protobuf JSON mapping requires.
+type (
+	otlpExportRequest struct {
+		ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+	}
+	otlpResourceLogs struct {
+		Resource  otlpResource    `json:"resource"`
+		ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeLogs struct {
+		Scope      otlpScope       `json:"scope"`
+		LogRecords []otlpLogRecord `json:"logRecords"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpLogRecord struct {
+		TimeUnixNano         string         `json:"timeUnixNano"`
+		ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+		SeverityNumber       int            `json:"severityNumber"`
+		SeverityText         string         `json:"severityText"`
+		Body                 otlpAnyValue   `json:"body"`
+		Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpAnyValue struct {
+		StringValue *string  `json:"stringValue,omitempty"`
+		BoolValue   *bool    `json:"boolValue,omitempty"`
+		IntValue    *string  `json:"intValue,omitempty"`
+		DoubleValue *float64 `json:"doubleValue,omitempty"`
+	}
+)
@@ -0,0 +1,200 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syslogFacilities maps facility names accepted in config.LogSink.Facility to codes.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogStructuredDataID is the SD-ID under which attributes are reported.
+// The "@32473" suffix is the IANA example enterprise number reserved for documentation.
+const syslogStructuredDataID = "goedu@32473"
+
+// syslogTransport writes RFC 5424 messages over UDP, TCP or a unix socket.
+//
+// Datagram transports (udp, unix/unixgram) send one message per packet. Stream
+// transports (tcp, unixstream) use RFC 6587 octet-counting framing and
+// reconnect lazily after a write error.
+type syslogTransport struct {
+	network  string
+	addr     string
+	facility int
+	appName  string
+	host     string
+	procID   string
+	timeout  time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// newSyslogTransport parses address ("udp://host:514", "tcp://host:601",
+// "unix:///dev/log", "unixstream:///run/syslog.sock") and dials the collector.
+func newSyslogTransport(address, facility, appName string, timeout time.Duration) (*syslogTransport, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid syslog address %q: %w", address, err)
+	}
+	t := &syslogTransport{
+		appName: appName,
+		host:    hostname(),
+		procID:  strconv.Itoa(os.Getpid()),
+		timeout: timeout,
+	}
+	switch u.Scheme {
+	case "udp", "tcp":
+		t.network, t.addr = u.Scheme, u.Host
+	case "unix", "unixgram":
+		t.network, t.addr = "unixgram", u.Path
+	case "unixstream":
+		t.network, t.addr = "unix", u.Path
+	default:
+		return nil, fmt.Errorf("unsupported syslog scheme %q (use udp, tcp, unix or unixstream)", u.Scheme)
+	}
+	if t.addr == "" {
+		return nil, fmt.Errorf("syslog address %q has no host or path", address)
+	}
+
+	t.facility = syslogFacilities["local0"]
+	if facility != "" {
+		code, ok := syslogFacilities[strings.ToLower(facility)]
+		if !ok {
+			return nil, fmt.Errorf("unknown syslog facility %q", facility)
+		}
+		t.facility = code
+	}
+
+	if err := t.connect(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// connect (re)establishes the connection; callers must hold t.mu or own t exclusively.
+func (t *syslogTransport) connect() error {
+	conn, err := net.DialTimeout(t.network, t.addr, t.timeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog at %s://%s: %w", t.network, t.addr, err)
+	}
+	t.conn = conn
+	return nil
+}
+
+func (t *syslogTransport) send(ctx context.Context, batch []Entry) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn == nil {
+		if err := t.connect(); err != nil {
+			return err
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = t.conn.SetWriteDeadline(deadline)
+	}
+
+	stream := t.network == "tcp" || t.network == "unix"
+	for i, e := range batch {
+		msg := t.format(e)
+		if stream {
+			msg = strconv.Itoa(len(msg)) + " " + msg
+		}
+		if _, err := t.conn.Write([]byte(msg)); err != nil {
+			// Drop the broken connection; the retry will redial. Entries already
+			// written are resent on retry, which syslog consumers tolerate better
+			// than silent loss.
+			t.conn.Close()
+			t.conn = nil
+			return fmt.Errorf("syslog write failed at record %d of %d: %w", i+1, len(batch), err)
+		}
+	}
+	return nil
+}
+
+func (t *syslogTransport) close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
+
+// format renders e as an RFC 5424 message:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD-ID key="value"...] MSG
+func (t *syslogTransport) format(e Entry) string {
+	var b strings.Builder
+	pri := t.facility*8 + syslogSeverity(e.Level)
+	fmt.Fprintf(&b, "<%d>1 %s %s %s %s - ",
+		pri, e.Time.UTC().Format(time.RFC3339Nano), t.host, t.appName, t.procID)
+
+	if len(e.Attrs) == 0 {
+		b.WriteString("-")
+	} else {
+		b.WriteString("[" + syslogStructuredDataID)
+		for _, a := range e.Attrs {
+			fmt.Fprintf(&b, ` %s="%s"`, syslogParamName(a.Key), syslogEscape(attrString(a.Value)))
+		}
+		b.WriteString("]")
+	}
+	b.WriteString(" ")
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// syslogSeverity maps slog levels onto RFC 5424 severities.
+func syslogSeverity(level slog.Level) int {
+	switch {
+	case level >= LevelFatal:
+		return 2 // Critical
+	case level >= slog.LevelError:
+		return 3 // Error
+	case level >= slog.LevelWarn:
+		return 4 // Warning
+	case level >= slog.LevelInfo:
+		return 6 // Informational
+	default:
+		return 7 // Debug
+	}
+}
+
+// syslogParamName sanitizes an attribute key into a valid SD-PARAM name
+// (printable US-ASCII without '=', ' ', ']' or '"', at most 32 characters).
+func syslogParamName(key string) string {
+	b := []byte(key)
+	for i, c := range b {
+		if c < 33 || c > 126 || c == '=' || c == ']' || c == '"' {
+			b[i] = '_'
+		}
+	}
+	if len(b) > 32 {
+		b = b[:32]
+	}
+	if len(b) == 0 {
+		return "_"
+	}
+	return string(b)
+}
+
+// syslogEscape escapes '"', '\' and ']' inside PARAM-VALUE as required by RFC 5424.
+func syslogEscape(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(v)
+}
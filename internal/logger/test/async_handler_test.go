@@ -0,0 +1,196 @@
+package logger_test
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/logger"
+)
+
+// gateHandler records messages and blocks every write until the gate is opened.
+type gateHandler struct {
+	mu       sync.Mutex
+	messages []string
+	gate     chan struct{}
+	started  chan struct{}
+}
+
+func newGateHandler(open bool) *gateHandler {
+	h := &gateHandler{gate: make(chan struct{}), started: make(chan struct{}, 1)}
+	if open {
+		close(h.gate)
+	}
+	return h
+}
+
+func (h *gateHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *gateHandler) Handle(_ context.Context, r slog.Record) error {
+	select {
+	case h.started <- struct{}{}:
+	default:
+	}
+	<-h.gate
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.messages = append(h.messages, r.Message)
+	return nil
+}
+
+func (h *gateHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *gateHandler) WithGroup(string) slog.Handler      { return h }
+
+func (h *gateHandler) Messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.messages...)
+}
+
+// closeAsync closes an async handler with a bounded timeout.
+func closeAsync(t *testing.T, h *logger.AsyncHandler) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.Close(ctx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
+
+// fillQueue occupies the worker with one record and then fills the queue.
+func fillQueue(t *testing.T, log *slog.Logger, inner *gateHandler, level slog.Level, n int) {
+	t.Helper()
+	log.Info("in flight")
+	select {
+	case <-inner.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Worker never picked up the first record")
+	}
+	for i := 0; i < n; i++ {
+		log.Log(context.Background(), level, "queued")
+	}
+}
+
+func TestAsyncHandler_WritesInOrder(t *testing.T) {
+	inner := newGateHandler(true)
+	h := logger.NewAsyncHandler(inner, 16, logger.OverflowBlock)
+	defer closeAsync(t, h)
+
+	log := slog.New(h)
+	for _, msg := range []string{"a", "b", "c", "d"} {
+		log.Info(msg)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if got := strings.Join(inner.Messages(), ""); got != "abcd" {
+		t.Errorf("Expected records in order, got %q", got)
+	}
+	if stats := h.Stats(); stats.Processed != 4 || stats.Capacity != 16 || stats.QueueDepth != 0 {
+		t.Errorf("Unexpected stats after flush: %+v", stats)
+	}
+}
+
+func TestAsyncHandler_DropNewest(t *testing.T) {
+	inner := newGateHandler(false)
+	h := logger.NewAsyncHandler(inner, 2, logger.OverflowDropNewest)
+	log := slog.New(h)
+
+	fillQueue(t, log, inner, slog.LevelInfo, 5)
+	stats := h.Stats()
+	if stats.Dropped != 3 || stats.QueueDepth != 2 {
+		t.Errorf("Expected 3 dropped and 2 queued records, got %+v", stats)
+	}
+
+	close(inner.gate)
+	closeAsync(t, h)
+	if got := len(inner.Messages()); got != 3 {
+		t.Errorf("Expected 3 written records, got %d", got)
+	}
+}
+
+func TestAsyncHandler_DropDebugFirst(t *testing.T) {
+	inner := newGateHandler(false)
+	h := logger.NewAsyncHandler(inner, 2, logger.OverflowDropDebugFirst)
+	log := slog.New(h)
+
+	fillQueue(t, log, inner, slog.LevelDebug, 2)
+	log.Debug("debug overflow") // Dropped: incoming debug record
+	log.Error("important")      // Evicts the oldest queued debug record
+
+	if stats := h.Stats(); stats.Dropped != 2 || stats.QueueDepth != 2 {
+		t.Errorf("Expected 2 dropped and 2 queued records, got %+v", stats)
+	}
+
+	close(inner.gate)
+	closeAsync(t, h)
+	got := inner.Messages()
+	if len(got) != 3 || got[2] != "important" {
+		t.Errorf("Expected error record to survive overflow, got %v", got)
+	}
+}
+
+func TestAsyncHandler_BlockWaitsForSpace(t *testing.T) {
+	inner := newGateHandler(false)
+	h := logger.NewAsyncHandler(inner, 1, logger.OverflowBlock)
+	log := slog.New(h)
+
+	fillQueue(t, log, inner, slog.LevelInfo, 1)
+
+	done := make(chan struct{})
+	go func() {
+		log.Info("waiting")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Expected caller to block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(inner.gate)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Blocked caller was never released")
+	}
+	closeAsync(t, h)
+
+	stats := h.Stats()
+	if stats.Blocked != 1 || stats.Dropped != 0 || stats.Processed != 3 {
+		t.Errorf("Unexpected stats for block policy: %+v", stats)
+	}
+}
+
+func TestAsyncHandler_WritesSynchronouslyAfterClose(t *testing.T) {
+	inner := newGateHandler(true)
+	h := logger.NewAsyncHandler(inner, 4, logger.OverflowBlock)
+	closeAsync(t, h)
+
+	slog.New(h).Info("after close")
+	if got := inner.Messages(); len(got) != 1 || got[0] != "after close" {
+		t.Errorf("Expected synchronous write after Close, got %v", got)
+	}
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	testCases := map[string]logger.OverflowPolicy{
+		"":                 logger.OverflowBlock,
+		"block":            logger.OverflowBlock,
+		"drop-newest":      logger.OverflowDropNewest,
+		"DROP_DEBUG_FIRST": logger.OverflowDropDebugFirst,
+		"unknown":          logger.OverflowBlock,
+	}
+	for name, want := range testCases {
+		if got := logger.ParseOverflowPolicy(name); got != want {
+			t.Errorf("ParseOverflowPolicy(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/logger"
+)
+
+// fatalHelperEnv makes the test binary act as a process that logs at FATAL.
+const fatalHelperEnv = "GOEDU_LOGGER_FATAL_HELPER"
+
+func TestParseLevel_CustomLevels(t *testing.T) {
+	testCases := []struct {
+		name string
+		want slog.Level
+	}{
+		{"trace", logger.LevelTrace},
+		{"TRACE", logger.LevelTrace},
+		{"debug", slog.LevelDebug},
+		{"Error", slog.LevelError},
+		{"fatal", logger.LevelFatal},
+		{"verbose", slog.LevelInfo},
+	}
+
+	for _, tc := range testCases {
+		if got := logger.ParseLevel(tc.name); got != tc.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLevelName(t *testing.T) {
+	testCases := []struct {
+		level slog.Level
+		want  string
+	}{
+		{logger.LevelTrace, "TRACE"},
+		{logger.LevelTrace - 2, "TRACE-2"},
+		{slog.LevelDebug - 2, "TRACE+2"},
+		{slog.LevelDebug, "DEBUG"},
+		{slog.LevelError + 2, "ERROR+2"},
+		{logger.LevelFatal, "FATAL"},
+		{logger.LevelFatal + 4, "FATAL+4"},
+	}
+
+	for _, tc := range testCases {
+		if got := logger.LevelName(tc.level); got != tc.want {
+			t.Errorf("LevelName(%d) = %q, want %q", tc.level, got, tc.want)
+		}
+	}
+}
+
+func TestPrettyConsoleHandler_CustomLevelLabels(t *testing.T) {
+	for layout, want := range map[logger.PrettyLayout][]string{
+		logger.LayoutExpanded: {" TRACE wire detail", " FATAL giving up"},
+		logger.LayoutCompact:  {" TRC wire detail", " FTL giving up"},
+	} {
+		var buf bytes.Buffer
+		h := logger.NewPrettyConsoleHandlerWithOptions(&buf,
+			&slog.HandlerOptions{Level: logger.LevelTrace},
+			&logger.PrettyOptions{Color: logger.ColorNever, Layout: layout})
+		log := slog.New(h)
+		log.Log(context.Background(), logger.LevelTrace, "wire detail")
+		log.Log(context.Background(), logger.LevelFatal, "giving up")
+
+		for _, w := range want {
+			if !strings.Contains(buf.String(), w) {
+				t.Errorf("%s layout: expected %q in output, got %q", layout, w, buf.String())
+			}
+		}
+	}
+}
+
+// TestFatal_LogsAndExits re-runs the test binary as a child process that
+// configures JSON logging at trace level and calls Fatal.
+func TestFatal_LogsAndExits(t *testing.T) {
+	if os.Getenv(fatalHelperEnv) == "1" {
+		logger.ConfigureLogger(config.Logger{Level: "trace", Format: "json", Output: "stdout"})
+		logger.Trace("wire detail", "command", "find")
+		logger.Fatal("giving up", slog.Any("error", errors.New("boom")))
+		return // Unreachable: Fatal exits the process
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestFatal_LogsAndExits$")
+	cmd.Env = append(os.Environ(), fatalHelperEnv+"=1")
+	out, err := cmd.Output()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("Expected exit status 1, got %v (output %q)", err, out)
+	}
+	for _, want := range []string{`"level":"TRACE"`, `"level":"FATAL"`, `"msg":"giving up"`} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("Expected %s in output, got %q", want, out)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package logger_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/logger"
+)
+
+// newPretty creates a pretty handler writing to a buffer.
+func newPretty(pretty *logger.PrettyOptions) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	h := logger.NewPrettyConsoleHandlerWithOptions(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}, pretty)
+	return slog.New(h), &buf
+}
+
+func TestPrettyConsoleHandler_ColorDetection(t *testing.T) {
+	testCases := []struct {
+		name      string
+		mode      logger.ColorMode
+		noColor   string
+		force     string
+		wantColor bool
+	}{
+		{name: "auto on non-terminal", mode: logger.ColorAuto},
+		{name: "FORCE_COLOR on non-terminal", mode: logger.ColorAuto, force: "1", wantColor: true},
+		{name: "FORCE_COLOR=0", mode: logger.ColorAuto, force: "0"},
+		{name: "NO_COLOR beats FORCE_COLOR", mode: logger.ColorAuto, noColor: "1", force: "1"},
+		{name: "always", mode: logger.ColorAlways, noColor: "1", wantColor: true},
+		{name: "never", mode: logger.ColorNever, force: "1"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tc.noColor)
+			t.Setenv("FORCE_COLOR", tc.force)
+
+			log, buf := newPretty(&logger.PrettyOptions{Color: tc.mode})
+			log.Info("hello")
+			if got := strings.Contains(buf.String(), "\033["); got != tc.wantColor {
+				t.Errorf("Expected color=%v, got output %q", tc.wantColor, buf.String())
+			}
+		})
+	}
+}
+
+func TestPrettyConsoleHandler_ExpandedAlignsKeys(t *testing.T) {
+	log, buf := newPretty(&logger.PrettyOptions{Layout: logger.LayoutExpanded})
+	log.Info("Starting server", "port", 8080, "environment", "dev")
+
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) < 3 {
+		t.Fatalf("Expected header and two attribute lines, got %q", buf.String())
+	}
+	if !strings.HasSuffix(lines[0], "INFO  Starting server") {
+		t.Errorf("Unexpected header line: %q", lines[0])
+	}
+	if lines[1] != "    port        = 8080" || lines[2] != "    environment = dev" {
+		t.Errorf("Expected aligned key/value columns, got %q and %q", lines[1], lines[2])
+	}
+}
+
+func TestPrettyConsoleHandler_GroupsAndAttrs(t *testing.T) {
+	log, buf := newPretty(&logger.PrettyOptions{Layout: logger.LayoutExpanded})
+	log.With("service", "api").WithGroup("req").With("method", "GET").Info("handled", "path", "/health")
+
+	want := "    service = api\n    req:\n        method = GET\n        path   = /health\n"
+	if !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("Expected merged group block %q, got %q", want, buf.String())
+	}
+}
+
+func TestPrettyConsoleHandler_MultiLineValues(t *testing.T) {
+	type course struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	}
+	log, buf := newPretty(&logger.PrettyOptions{Layout: logger.LayoutExpanded})
+	log.Error("failed",
+		"err", errors.Join(errors.New("first"), errors.New("second")),
+		"course", course{ID: 7, Title: "Go"},
+		"stack", "goroutine 1 [running]:\nmain.main()",
+	)
+
+	out := buf.String()
+	for _, want := range []string{
+		"    err    =\n        │ first\n        │ second\n",
+		"        │   \"id\": 7,\n",
+		"    stack  =\n        │ goroutine 1 [running]:\n        │ main.main()\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrettyConsoleHandler_Compact(t *testing.T) {
+	log, buf := newPretty(&logger.PrettyOptions{Layout: logger.LayoutCompact})
+	log.WithGroup("req").Warn("slow request", "path", "/a b", "ms", 1200, "err", errors.New("line1\nline2"))
+
+	out := buf.String()
+	if strings.Count(out, "\n") != 1 {
+		t.Fatalf("Expected a single line, got %q", out)
+	}
+	want := ` WRN slow request req.path="/a b" req.ms=1200 req.err="line1\nline2"` + "\n"
+	if !strings.HasSuffix(out, want) {
+		t.Errorf("Expected %q, got %q", want, out)
+	}
+}
+
+func TestPrettyConsoleHandler_AutoLayoutUsesCompactOnNarrowTerminal(t *testing.T) {
+	t.Setenv("COLUMNS", "80")
+	log, buf := newPretty(&logger.PrettyOptions{CompactWidth: 100})
+	log.Info("narrow", "k", "v")
+	if !strings.Contains(buf.String(), "INF narrow k=v") {
+		t.Errorf("Expected compact output for an 80-column terminal, got %q", buf.String())
+	}
+}
@@ -0,0 +1,278 @@
+package logger_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/logger"
+)
+
+// newSink creates a sink handler and registers cleanup that closes it.
+func newSink(t *testing.T, cfg config.LogSink) *logger.ShippingHandler {
+	t.Helper()
+	h, err := logger.NewSinkHandler(cfg, slog.LevelDebug)
+	if err != nil {
+		t.Fatalf("Failed to create %s sink: %v", cfg.Type, err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		h.Close(ctx)
+	})
+	return h
+}
+
+// flush forces delivery of buffered records.
+func flush(t *testing.T, h *logger.ShippingHandler) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.Flush(ctx); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+}
+
+// readDatagram reads one UDP datagram with a deadline.
+func readDatagram(t *testing.T, conn net.PacketConn) []byte {
+	t.Helper()
+	buf := make([]byte, 65535)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("Failed to read datagram: %v", err)
+	}
+	return buf[:n]
+}
+
+func TestSyslogSink_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	h := newSink(t, config.LogSink{Type: "syslog", Address: "udp://" + conn.LocalAddr().String(), Facility: "local3", AppName: "theta"})
+	slog.New(h).WithGroup("req").Warn("slow request", "path", `/a"b]`)
+	flush(t, h)
+
+	msg := string(readDatagram(t, conn))
+	// local3 (19) * 8 + warning (4) = 156
+	if !strings.HasPrefix(msg, "<156>1 ") {
+		t.Errorf("Unexpected PRI/version prefix: %s", msg)
+	}
+	if !strings.Contains(msg, " theta ") {
+		t.Errorf("Expected APP-NAME in message: %s", msg)
+	}
+	if !strings.Contains(msg, `[goedu@32473 req.path="/a\"b\]"]`) {
+		t.Errorf("Expected escaped structured data: %s", msg)
+	}
+	if !strings.HasSuffix(msg, " slow request") {
+		t.Errorf("Expected message at the end: %s", msg)
+	}
+}
+
+func TestSyslogSink_TCPOctetCounting(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			lenStr, err := r.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(lenStr))
+			frame := make([]byte, n)
+			if _, err := io.ReadFull(r, frame); err != nil {
+				return
+			}
+			received <- string(frame)
+		}
+	}()
+
+	h := newSink(t, config.LogSink{Type: "syslog", Address: "tcp://" + ln.Addr().String()})
+	log := slog.New(h)
+	log.Info("first")
+	log.Error("second")
+	flush(t, h)
+
+	for _, want := range []string{"first", "second"} {
+		select {
+		case msg := <-received:
+			if !strings.HasSuffix(msg, want) {
+				t.Errorf("Expected frame ending with %q, got %q", want, msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Timed out waiting for %q", want)
+		}
+	}
+}
+
+func TestGELFSink_FieldsAndChunking(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	h := newSink(t, config.LogSink{Type: "gelf", Address: conn.LocalAddr().String()})
+	log := slog.New(h)
+
+	log.Info("enrolled", "course_id", 42, "id", "x")
+	flush(t, h)
+
+	var msg map[string]interface{}
+	if err := json.Unmarshal(readDatagram(t, conn), &msg); err != nil {
+		t.Fatalf("Expected JSON GELF payload: %v", err)
+	}
+	if msg["version"] != "1.1" || msg["short_message"] != "enrolled" {
+		t.Errorf("Unexpected GELF envelope: %v", msg)
+	}
+	if msg["level"] != float64(6) {
+		t.Errorf("Expected syslog level 6 for INFO, got %v", msg["level"])
+	}
+	if msg["_course_id"] != float64(42) {
+		t.Errorf("Expected numeric additional field, got %v", msg["_course_id"])
+	}
+	if _, ok := msg["_id"]; ok {
+		t.Error("Reserved _id field must not be sent")
+	}
+
+	// A message larger than one datagram must arrive as GELF chunks.
+	log.Info(strings.Repeat("x", 5000))
+	flush(t, h)
+	chunk := readDatagram(t, conn)
+	if chunk[0] != 0x1e || chunk[1] != 0x0f {
+		t.Fatalf("Expected chunk magic bytes, got % x", chunk[:2])
+	}
+	if count := chunk[11]; count < 2 {
+		t.Errorf("Expected multiple chunks, got sequence count %d", count)
+	}
+}
+
+func TestOTLPSink_RetriesThenDelivers(t *testing.T) {
+	var calls atomic.Int32
+	var mu sync.Mutex
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test" {
+			t.Errorf("Expected configured header, got %q", r.Header.Get("Authorization"))
+		}
+		mu.Lock()
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	h := newSink(t, config.LogSink{
+		Type:           "otlp",
+		Address:        srv.URL + "/v1/logs",
+		Headers:        map[string]string{"Authorization": "Bearer test"},
+		RetryBackoffMs: 1,
+	})
+	slog.New(h).Error("export me", "attempt", 1)
+	flush(t, h)
+
+	stats := h.Stats()
+	if stats.Sent != 1 || stats.Retries != 1 || stats.Failed != 0 {
+		t.Errorf("Unexpected stats after retry: %+v", stats)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	raw, _ := json.Marshal(body)
+	for _, want := range []string{`"severityNumber":17`, `"stringValue":"export me"`, `"key":"attempt"`, `"service.name"`} {
+		if !strings.Contains(string(raw), want) {
+			t.Errorf("Expected %s in OTLP payload: %s", want, raw)
+		}
+	}
+}
+
+func TestOTLPSink_PermanentFailureIsNotRetried(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	h := newSink(t, config.LogSink{Type: "otlp", Address: srv.URL, RetryBackoffMs: 1})
+	slog.New(h).Info("rejected")
+	flush(t, h)
+
+	if stats := h.Stats(); stats.Failed != 1 || stats.Retries != 0 {
+		t.Errorf("Expected one failed record without retries, got %+v", stats)
+	}
+}
+
+func TestShippingHandler_DropsWhenBufferFull(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+	}))
+	defer srv.Close()
+
+	h := newSink(t, config.LogSink{Type: "otlp", Address: srv.URL, BatchSize: 1, BufferSize: 1})
+	log := slog.New(h)
+
+	// The first record occupies the delivery goroutine, the second fills the buffer.
+	log.Info("in flight")
+	<-started
+	for i := 0; i < 4; i++ {
+		log.Info("overflow")
+	}
+
+	if dropped := h.Stats().Dropped; dropped != 3 {
+		t.Errorf("Expected 3 dropped records, got %d", dropped)
+	}
+
+	// Unblock the collector so the buffered record is delivered before the server closes.
+	close(release)
+	flush(t, h)
+	if sent := h.Stats().Sent; sent != 2 {
+		t.Errorf("Expected 2 delivered records, got %d", sent)
+	}
+}
+
+func TestNewSinkHandler_RejectsInvalidConfig(t *testing.T) {
+	testCases := []config.LogSink{
+		{Type: "kafka", Address: "localhost:9092"},
+		{Type: "syslog", Address: "ftp://host"},
+		{Type: "syslog", Address: "udp://127.0.0.1:514", Facility: "nope"},
+		{Type: "otlp", Address: "collector:4318"},
+	}
+	for _, cfg := range testCases {
+		if _, err := logger.NewSinkHandler(cfg, slog.LevelInfo); err == nil {
+			t.Errorf("Expected error for %+v", cfg)
+		}
+	}
+}
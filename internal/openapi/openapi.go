@@ -0,0 +1,335 @@
+// Package openapi builds an OpenAPI 3.1 document from route metadata.
+//
+// Routes are described where they are registered, with Go values standing in
+// for request and response bodies; their types are turned into JSON Schemas
+// by reflection, following encoding/json field names. Named struct types
+// become shared component schemas, so a type used by several routes is
+// described once.
+//
+// Usage Examples:
+//
+//	doc := openapi.New(openapi.Info{Title: "GoEdu-Theta API", Version: "1.0.0"})
+//	doc.Add(http.MethodGet, "/courses/:id", openapi.Operation{
+//	    Summary:   "Get a course",
+//	    Responses: map[int]openapi.Response{
+//	        http.StatusOK:       {Description: "The course", Content: openapi.JSON(Course{})},
+//	        http.StatusNotFound: openapi.ProblemResponse("No such course"),
+//	    },
+//	})
+//	data, err := json.Marshal(doc)
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apperror"
+)
+
+// Version is the OpenAPI specification version of generated documents.
+const Version = "3.1.0"
+
+// Info describes the API as a whole.
+type Info struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+// Operation describes one method on one path.
+type Operation struct {
+	Summary     string
+	Description string
+	Tags        []string
+
+	// OperationID defaults to the method and path in camel case, e.g. "getCourseById".
+	OperationID string
+
+	// Parameters lists query and header parameters. Path parameters are added
+	// from the route pattern unless listed here.
+	Parameters []Parameter
+
+	// Request describes the accepted request body, one entry per media type.
+	Request []Content
+
+	// Responses maps status codes to responses. Every operation needs at least one.
+	Responses map[int]Response
+
+	// Deprecated marks operations clients should migrate away from.
+	Deprecated bool
+}
+
+// Parameter describes a path, query or header parameter.
+type Parameter struct {
+	Name        string
+	In          string // "path", "query" or "header"
+	Description string
+	Required    bool
+	Schema      any // Example value of the parameter's type; nil means string
+}
+
+// Response describes one response status of an operation.
+type Response struct {
+	Description string
+	Content     []Content         // Empty for responses without a body
+	Headers     map[string]string // Header name to description
+}
+
+// Content pairs a media type with an example value of the body's Go type.
+type Content struct {
+	MediaType string
+	Schema    any // nil means any body
+}
+
+// JSON describes an application/json body of the type of v.
+func JSON(v any) []Content {
+	return []Content{{MediaType: "application/json", Schema: v}}
+}
+
+// ProblemResponse describes an application/problem+json error response, as
+// written by handlers.WriteError.
+func ProblemResponse(description string) Response {
+	return Response{
+		Description: description,
+		Content:     []Content{{MediaType: "application/problem+json", Schema: apperror.Problem{}}},
+	}
+}
+
+// route identifies an operation.
+type route struct {
+	method string
+	path   string // OpenAPI form, e.g. /courses/{id}
+}
+
+// Document collects operations and renders them as an OpenAPI document. It is
+// safe for concurrent use.
+type Document struct {
+	info Info
+
+	mu         sync.Mutex
+	operations map[route]Operation
+}
+
+// New creates an empty document.
+func New(info Info) *Document {
+	return &Document{info: info, operations: make(map[route]Operation)}
+}
+
+// Add describes the route registered for method and path. The path uses the
+// router's syntax (":id", "*rest"). Adding a route twice replaces the first
+// description.
+func (d *Document) Add(method, path string, op Operation) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.operations[route{method: strings.ToUpper(method), path: Path(path)}] = op
+}
+
+// Has reports whether the route registered for method and path is described.
+func (d *Document) Has(method, path string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.operations[route{method: strings.ToUpper(method), path: Path(path)}]
+	return ok
+}
+
+// Path converts a router path pattern to OpenAPI syntax: "/courses/:id" and
+// "/files/*path" become "/courses/{id}" and "/files/{path}".
+func Path(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// MarshalJSON renders the document.
+func (d *Document) MarshalJSON() ([]byte, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Render in a fixed order so component names are stable when type names collide
+	routes := make([]route, 0, len(d.operations))
+	for r := range d.operations {
+		routes = append(routes, r)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].path != routes[j].path {
+			return routes[i].path < routes[j].path
+		}
+		return routes[i].method < routes[j].method
+	})
+
+	schemas := newSchemaRegistry()
+	paths := make(map[string]map[string]any)
+	for _, r := range routes {
+		op := d.operations[r]
+		if len(op.Responses) == 0 {
+			return nil, fmt.Errorf("%s %s has no responses", r.method, r.path)
+		}
+		item, ok := paths[r.path]
+		if !ok {
+			item = make(map[string]any)
+			paths[r.path] = item
+		}
+		item[strings.ToLower(r.method)] = renderOperation(r, op, schemas)
+	}
+
+	info := map[string]any{"title": d.info.Title, "version": d.info.Version}
+	if d.info.Description != "" {
+		info["description"] = d.info.Description
+	}
+	doc := map[string]any{
+		"openapi": Version,
+		"info":    info,
+		"paths":   paths,
+	}
+	if len(schemas.components) > 0 {
+		doc["components"] = map[string]any{"schemas": schemas.components}
+	}
+	return json.Marshal(doc)
+}
+
+// renderOperation renders one operation object.
+func renderOperation(r route, op Operation, schemas *schemaRegistry) map[string]any {
+	out := map[string]any{
+		"operationId": op.OperationID,
+	}
+	if op.OperationID == "" {
+		out["operationId"] = operationID(r)
+	}
+	if op.Summary != "" {
+		out["summary"] = op.Summary
+	}
+	if op.Description != "" {
+		out["description"] = op.Description
+	}
+	if len(op.Tags) > 0 {
+		out["tags"] = op.Tags
+	}
+	if op.Deprecated {
+		out["deprecated"] = true
+	}
+
+	if params := renderParameters(r.path, op.Parameters, schemas); len(params) > 0 {
+		out["parameters"] = params
+	}
+	if len(op.Request) > 0 {
+		out["requestBody"] = map[string]any{
+			"required": true,
+			"content":  renderContent(op.Request, schemas),
+		}
+	}
+
+	responses := make(map[string]any, len(op.Responses))
+	for status, resp := range op.Responses {
+		description := resp.Description
+		if description == "" {
+			description = http.StatusText(status)
+		}
+		rendered := map[string]any{"description": description}
+		if len(resp.Content) > 0 {
+			rendered["content"] = renderContent(resp.Content, schemas)
+		}
+		if len(resp.Headers) > 0 {
+			headers := make(map[string]any, len(resp.Headers))
+			for name, desc := range resp.Headers {
+				headers[name] = map[string]any{"description": desc, "schema": map[string]any{"type": "string"}}
+			}
+			rendered["headers"] = headers
+		}
+		responses[strconv.Itoa(status)] = rendered
+	}
+	out["responses"] = responses
+	return out
+}
+
+// renderParameters renders the declared parameters plus undeclared path parameters.
+func renderParameters(path string, declared []Parameter, schemas *schemaRegistry) []any {
+	params := append([]Parameter(nil), declared...)
+	for _, segment := range strings.Split(path, "/") {
+		if !strings.HasPrefix(segment, "{") {
+			continue
+		}
+		name := strings.Trim(segment, "{}")
+		found := false
+		for _, p := range declared {
+			if p.In == "path" && p.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			params = append(params, Parameter{Name: name, In: "path"})
+		}
+	}
+
+	out := make([]any, 0, len(params))
+	for _, p := range params {
+		var schema any = map[string]any{"type": "string"}
+		if p.Schema != nil {
+			schema = schemas.schemaOf(p.Schema)
+		}
+		rendered := map[string]any{
+			"name":     p.Name,
+			"in":       p.In,
+			"required": p.Required || p.In == "path", // Path parameters are always required
+			"schema":   schema,
+		}
+		if p.Description != "" {
+			rendered["description"] = p.Description
+		}
+		out = append(out, rendered)
+	}
+	return out
+}
+
+// renderContent renders a content map keyed by media type.
+func renderContent(content []Content, schemas *schemaRegistry) map[string]any {
+	out := make(map[string]any, len(content))
+	for _, c := range content {
+		media := map[string]any{}
+		if c.Schema != nil {
+			media["schema"] = schemas.schemaOf(c.Schema)
+		}
+		out[c.MediaType] = media
+	}
+	return out
+}
+
+// operationID derives an operation ID such as "getCourseById" from a route.
+func operationID(r route) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(r.method))
+	words := strings.FieldsFunc(r.path, func(c rune) bool {
+		return c == '/' || c == '-' || c == '_' || c == '.'
+	})
+	if len(words) == 0 {
+		words = []string{"root"}
+	}
+	for _, w := range words {
+		if strings.HasPrefix(w, "{") {
+			b.WriteString("By")
+			w = strings.Trim(w, "{}")
+		}
+		b.WriteString(upperFirst(w))
+	}
+	return b.String()
+}
+
+// upperFirst upper-cases the first letter of s.
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
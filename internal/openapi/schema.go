@@ -0,0 +1,137 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// schemaRegistry turns Go types into JSON Schemas, collecting named struct
+// types as component schemas.
+type schemaRegistry struct {
+	components map[string]any          // Component schemas by name
+	names      map[reflect.Type]string // Component name of each registered type
+}
+
+// newSchemaRegistry creates an empty registry.
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		components: make(map[string]any),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// Types with their own JSON encoding, described by format rather than fields.
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaOf returns the schema of the type of v.
+func (r *schemaRegistry) schemaOf(v any) any {
+	return r.schema(reflect.TypeOf(v))
+}
+
+// schema returns the schema of t, registering named structs as components.
+func (r *schemaRegistry) schema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "contentEncoding": "base64"} // encoding/json writes []byte as base64
+		}
+		return map[string]any{"type": "array", "items": r.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": r.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t) // Anonymous structs are described inline
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + r.register(t)}
+	}
+	return map[string]any{} // Interfaces and anything else accept any value
+}
+
+// register adds the named struct t as a component and returns its name.
+func (r *schemaRegistry) register(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+	name := upperFirst(t.Name())
+	if _, taken := r.components[name]; taken {
+		// Same type name in another package; qualify it with the package name
+		pkg := t.PkgPath()
+		name = upperFirst(pkg[strings.LastIndex(pkg, "/")+1:]) + name
+	}
+	r.names[t] = name
+	r.components[name] = map[string]any{} // Placeholder so recursive types terminate
+	r.components[name] = r.structSchema(t)
+	return name
+}
+
+// structSchema describes the fields of t as encoding/json writes them.
+// Fields without omitempty are required.
+func (r *schemaRegistry) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	var required []string
+	r.addFields(t, properties, &required)
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addFields adds the JSON fields of t, including promoted fields of embedded structs.
+func (r *schemaRegistry) addFields(t reflect.Type, properties map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.addFields(ft, properties, required)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = r.schema(f.Type)
+		if !strings.Contains(","+opts+",", ",omitempty,") && !strings.Contains(","+opts+",", ",omitzero,") {
+			*required = append(*required, name)
+		}
+	}
+}
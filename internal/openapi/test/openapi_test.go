@@ -0,0 +1,135 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/openapi"
+)
+
+// course is a response type exercising the supported field kinds.
+type course struct {
+	ID        string            `json:"id"`
+	Title     string            `json:"title"`
+	Credits   uint              `json:"credits"`
+	Tags      []string          `json:"tags,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Starts    time.Time         `json:"starts"`
+	Lecturer  *lecturer         `json:"lecturer,omitempty"`
+	internal  string            // Unexported fields are not encoded
+	Skipped   string            `json:"-"`
+	Published bool              `json:"published"`
+}
+
+// lecturer is a named struct referenced from course.
+type lecturer struct {
+	Name string `json:"name"`
+}
+
+// render marshals doc and decodes it into a generic map.
+func render(t *testing.T, doc *openapi.Document) map[string]any {
+	t.Helper()
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("Failed to render document: %v", err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Rendered document is not JSON: %v", err)
+	}
+	return out
+}
+
+// lookup walks nested maps along keys.
+func lookup(t *testing.T, v any, keys ...string) any {
+	t.Helper()
+	for _, k := range keys {
+		m, ok := v.(map[string]any)
+		if !ok {
+			t.Fatalf("Expected an object before %q, got %v", k, v)
+		}
+		v = m[k]
+	}
+	return v
+}
+
+// TestDocument_Schemas tests JSON Schema generation from Go types.
+func TestDocument_Schemas(t *testing.T) {
+	doc := openapi.New(openapi.Info{Title: "Test", Version: "1.0.0"})
+	doc.Add(http.MethodGet, "/courses/:id", openapi.Operation{
+		Summary: "Get a course",
+		Responses: map[int]openapi.Response{
+			http.StatusOK:       {Description: "The course", Content: openapi.JSON(course{})},
+			http.StatusNotFound: openapi.ProblemResponse("No such course"),
+		},
+	})
+	out := render(t, doc)
+
+	if out["openapi"] != openapi.Version {
+		t.Errorf("Expected openapi %s, got %v", openapi.Version, out["openapi"])
+	}
+	op := lookup(t, out, "paths", "/courses/{id}", "get")
+	if id := lookup(t, op, "operationId"); id != "getCoursesById" {
+		t.Errorf("Expected a generated operation ID, got %v", id)
+	}
+	params, _ := lookup(t, op, "parameters").([]any)
+	if len(params) != 1 || lookup(t, params[0], "name") != "id" || lookup(t, params[0], "required") != true {
+		t.Errorf("Expected a required id path parameter, got %v", params)
+	}
+	if ref := lookup(t, op, "responses", "200", "content", "application/json", "schema", "$ref"); ref != "#/components/schemas/Course" {
+		t.Errorf("Expected a component reference, got %v", ref)
+	}
+	if ref := lookup(t, op, "responses", "404", "content", "application/problem+json", "schema", "$ref"); ref != "#/components/schemas/Problem" {
+		t.Errorf("Expected problem details for 404, got %v", ref)
+	}
+
+	schema := lookup(t, out, "components", "schemas", "Course")
+	props := lookup(t, schema, "properties").(map[string]any)
+	for _, name := range []string{"internal", "Skipped"} {
+		if _, ok := props[name]; ok {
+			t.Errorf("Expected field %s to be left out", name)
+		}
+	}
+	if f := lookup(t, props, "starts", "format"); f != "date-time" {
+		t.Errorf("Expected time.Time as date-time, got %v", f)
+	}
+	if typ := lookup(t, props, "tags", "items", "type"); typ != "string" {
+		t.Errorf("Expected tags as an array of strings, got %v", typ)
+	}
+	if ref := lookup(t, props, "lecturer", "$ref"); ref != "#/components/schemas/Lecturer" {
+		t.Errorf("Expected pointer fields to reference their type, got %v", ref)
+	}
+	required, _ := lookup(t, schema, "required").([]any)
+	want := map[any]bool{"id": true, "title": true, "credits": true, "starts": true, "published": true}
+	if len(required) != len(want) {
+		t.Errorf("Expected fields without omitempty to be required, got %v", required)
+	}
+	for _, r := range required {
+		if !want[r] {
+			t.Errorf("Unexpected required field %v", r)
+		}
+	}
+}
+
+// TestDocument_Has tests route lookup in router syntax.
+func TestDocument_Has(t *testing.T) {
+	doc := openapi.New(openapi.Info{Title: "Test", Version: "1.0.0"})
+	doc.Add("get", "/files/*path", openapi.Operation{Responses: map[int]openapi.Response{http.StatusOK: {}}})
+
+	if !doc.Has(http.MethodGet, "/files/*path") {
+		t.Error("Expected the route to be described")
+	}
+	if doc.Has(http.MethodPost, "/files/*path") || doc.Has(http.MethodGet, "/files") {
+		t.Error("Expected other routes not to be described")
+	}
+	if got := openapi.Path("/files/*path"); got != "/files/{path}" {
+		t.Errorf("Expected OpenAPI path syntax, got %q", got)
+	}
+
+	doc.Add(http.MethodDelete, "/files/*path", openapi.Operation{})
+	if _, err := json.Marshal(doc); err == nil {
+		t.Error("Expected an error for an operation without responses")
+	}
+}
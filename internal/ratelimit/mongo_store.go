@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultCollection is the MongoDB collection used when none is configured.
+const DefaultCollection = "rate_limits"
+
+// MongoStore keeps token buckets in a MongoDB collection shared by all
+// application instances, one document per client key.
+//
+// Each Take is a single findAndModify with an update pipeline (MongoDB 4.2+),
+// so refilling and taking a token is atomic on the server and uses the
+// server clock ($$NOW); instance clock skew does not affect the budget.
+// A TTL index removes buckets once they would have refilled completely.
+type MongoStore struct {
+	coll *mongo.Collection
+}
+
+// NewMongoStore prepares the rate limit collection in db, creating the TTL
+// index if needed. An empty collection name selects DefaultCollection.
+func NewMongoStore(ctx context.Context, db *mongo.Database, collection string) (*MongoStore, error) {
+	if collection == "" {
+		collection = DefaultCollection
+	}
+	coll := db.Collection(collection)
+	_, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0).SetName("expires_at_ttl"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rate limit expiry index: %w", err)
+	}
+	return &MongoStore{coll: coll}, nil
+}
+
+// mongoBucket is the part of a bucket document read back after an update.
+type mongoBucket struct {
+	Tokens  float64 `bson:"tokens"`
+	Allowed bool    `bson:"allowed"`
+}
+
+// Take implements Store.
+func (s *MongoStore) Take(ctx context.Context, key string, p Policy) (Result, error) {
+	burst := float64(p.Burst)
+	elapsedSeconds := bson.D{{Key: "$divide", Value: bson.A{
+		bson.D{{Key: "$subtract", Value: bson.A{"$$NOW", bson.D{{Key: "$ifNull", Value: bson.A{"$updated_at", "$$NOW"}}}}}},
+		1000,
+	}}}
+	refilled := bson.D{{Key: "$min", Value: bson.A{
+		burst,
+		bson.D{{Key: "$add", Value: bson.A{
+			bson.D{{Key: "$ifNull", Value: bson.A{"$tokens", burst}}},
+			bson.D{{Key: "$multiply", Value: bson.A{elapsedSeconds, p.Rate}}},
+		}}},
+	}}}
+
+	pipeline := mongo.Pipeline{
+		// Refill for the time since the last request; a new document starts full
+		{{Key: "$set", Value: bson.D{{Key: "tokens", Value: refilled}, {Key: "updated_at", Value: "$$NOW"}}}},
+		{{Key: "$set", Value: bson.D{{Key: "allowed", Value: bson.D{{Key: "$gte", Value: bson.A{"$tokens", 1}}}}}}},
+		{{Key: "$set", Value: bson.D{
+			{Key: "tokens", Value: bson.D{{Key: "$cond", Value: bson.A{"$allowed", bson.D{{Key: "$subtract", Value: bson.A{"$tokens", 1}}}, "$tokens"}}}},
+			{Key: "expires_at", Value: bson.D{{Key: "$add", Value: bson.A{"$$NOW", p.fillTime().Milliseconds()}}}},
+		}}},
+	}
+
+	var doc mongoBucket
+	err := s.coll.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: key}}, pipeline,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&doc)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to update rate limit bucket: %w", err)
+	}
+	return newResult(p, doc.Tokens, doc.Allowed), nil
+}
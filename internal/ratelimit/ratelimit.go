@@ -0,0 +1,221 @@
+// Package ratelimit implements token bucket rate limiting with pluggable
+// bucket storage.
+//
+// A Policy describes a bucket: its capacity (Burst) and how fast it refills
+// (Rate). Every request takes one token from the bucket identified by a client
+// key; when the bucket is empty the request is rejected until enough time has
+// passed to regain a token. Buckets live in a Store, either in process memory
+// (MemoryStore) or in a shared MongoDB collection (MongoStore) so that several
+// application instances enforce one budget per client.
+//
+// Usage Examples:
+//
+//	store := ratelimit.NewMemoryStore()
+//	limiter := ratelimit.New(store, false)
+//	policy := ratelimit.NewPolicy("default", cfg.Server.RateLimit.RateLimitPolicy)
+//
+//	result, err := limiter.Allow(ctx, policy, "ip:"+clientIP)
+//	if !result.Allowed {
+//	    // Reply 429 with Retry-After: result.RetryAfter
+//	}
+//
+// Metrics:
+// Limiter decisions are counted per policy name and reported by Stats for the
+// /metrics endpoint.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+)
+
+// Policy is a token bucket configuration.
+type Policy struct {
+	Name   string  // Policy name reported in metrics, e.g. "default" or a route prefix
+	Rate   float64 // Tokens regained per second
+	Burst  int     // Bucket capacity; 0 disables limiting
+	Window int     // Configured window in seconds, reported in the RateLimit-Policy header
+	Limit  int     // Configured requests per window
+}
+
+// NewPolicy converts a configured policy. Requests of 0 or less yields a
+// policy that never limits; a Window of 0 or less is treated as one second.
+func NewPolicy(name string, cfg config.RateLimitPolicy) Policy {
+	if cfg.Requests <= 0 {
+		return Policy{Name: name}
+	}
+	window := cfg.Window
+	if window <= 0 {
+		window = 1
+	}
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = cfg.Requests
+	}
+	return Policy{
+		Name:   name,
+		Rate:   float64(cfg.Requests) / float64(window),
+		Burst:  burst,
+		Window: window,
+		Limit:  cfg.Requests,
+	}
+}
+
+// Unlimited reports whether the policy never rejects requests.
+func (p Policy) Unlimited() bool {
+	return p.Burst <= 0 || p.Rate <= 0
+}
+
+// fillTime returns how long an empty bucket takes to become full. Buckets
+// untouched for this long are indistinguishable from new ones and may be discarded.
+func (p Policy) fillTime() time.Duration {
+	return time.Duration(float64(p.Burst) / p.Rate * float64(time.Second))
+}
+
+// Result is the outcome of taking a token.
+type Result struct {
+	Allowed    bool          // The request may proceed
+	Limit      int           // Bucket capacity
+	Remaining  int           // Whole tokens left after this request
+	Reset      time.Duration // Time until the bucket is full again
+	RetryAfter time.Duration // Time until a token is available; 0 when Allowed
+}
+
+// newResult derives a Result from the token count left in the bucket after a
+// take attempt. Stores share it so all backends report identical headers.
+func newResult(p Policy, tokens float64, allowed bool) Result {
+	tokens = math.Max(0, math.Min(tokens, float64(p.Burst)))
+	result := Result{
+		Allowed:   allowed,
+		Limit:     p.Burst,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((float64(p.Burst) - tokens) / p.Rate * float64(time.Second)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) / p.Rate * float64(time.Second))
+	}
+	return result
+}
+
+// Store keeps token buckets.
+//
+// Implementations must make Take atomic per key: concurrent requests for the
+// same key must never take the same token twice.
+type Store interface {
+	// Take refills the bucket for key according to p, then takes one token if available.
+	Take(ctx context.Context, key string, p Policy) (Result, error)
+}
+
+// OpenStore returns the store selected by cfg.Store. The "mongodb" store
+// requires db.
+func OpenStore(ctx context.Context, cfg config.ServerRateLimit, db *mongo.Database) (Store, error) {
+	switch strings.ToLower(cfg.Store) {
+	case "memory", "":
+		return NewMemoryStore(), nil
+	case "mongodb", "mongo":
+		if db == nil {
+			return nil, errors.New("rate limit store mongodb requires a database connection")
+		}
+		return NewMongoStore(ctx, db, cfg.Collection)
+	default:
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.Store)
+	}
+}
+
+// Limiter applies policies using a Store and counts its decisions.
+type Limiter struct {
+	store      Store
+	failClosed bool
+}
+
+// New creates a Limiter. When failClosed is set, requests are rejected while
+// the store is failing; otherwise they are allowed.
+func New(store Store, failClosed bool) *Limiter {
+	return &Limiter{store: store, failClosed: failClosed}
+}
+
+// Allow takes a token for key under policy p.
+//
+// Store errors are returned together with a Result whose Allowed field
+// reflects the fail-open or fail-closed setting, so callers can log the error
+// and still act on the Result.
+func (l *Limiter) Allow(ctx context.Context, p Policy, key string) (Result, error) {
+	if p.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+	counters := countersFor(p.Name)
+
+	result, err := l.store.Take(ctx, p.Name+"|"+key, p)
+	if err != nil {
+		counters.add(func(s *PolicyStats) { s.Errors++ })
+		return Result{Allowed: !l.failClosed, Limit: p.Burst}, err
+	}
+	if result.Allowed {
+		counters.add(func(s *PolicyStats) { s.Allowed++ })
+	} else {
+		counters.add(func(s *PolicyStats) { s.Limited++ })
+	}
+	return result, nil
+}
+
+// PolicyStats are the decision counters of one policy.
+type PolicyStats struct {
+	Policy  string // Policy name
+	Allowed uint64 // Requests that received a token
+	Limited uint64 // Requests rejected with 429
+	Errors  uint64 // Requests where the store failed
+}
+
+// policyCounters guards the counters of one policy.
+type policyCounters struct {
+	mu    sync.Mutex
+	stats PolicyStats
+}
+
+func (c *policyCounters) add(update func(*PolicyStats)) {
+	c.mu.Lock()
+	update(&c.stats)
+	c.mu.Unlock()
+}
+
+var (
+	statsMu sync.Mutex
+	stats   = make(map[string]*policyCounters)
+)
+
+// countersFor returns the counters of the named policy, creating them on first use.
+func countersFor(name string) *policyCounters {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	c, ok := stats[name]
+	if !ok {
+		c = &policyCounters{stats: PolicyStats{Policy: name}}
+		stats[name] = c
+	}
+	return c
+}
+
+// Stats returns the decision counters of every policy that has seen traffic,
+// sorted by policy name.
+func Stats() []PolicyStats {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	result := make([]PolicyStats, 0, len(stats))
+	for _, c := range stats {
+		c.mu.Lock()
+		result = append(result, c.stats)
+		c.mu.Unlock()
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Policy < result[j].Policy })
+	return result
+}
@@ -0,0 +1,86 @@
+package ratelimit_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/ratelimit"
+)
+
+// TestNewPolicy tests conversion of configured policies.
+func TestNewPolicy(t *testing.T) {
+	testCases := []struct {
+		name      string
+		cfg       config.RateLimitPolicy
+		wantRate  float64
+		wantBurst int
+		unlimited bool
+	}{
+		{"Burst defaults to requests", config.RateLimitPolicy{Requests: 120, Window: 60}, 2, 120, false},
+		{"Explicit burst", config.RateLimitPolicy{Requests: 10, Window: 1, Burst: 25}, 10, 25, false},
+		{"Zero window means per second", config.RateLimitPolicy{Requests: 5}, 5, 5, false},
+		{"Zero requests disables limiting", config.RateLimitPolicy{Window: 60}, 0, 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := ratelimit.NewPolicy("test", tc.cfg)
+			if p.Rate != tc.wantRate || p.Burst != tc.wantBurst || p.Unlimited() != tc.unlimited {
+				t.Errorf("Expected rate %v burst %d unlimited %v, got %+v", tc.wantRate, tc.wantBurst, tc.unlimited, p)
+			}
+		})
+	}
+}
+
+// TestMemoryStore_TokenBucket tests burst consumption, refill and per-key isolation.
+func TestMemoryStore_TokenBucket(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	// 20 tokens per second: one token every 50ms
+	p := ratelimit.NewPolicy("test", config.RateLimitPolicy{Requests: 20, Window: 1, Burst: 2})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if r, _ := store.Take(ctx, "a", p); !r.Allowed || r.Remaining != 1-i {
+			t.Fatalf("Take %d: expected allowed with %d remaining, got %+v", i+1, 1-i, r)
+		}
+	}
+	r, _ := store.Take(ctx, "a", p)
+	if r.Allowed || r.RetryAfter <= 0 || r.RetryAfter > 50*time.Millisecond {
+		t.Fatalf("Expected rejection with RetryAfter up to 50ms, got %+v", r)
+	}
+	if r, _ := store.Take(ctx, "b", p); !r.Allowed {
+		t.Error("Expected an independent bucket for another key")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if r, _ := store.Take(ctx, "a", p); !r.Allowed {
+		t.Errorf("Expected a refilled token after waiting, got %+v", r)
+	}
+}
+
+// TestMemoryStore_Concurrent tests that concurrent takes never exceed the burst.
+func TestMemoryStore_Concurrent(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	p := ratelimit.NewPolicy("test", config.RateLimitPolicy{Requests: 50, Window: 3600})
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if r, _ := store.Take(context.Background(), "shared", p); r.Allowed {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := allowed.Load(); got != 50 {
+		t.Errorf("Expected exactly 50 allowed requests, got %d", got)
+	}
+}
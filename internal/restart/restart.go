@@ -0,0 +1,188 @@
+// Package restart implements zero-downtime restarts by handing listening
+// sockets over to a new process.
+//
+// The running process starts its executable again, passing the bound
+// listening sockets and the write end of a readiness pipe as extra file
+// descriptors. The new process takes the sockets over with Inherited, starts
+// serving and calls Ready. Only then does the old process stop accepting
+// connections and drain; if the new process exits or does not become ready in
+// time, the old one keeps serving. Connections arriving during the handoff
+// queue on the shared sockets and are accepted by whichever process is
+// serving, so none are refused.
+//
+// Protocol:
+//   - Listening sockets are file descriptors 3 to 3+n-1, as with systemd socket activation
+//   - GOEDU_LISTEN_FDS: Number of listening sockets
+//   - GOEDU_LISTEN_FDNAMES: Colon-separated socket names, like systemd's LISTEN_FDNAMES
+//   - GOEDU_READY_FD: Descriptor of the readiness pipe; the new process writes one byte to it
+//
+// Usage Examples:
+//
+//	// Old process, on SIGUSR2
+//	pid, err := restart.Spawn(ctx, listeners)
+//	if err != nil {
+//	    // Keep serving; the new process was not started or never became ready
+//	}
+//
+//	// New process, once it serves
+//	for _, l := range restart.Inherited() { ... }
+//	restart.Ready()
+package restart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Environment variables describing handed over descriptors.
+const (
+	envListenFDs     = "GOEDU_LISTEN_FDS"
+	envListenFDNames = "GOEDU_LISTEN_FDNAMES"
+	envReadyFD       = "GOEDU_READY_FD"
+)
+
+// firstFD is the descriptor of the first handed over socket; 0-2 are stdio.
+const firstFD = 3
+
+// Listener is a listening socket handed over between processes.
+type Listener struct {
+	Name string   // Role of the socket, e.g. "http" or "admin"
+	File *os.File // Listening socket
+}
+
+// Spawn starts a new process of the current executable with the same
+// arguments, hands it listeners and waits until it calls Ready.
+//
+// The executable is resolved again, so a binary replaced on disk is picked
+// up. The new process shares stdin, stdout and stderr. Spawn returns an error
+// if the process cannot be started, exits before it is ready, or is not ready
+// before ctx is done, in which case it is killed. The caller keeps ownership
+// of the listener files and should close them afterwards.
+//
+// Returns:
+//   - int: PID of the new process
+//   - error: Why the new process is not serving
+func Spawn(ctx context.Context, listeners []Listener) (int, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("failed to locate executable: %w", err)
+	}
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return 0, fmt.Errorf("failed to create readiness pipe: %w", err)
+	}
+	defer readyR.Close()
+
+	files := make([]*os.File, 0, len(listeners)+1)
+	names := make([]string, 0, len(listeners))
+	for _, l := range listeners {
+		files = append(files, l.File)
+		names = append(names, l.Name)
+	}
+	files = append(files, readyW)
+
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = files
+	cmd.Env = append(environWithout(envListenFDs, envListenFDNames, envReadyFD, "LISTEN_PID", "LISTEN_FDS", "LISTEN_FDNAMES"),
+		envListenFDs+"="+strconv.Itoa(len(listeners)),
+		envListenFDNames+"="+strings.Join(names, ":"),
+		envReadyFD+"="+strconv.Itoa(firstFD+len(listeners)),
+	)
+	err = cmd.Start()
+	// Only the new process holds the write end now, so EOF means it exited
+	readyW.Close()
+	if err != nil {
+		return 0, fmt.Errorf("failed to start new process: %w", err)
+	}
+
+	ready := make(chan error, 1)
+	go func() {
+		_, err := readyR.Read(make([]byte, 1))
+		if errors.Is(err, io.EOF) {
+			err = errors.New("new process exited before it was ready")
+		}
+		ready <- err
+	}()
+
+	select {
+	case err = <-ready:
+	case <-ctx.Done():
+		err = fmt.Errorf("new process not ready: %w", ctx.Err())
+	}
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return 0, err
+	}
+	pid := cmd.Process.Pid
+	// The new process outlives this one; nothing is left to wait for
+	cmd.Process.Release()
+	return pid, nil
+}
+
+// Inherited returns the listening sockets handed over by the previous
+// process, or nil if this process was not started by Spawn. The variables
+// describing them are removed from the environment, so calling it again
+// returns nil.
+func Inherited() []Listener {
+	count, err := strconv.Atoi(os.Getenv(envListenFDs))
+	if err != nil || count <= 0 {
+		return nil
+	}
+	names := strings.Split(os.Getenv(envListenFDNames), ":")
+	os.Unsetenv(envListenFDs)
+	os.Unsetenv(envListenFDNames)
+
+	listeners := make([]Listener, 0, count)
+	for i := 0; i < count; i++ {
+		name := ""
+		if i < len(names) {
+			name = names[i]
+		}
+		listeners = append(listeners, Listener{Name: name, File: os.NewFile(uintptr(firstFD+i), name)})
+	}
+	return listeners
+}
+
+// Ready tells the previous process that this one is serving, so it can
+// drain and exit. It does nothing if this process was not started by Spawn.
+func Ready() error {
+	fd, err := strconv.Atoi(os.Getenv(envReadyFD))
+	if err != nil {
+		return nil
+	}
+	os.Unsetenv(envReadyFD)
+	f := os.NewFile(uintptr(fd), "restart-ready")
+	defer f.Close()
+	if _, err := f.Write([]byte{1}); err != nil {
+		return fmt.Errorf("failed to signal readiness: %w", err)
+	}
+	return nil
+}
+
+// environWithout returns the environment without the given variables.
+func environWithout(keys ...string) []string {
+	env := os.Environ()
+	filtered := env[:0:0]
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		drop := false
+		for _, key := range keys {
+			if name == key {
+				drop = true
+				break
+			}
+		}
+		if !drop {
+			filtered = append(filtered, kv)
+		}
+	}
+	return filtered
+}
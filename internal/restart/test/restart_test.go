@@ -0,0 +1,174 @@
+package restart_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/restart"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// childModeEnv selects the behaviour of the test binary when Spawn re-executes it.
+const childModeEnv = "GOEDU_TEST_RESTART_CHILD"
+
+// TestMain runs the test binary as the new process when started by Spawn.
+func TestMain(m *testing.M) {
+	switch os.Getenv(childModeEnv) {
+	case "":
+		os.Exit(m.Run())
+	case "serve":
+		os.Exit(runChildServer())
+	case "exit":
+		os.Exit(3) // Fails before signalling readiness
+	case "hang":
+		time.Sleep(time.Minute) // Never signals readiness
+		os.Exit(0)
+	}
+}
+
+// runChildServer serves the inherited listeners until /quit is requested.
+func runChildServer() int {
+	// An unbindable host proves the inherited sockets are used instead
+	srv := server.NewServer(config.Server{Host: "256.0.0.1", Port: 8080}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	quit := make(chan struct{})
+	srv.Router().GET("/pid", func(c *gin.Context) {
+		c.String(http.StatusOK, strconv.Itoa(os.Getpid()))
+	})
+	srv.Router().GET("/quit", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+		close(quit)
+	})
+	if err := srv.Start(); err != nil {
+		return 1
+	}
+	if err := restart.Ready(); err != nil {
+		return 1
+	}
+	select {
+	case <-quit:
+	case <-time.After(30 * time.Second):
+	}
+	srv.Shutdown(context.Background())
+	return 0
+}
+
+// get sends GET url with client and returns the response body.
+func get(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+// TestSpawn_HandsOverListeners tests that a new process serves TCP and Unix listeners after the old one stops.
+func TestSpawn_HandsOverListeners(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "goedu.sock")
+	cfg := config.Server{Host: "127.0.0.1", Port: 0, Listeners: []config.ServerListener{{Address: "unix://" + socket}}}
+	srv := server.NewServer(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	addr := srv.Addr()
+
+	listeners, err := srv.ListenerFiles()
+	if err != nil {
+		t.Fatalf("ListenerFiles failed: %v", err)
+	}
+	if len(listeners) != 2 || listeners[0].Name != "http" || listeners[1].Name != "http" {
+		t.Fatalf("Expected two public listeners, got %+v", listeners)
+	}
+
+	t.Setenv(childModeEnv, "serve")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	pid, err := restart.Spawn(ctx, listeners)
+	for _, l := range listeners {
+		l.File.Close()
+	}
+	if err != nil {
+		srv.Shutdown(context.Background())
+		t.Fatalf("Spawn failed: %v", err)
+	}
+	child, _ := os.FindProcess(pid)
+	defer child.Kill()
+
+	// Drain the old server; the new process keeps the sockets open
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if _, err := os.Stat(socket); err != nil {
+		t.Fatalf("Expected the Unix socket to survive the old server's shutdown: %v", err)
+	}
+
+	want := strconv.Itoa(pid)
+	if got := get(t, http.DefaultClient, "http://"+addr+"/pid"); got != want {
+		t.Errorf("Expected the TCP listener served by PID %s, got %q", want, got)
+	}
+	unixClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		},
+	}}
+	if got := get(t, unixClient, "http://goedu/pid"); got != want {
+		t.Errorf("Expected the Unix socket served by PID %s, got %q", want, got)
+	}
+
+	get(t, http.DefaultClient, "http://"+addr+"/quit")
+	if state, err := child.Wait(); err != nil || !state.Success() {
+		t.Errorf("Expected the new process to exit cleanly, got %v (%v)", state, err)
+	}
+}
+
+// TestSpawn_NotReady tests that Spawn fails when the new process exits or hangs before signalling readiness.
+func TestSpawn_NotReady(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer l.Close()
+	f, _ := l.(*net.TCPListener).File()
+	defer f.Close()
+	listeners := []restart.Listener{{Name: "http", File: f}}
+
+	t.Setenv(childModeEnv, "exit")
+	if _, err := restart.Spawn(context.Background(), listeners); err == nil {
+		t.Error("Expected an error when the new process exits before it is ready")
+	}
+
+	t.Setenv(childModeEnv, "hang")
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := restart.Spawn(ctx, listeners); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a readiness timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected the hanging process to be killed at the timeout, took %v", elapsed)
+	}
+}
+
+// TestReady_WithoutParent tests that Ready and Inherited are no-ops in a normally started process.
+func TestReady_WithoutParent(t *testing.T) {
+	if err := restart.Ready(); err != nil {
+		t.Errorf("Expected Ready to do nothing, got %v", err)
+	}
+	if listeners := restart.Inherited(); listeners != nil {
+		t.Errorf("Expected no inherited listeners, got %+v", listeners)
+	}
+}
@@ -0,0 +1,109 @@
+// Package routes records the routes a server registers, so route listings
+// (the root endpoint, the startup log and the routes command) are generated
+// from the routing table instead of being maintained by hand.
+//
+// Usage Examples:
+//
+//	registry := routes.NewRegistry()
+//	registry.Add(routes.Route{Listener: routes.ListenerPublic, Method: "GET", Path: "/health", Description: "Liveness check", Auth: routes.AuthNone})
+//	for _, r := range registry.Routes(routes.ListenerPublic) {
+//	    fmt.Println(r) // GET /health
+//	}
+package routes
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+)
+
+// Listeners a route can be served on.
+const (
+	ListenerPublic = "public" // Public API listeners
+	ListenerAdmin  = "admin"  // Admin listener
+)
+
+// Authentication requirements of a route.
+const (
+	AuthNone       = "none"        // Open to any client
+	AuthAdminToken = "admin_token" // Requires the admin bearer token
+)
+
+// Route describes one registered route.
+type Route struct {
+	Listener    string `json:"listener"`          // ListenerPublic or ListenerAdmin
+	Method      string `json:"method"`            // HTTP method
+	Path        string `json:"path"`              // Route pattern, e.g. /courses/:id
+	Description string `json:"description"`       // One-line summary
+	Auth        string `json:"auth"`              // AuthNone or AuthAdminToken
+	Deprecated  bool   `json:"deprecated"`        // Clients should migrate away
+	Sunset      string `json:"sunset,omitempty"`  // Planned removal date (YYYY-MM-DD) of a deprecated route
+	Version     string `json:"version,omitempty"` // API version, e.g. "v2"; empty for unversioned routes
+}
+
+// String formats the route as "METHOD /path".
+func (r Route) String() string {
+	return r.Method + " " + r.Path
+}
+
+// Registry collects routes as they are registered. It is safe for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	routes []Route
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Add records a registered route.
+func (r *Registry) Add(route Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, route)
+}
+
+// Routes returns the routes served on listener, or all routes when listener
+// is "", ordered by listener, path and method.
+func (r *Registry) Routes(listener string) []Route {
+	r.mu.RLock()
+	out := make([]Route, 0, len(r.routes))
+	for _, route := range r.routes {
+		if listener == "" || route.Listener == listener {
+			out = append(out, route)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Listener != b.Listener {
+			return a.Listener > b.Listener // Public before admin
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+	return out
+}
+
+// Write prints routes as an aligned table, as shown by the routes command.
+func Write(w io.Writer, routes []Route) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "LISTENER\tMETHOD\tPATH\tAUTH\tDESCRIPTION")
+	for _, r := range routes {
+		description := r.Description
+		switch {
+		case r.Deprecated && r.Sunset != "":
+			description = "[deprecated, sunset " + r.Sunset + "] " + description
+		case r.Deprecated:
+			description = "[deprecated] " + description
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Listener, r.Method, r.Path, r.Auth, description)
+	}
+	return tw.Flush()
+}
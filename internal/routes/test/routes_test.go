@@ -0,0 +1,70 @@
+package routes_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/routes"
+)
+
+// TestRegistry_Routes tests filtering by listener and the listing order.
+func TestRegistry_Routes(t *testing.T) {
+	registry := routes.NewRegistry()
+	registry.Add(routes.Route{Listener: routes.ListenerAdmin, Method: "GET", Path: "/config"})
+	registry.Add(routes.Route{Listener: routes.ListenerPublic, Method: "POST", Path: "/courses"})
+	registry.Add(routes.Route{Listener: routes.ListenerPublic, Method: "GET", Path: "/courses"})
+	registry.Add(routes.Route{Listener: routes.ListenerPublic, Method: "GET", Path: "/"})
+
+	tests := []struct {
+		listener string
+		want     []string
+	}{
+		{"", []string{"GET /", "GET /courses", "POST /courses", "GET /config"}},
+		{routes.ListenerPublic, []string{"GET /", "GET /courses", "POST /courses"}},
+		{routes.ListenerAdmin, []string{"GET /config"}},
+	}
+	for _, tt := range tests {
+		got := registry.Routes(tt.listener)
+		if len(got) != len(tt.want) {
+			t.Errorf("Routes(%q): expected %v, got %v", tt.listener, tt.want, got)
+			continue
+		}
+		for i, r := range got {
+			if r.String() != tt.want[i] {
+				t.Errorf("Routes(%q)[%d]: expected %s, got %s", tt.listener, i, tt.want[i], r)
+			}
+		}
+	}
+}
+
+// TestWrite tests the table printed by the routes command.
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	err := routes.Write(&buf, []routes.Route{
+		{Listener: routes.ListenerPublic, Method: "GET", Path: "/", Description: "API information", Auth: routes.AuthNone},
+		{Listener: routes.ListenerPublic, Method: "GET", Path: "/old", Description: "Old listing", Auth: routes.AuthNone, Deprecated: true},
+		{Listener: routes.ListenerAdmin, Method: "GET", Path: "/config", Description: "Configuration", Auth: routes.AuthAdminToken},
+	})
+	if err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected header and 3 rows, got:\n%s", buf.String())
+	}
+	if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "LISTENER METHOD PATH AUTH DESCRIPTION" {
+		t.Errorf("Unexpected header %q", lines[0])
+	}
+	if !strings.Contains(lines[2], "[deprecated] Old listing") {
+		t.Errorf("Expected deprecated marker, got %q", lines[2])
+	}
+	if fields := strings.Fields(lines[3]); fields[0] != "admin" || fields[3] != "admin_token" {
+		t.Errorf("Unexpected admin row %q", lines[3])
+	}
+	// Columns are aligned
+	if strings.Index(lines[1], "/") != strings.Index(lines[3], "/") {
+		t.Errorf("Columns not aligned:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,233 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/pprof"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apperror"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/audit"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/logger"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/routes"
+)
+
+// adminProbePaths are the admin endpoints served without the admin token, so
+// local health and readiness probes need no credentials.
+var adminProbePaths = map[string]bool{"/health": true, "/ready": true}
+
+// adminLevelNames lists the log levels accepted by PUT /log-level. Unlike
+// logger.ParseLevel, unknown names are rejected instead of falling back to info.
+var adminLevelNames = map[string]bool{
+	"trace": true, "debug": true, "info": true, "warn": true, "error": true, "fatal": true,
+}
+
+// SetConfigDump makes cfg available at GET /config on the admin listener, with
+// passwords, tokens and similar values redacted. Call it before Start.
+//
+// Example:
+//
+//	httpServer := server.NewServer(cfg.Server, logger.GetLogger())
+//	httpServer.SetConfigDump(cfg)
+func (s *Server) SetConfigDump(cfg *config.Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configDump = cfg
+}
+
+// newAdminRouter builds the router for the admin listener.
+//
+// The admin router has its own middleware stack so operational traffic is
+// logged separately (listener=admin) and protected by the optional admin
+// token without affecting the public API.
+//
+// Endpoints:
+//   - GET /health: Liveness check, never requires the token (for local probes)
+//   - GET /ready: Readiness check, never requires the token; 503 while draining
+//   - GET /metrics: Application metrics
+//   - GET /debug/pprof/...: Runtime profiling (net/http/pprof)
+//   - GET /log-level: Current minimum log level
+//   - PUT /log-level: Change the log level at runtime; recorded in the audit trail
+//   - GET /config: Effective configuration with secrets redacted
+func (s *Server) newAdminRouter() *gin.Engine {
+	adminLogger := s.logger.With(slog.String("listener", "admin"))
+
+	router := gin.New()
+	trustProxies(router, s.config.TrustedProxies, adminLogger)
+	router.Use(requestIDMiddleware())
+	router.Use(ginLoggerMiddleware(adminLogger))
+	router.Use(recoveryMiddleware(adminLogger))
+	router.Use(adminAuthMiddleware(s.config.AdminToken))
+
+	// handle registers an admin route and records it in the route registry
+	handle := func(method, path, description string, handler gin.HandlerFunc) {
+		router.Handle(method, path, handler)
+		auth := routes.AuthAdminToken
+		if s.config.AdminToken == "" || adminProbePaths[path] {
+			auth = routes.AuthNone
+		}
+		s.routes.Add(routes.Route{Listener: routes.ListenerAdmin, Method: method, Path: path, Description: description, Auth: auth})
+	}
+
+	h := handlers.NewHandler(adminLogger)
+	h.SetLoadShedder(s.shedder) // Shedding gauges of the public listener
+	handle(http.MethodGet, "/health", "Liveness check", h.HandleHealth)
+	handle(http.MethodGet, "/ready", "Readiness check", s.handleReady)
+	handle(http.MethodGet, "/metrics", "Runtime and application metrics", h.HandleMetrics)
+
+	// Profiling endpoints; named profiles (heap, goroutine, allocs, ...) share one route
+	handle(http.MethodGet, "/debug/pprof/", "Profile index", gin.WrapF(pprof.Index))
+	handle(http.MethodGet, "/debug/pprof/cmdline", "Process command line", gin.WrapF(pprof.Cmdline))
+	handle(http.MethodGet, "/debug/pprof/profile", "CPU profile", gin.WrapF(pprof.Profile))
+	handle(http.MethodGet, "/debug/pprof/symbol", "Symbol lookup", gin.WrapF(pprof.Symbol))
+	handle(http.MethodPost, "/debug/pprof/symbol", "Symbol lookup", gin.WrapF(pprof.Symbol))
+	handle(http.MethodGet, "/debug/pprof/trace", "Execution trace", gin.WrapF(pprof.Trace))
+	handle(http.MethodGet, "/debug/pprof/:profile", "Named profile (heap, goroutine, allocs, ...)", func(c *gin.Context) {
+		pprof.Handler(c.Param("profile")).ServeHTTP(c.Writer, c.Request)
+	})
+
+	handle(http.MethodGet, "/log-level", "Current minimum log level", s.handleGetLogLevel)
+	handle(http.MethodPut, "/log-level", "Change the log level; recorded in the audit trail", s.handleSetLogLevel)
+	handle(http.MethodGet, "/config", "Effective configuration with secrets redacted", s.handleConfigDump)
+
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(h.HandleNoRoute)
+	router.NoMethod(h.HandleNoMethod)
+
+	return router
+}
+
+// adminAuthMiddleware requires "Authorization: Bearer <token>" on every admin
+// request except the /health and /ready probes. An empty token disables authentication.
+func adminAuthMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" || adminProbePaths[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+		given := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		// Constant-time comparison prevents recovering the token through response timing
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			handlers.WriteError(c, apperror.Unauthorized("a valid admin token is required"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// handleGetLogLevel returns the current minimum log level.
+func (s *Server) handleGetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"level": logger.LevelName(logger.Level())})
+}
+
+// handleSetLogLevel changes the minimum log level at runtime.
+//
+// Request body: {"level": "debug"}
+//
+// The change is recorded in the audit trail before it is applied; when the
+// audit trail is unavailable the request is rejected with 503 so log level
+// changes are never made without a record.
+func (s *Server) handleSetLogLevel(c *gin.Context) {
+	var req struct {
+		Level string `json:"level"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || !adminLevelNames[strings.ToLower(req.Level)] {
+		handlers.WriteError(c, apperror.BadRequest("invalid log level").
+			WithField("level", "one_of", "level must be one of trace, debug, info, warn, error, fatal"))
+		return
+	}
+
+	previous := logger.LevelName(logger.Level())
+	level := logger.ParseLevel(req.Level)
+	if err := audit.Record(c.Request.Context(), audit.User("admin", c.ClientIP()), "logger.level.change",
+		audit.Resource{Type: "logger", ID: logger.LevelName(level)}, audit.OutcomeSuccess); err != nil {
+		s.logger.Error("❌ Log level change rejected: audit trail unavailable",
+			slog.Any("error", err),
+		)
+		handlers.WriteError(c, apperror.ServiceUnavailable("audit trail unavailable").Wrap(err))
+		return
+	}
+
+	logger.SetLevel(level)
+	s.logger.Warn("🔄 Log level changed at runtime",
+		slog.String("previous", previous),             // Level before the change
+		slog.String("level", logger.LevelName(level)), // New minimum level
+		slog.String("client_ip", c.ClientIP()),        // Who requested the change
+	)
+	c.JSON(http.StatusOK, gin.H{"level": logger.LevelName(level), "previous": previous})
+}
+
+// handleConfigDump returns the effective configuration with secrets redacted.
+func (s *Server) handleConfigDump(c *gin.Context) {
+	s.mu.Lock()
+	cfg := s.configDump
+	s.mu.Unlock()
+	if cfg == nil {
+		handlers.WriteError(c, apperror.NotFound("configuration dump not enabled"))
+		return
+	}
+
+	dump, err := redactedConfig(cfg)
+	if err != nil {
+		handlers.WriteError(c, apperror.Internal(err))
+		return
+	}
+	c.JSON(http.StatusOK, dump)
+}
+
+// redactedConfig converts cfg to its JSON representation and replaces the
+// values of sensitive keys (e.g. "password", "admin_token", "hash_salt") with
+// logger.RedactedMask.
+func redactedConfig(cfg *config.Config) (map[string]interface{}, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var dump map[string]interface{}
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return nil, err
+	}
+	redactMap(dump, "")
+	return dump, nil
+}
+
+// redactMap masks sensitive values in m in place; prefix is the dotted key path of m.
+func redactMap(m map[string]interface{}, prefix string) {
+	for key, value := range m {
+		full := strings.ToLower(prefix + key)
+		if sensitiveKey(strings.ToLower(key)) || sensitiveKey(full) {
+			if s, ok := value.(string); ok && s == "" {
+				continue // Show that an optional secret is unset
+			}
+			m[key] = logger.RedactedMask
+			continue
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			redactMap(v, full+".")
+		case []interface{}:
+			for _, item := range v {
+				if nested, ok := item.(map[string]interface{}); ok {
+					redactMap(nested, full+".")
+				}
+			}
+		}
+	}
+}
+
+// sensitiveKey reports whether key matches one of logger.DefaultRedactKeys.
+func sensitiveKey(key string) bool {
+	for _, pattern := range logger.DefaultRedactKeys {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
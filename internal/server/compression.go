@@ -0,0 +1,298 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+)
+
+// compressor creates and recycles encoders for one content coding.
+type compressor struct {
+	name string    // Content-Encoding token, e.g. "gzip"
+	pool sync.Pool // Reusable encoders; creating them is far costlier than resetting
+}
+
+// encoder is the common interface of the pooled gzip and zstd writers.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// get returns an encoder writing to w.
+func (c *compressor) get(w io.Writer) encoder {
+	enc := c.pool.Get().(encoder)
+	enc.Reset(w)
+	return enc
+}
+
+// compressionPolicy is a config.ServerCompression prepared for per-request use.
+type compressionPolicy struct {
+	compressors  []*compressor // Supported encodings in order of preference
+	minSize      int
+	contentTypes []string // Lower-cased media types; entries ending in "/" match a family
+}
+
+// compressionMiddleware creates a Gin middleware compressing responses with
+// the encoding negotiated from Accept-Encoding.
+//
+// The response body is buffered until MinSize bytes are written, so small
+// responses are sent uncompressed with their original Content-Length. Once a
+// response is compressed, Content-Length is dropped and a strong ETag is
+// weakened, since the bytes on the wire no longer match the representation
+// the tag was computed for. "Vary: Accept-Encoding" is added to every response
+// with a compressible content type, compressed or not, so shared caches keep
+// the variants apart.
+//
+// Skip Rules:
+//   - Requests for Server-Sent Events (Accept: text/event-stream) and protocol upgrades
+//   - HEAD requests and bodyless statuses (1xx, 204, 304)
+//   - Responses that already set Content-Encoding or whose Content-Type is not listed
+//   - Responses flushed before MinSize bytes were written are sent as they stream
+//
+// Parameters:
+//   - cfg: Compression configuration from config.Server
+//   - logger: Logger for configuration warnings
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function compatible with Gin router
+func compressionMiddleware(cfg config.ServerCompression, logger *slog.Logger) gin.HandlerFunc {
+	policy := newCompressionPolicy(cfg, logger)
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead ||
+			strings.Contains(c.GetHeader("Accept"), "text/event-stream") ||
+			c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		cw := &compressWriter{
+			ResponseWriter: c.Writer,
+			policy:         policy,
+			compressor:     policy.negotiate(c.GetHeader("Accept-Encoding")),
+		}
+		c.Writer = cw
+		completed := false
+		defer func() {
+			if !completed {
+				// A panic is passing through; never send its partial body as a success
+				cw.discardBuffered()
+			}
+			cw.finish()
+			c.Writer = cw.ResponseWriter
+		}()
+		c.Next()
+		completed = true
+	}
+}
+
+// newCompressionPolicy builds encoder pools for the configured algorithms.
+func newCompressionPolicy(cfg config.ServerCompression, logger *slog.Logger) *compressionPolicy {
+	policy := &compressionPolicy{minSize: cfg.MinSize}
+	for _, ct := range cfg.ContentTypes {
+		policy.contentTypes = append(policy.contentTypes, strings.ToLower(strings.TrimSpace(ct)))
+	}
+
+	for _, name := range cfg.Algorithms {
+		switch strings.ToLower(name) {
+		case "gzip":
+			level := cfg.GzipLevel
+			if level < gzip.BestSpeed || level > gzip.BestCompression {
+				level = gzip.DefaultCompression
+			}
+			policy.compressors = append(policy.compressors, &compressor{name: "gzip", pool: sync.Pool{New: func() any {
+				w, _ := gzip.NewWriterLevel(io.Discard, level) // Level validated above
+				return w
+			}}})
+		case "zstd":
+			level := zstd.EncoderLevelFromZstd(cfg.ZstdLevel)
+			policy.compressors = append(policy.compressors, &compressor{name: "zstd", pool: sync.Pool{New: func() any {
+				// One goroutine per encoder: requests already run concurrently
+				w, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderLevel(level), zstd.WithEncoderConcurrency(1))
+				return w
+			}}})
+		default:
+			logger.Warn("⚠️ Unknown compression algorithm ignored",
+				slog.String("algorithm", name), // Supported: zstd, gzip
+			)
+		}
+	}
+	return policy
+}
+
+// negotiate picks the first configured encoding the client accepts, or nil.
+func (p *compressionPolicy) negotiate(acceptEncoding string) *compressor {
+	if acceptEncoding == "" {
+		return nil
+	}
+	accepted := make(map[string]bool)
+	wildcard := false
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		ok := true
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v <= 0 {
+				ok = false
+			}
+		}
+		if name == "*" {
+			wildcard = ok
+			continue
+		}
+		accepted[name] = ok
+	}
+	for _, c := range p.compressors {
+		if ok, listed := accepted[c.name]; ok || (!listed && wildcard) {
+			return c
+		}
+	}
+	return nil
+}
+
+// compressible reports whether responses with the given Content-Type may be compressed.
+func (p *compressionPolicy) compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "text/event-stream" {
+		return false
+	}
+	for _, allowed := range p.contentTypes {
+		if mediaType == allowed || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(mediaType, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressWriter buffers the start of a response until it can decide whether
+// to compress, then either streams through an encoder or passes writes on.
+type compressWriter struct {
+	gin.ResponseWriter
+	policy     *compressionPolicy
+	compressor *compressor // Negotiated encoding, nil if the client accepts none
+
+	buf     []byte  // Body written before the decision
+	decided bool    // Whether the compression decision has been made
+	enc     encoder // Active encoder, nil when passing through
+}
+
+// Write implements io.Writer.
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		w.buf = append(w.buf, p...)
+		if len(w.buf) < w.policy.minSize {
+			return len(p), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if w.enc != nil {
+		return w.enc.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// WriteString implements io.StringWriter.
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow sends the headers immediately; the body, if any, is not compressed.
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.decide(false)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Written reports whether the headers were sent. Body bytes still held in the
+// buffer do not count, so the status can change until the decision is made.
+func (w *compressWriter) Written() bool {
+	return w.ResponseWriter.Written()
+}
+
+// discardBuffered drops body bytes not yet sent, e.g. the partial output of a
+// handler that panicked, so an error response can replace them.
+func (w *compressWriter) discardBuffered() {
+	if !w.decided {
+		w.buf = nil
+	}
+}
+
+// Flush sends buffered data to the client. Flushing before MinSize is reached
+// decides against compression, which keeps streaming responses streaming.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(false)
+	}
+	if w.enc != nil {
+		w.enc.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide sets the response headers for compression, or not, and writes out the buffer.
+func (w *compressWriter) decide(largeEnough bool) error {
+	w.decided = true
+	header := w.Header()
+
+	status := w.Status()
+	bodyless := status < 200 || status == http.StatusNoContent || status == http.StatusNotModified
+	if !bodyless && header.Get("Content-Encoding") == "" {
+		contentType := header.Get("Content-Type")
+		if contentType == "" && len(w.buf) > 0 {
+			// Set it now, as net/http would on the first write
+			contentType = http.DetectContentType(w.buf)
+			header.Set("Content-Type", contentType)
+		}
+		if w.policy.compressible(contentType) {
+			header.Add("Vary", "Accept-Encoding")
+			if largeEnough && w.compressor != nil {
+				header.Set("Content-Encoding", w.compressor.name)
+				header.Del("Content-Length")
+				if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+					header.Set("ETag", "W/"+etag)
+				}
+				w.enc = w.compressor.get(w.ResponseWriter)
+			}
+		}
+	}
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if w.enc != nil {
+		_, err := w.enc.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// finish completes the response after the handler chain returns.
+func (w *compressWriter) finish() {
+	if !w.decided {
+		// Still below MinSize, otherwise Write would have decided
+		w.decide(false)
+	}
+	if w.enc != nil {
+		w.enc.Close()
+		w.enc.Reset(io.Discard) // Drop the reference to the response writer
+		w.compressor.pool.Put(w.enc)
+		w.enc = nil
+	}
+}
@@ -0,0 +1,267 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+)
+
+// corsPolicy is a config.CORSPolicy prepared for per-request matching.
+type corsPolicy struct {
+	origins          []string        // Lower-cased exact origins and wildcard patterns
+	anyOrigin        bool            // "*" listed in AllowedOrigins
+	methods          map[string]bool // Upper-cased allowed methods
+	allowMethods     string          // Access-Control-Allow-Methods value
+	headers          map[string]bool // Canonical allowed request headers
+	anyHeader        bool            // "*" listed in AllowedHeaders
+	allowHeaders     string          // Access-Control-Allow-Headers value
+	exposeHeaders    string          // Access-Control-Expose-Headers value
+	allowCredentials bool
+	maxAge           string // Access-Control-Max-Age value, empty to omit
+}
+
+// corsGroup binds a policy to a route group path prefix.
+type corsGroup struct {
+	prefix string
+	policy *corsPolicy
+}
+
+// corsMiddleware creates a Gin middleware implementing CORS from cfg.
+//
+// The middleware must be installed with router.Use so it also runs for
+// OPTIONS requests that match no route: preflight requests are answered
+// directly (204 when allowed, 403 when not) and never reach the handlers.
+//
+// Policy Selection:
+//   - Requests under a path prefix in cfg.Groups use that group's policy (longest prefix wins)
+//   - All other requests use the default policy
+//   - A policy without allowed origins leaves requests untouched, so browsers apply same-origin rules
+//
+// Caching:
+// "Vary: Origin" is added to every response covered by a policy, and preflight
+// responses also vary on the requested method and headers, so shared caches
+// never serve one origin's CORS headers to another.
+//
+// Parameters:
+//   - cfg: CORS configuration from config.Server
+//   - logger: Logger for rejected preflight requests
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function compatible with Gin router
+func corsMiddleware(cfg config.ServerCORS, logger *slog.Logger) gin.HandlerFunc {
+	defaultPolicy := newCORSPolicy(cfg.CORSPolicy)
+
+	groups := make([]corsGroup, 0, len(cfg.Groups))
+	for prefix, groupCfg := range cfg.Groups {
+		groups = append(groups, corsGroup{
+			prefix: prefix,
+			policy: newCORSPolicy(inheritCORSPolicy(groupCfg, cfg.CORSPolicy)),
+		})
+	}
+	// Longest prefix first so the most specific group wins
+	sort.Slice(groups, func(i, j int) bool { return len(groups[i].prefix) > len(groups[j].prefix) })
+
+	return func(c *gin.Context) {
+		policy := defaultPolicy
+		for _, g := range groups {
+			if strings.HasPrefix(c.Request.URL.Path, g.prefix) {
+				policy = g.policy
+				break
+			}
+		}
+		if len(policy.origins) == 0 && !policy.anyOrigin {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			// Same-origin or non-browser request
+			c.Next()
+			return
+		}
+
+		exact, allowed := policy.matchOrigin(origin)
+		if !allowed {
+			if preflight {
+				logger.Debug("🌐 CORS preflight rejected",
+					slog.String("origin", origin),
+					slog.String("path", c.Request.URL.Path),
+					slog.String("reason", "origin not allowed"),
+				)
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			// Serve without CORS headers; the browser withholds the response from the page
+			c.Next()
+			return
+		}
+
+		if preflight {
+			method := strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))
+			requested := c.GetHeader("Access-Control-Request-Headers")
+			if reason := policy.checkPreflight(method, requested); reason != "" {
+				logger.Debug("🌐 CORS preflight rejected",
+					slog.String("origin", origin),
+					slog.String("path", c.Request.URL.Path),
+					slog.String("reason", reason),
+				)
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+		}
+
+		// Credentials are only allowed for explicitly listed origins, never via "*"
+		credentials := policy.allowCredentials && exact
+		if credentials || !policy.anyOrigin {
+			header.Set("Access-Control-Allow-Origin", origin)
+		} else {
+			header.Set("Access-Control-Allow-Origin", "*")
+		}
+		if credentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			header.Set("Access-Control-Allow-Methods", policy.allowMethods)
+			if policy.anyHeader {
+				if requested := c.GetHeader("Access-Control-Request-Headers"); requested != "" {
+					header.Set("Access-Control-Allow-Headers", requested)
+				}
+			} else if policy.allowHeaders != "" {
+				header.Set("Access-Control-Allow-Headers", policy.allowHeaders)
+			}
+			if policy.maxAge != "" {
+				header.Set("Access-Control-Max-Age", policy.maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if policy.exposeHeaders != "" {
+			header.Set("Access-Control-Expose-Headers", policy.exposeHeaders)
+		}
+		c.Next()
+	}
+}
+
+// inheritCORSPolicy fills empty lists and a zero MaxAge of a group policy from the default policy.
+func inheritCORSPolicy(group, base config.CORSPolicy) config.CORSPolicy {
+	if len(group.AllowedOrigins) == 0 {
+		group.AllowedOrigins = base.AllowedOrigins
+	}
+	if len(group.AllowedMethods) == 0 {
+		group.AllowedMethods = base.AllowedMethods
+	}
+	if len(group.AllowedHeaders) == 0 {
+		group.AllowedHeaders = base.AllowedHeaders
+	}
+	if len(group.ExposedHeaders) == 0 {
+		group.ExposedHeaders = base.ExposedHeaders
+	}
+	if group.MaxAge == 0 {
+		group.MaxAge = base.MaxAge
+	}
+	return group
+}
+
+// newCORSPolicy normalizes cfg for matching.
+func newCORSPolicy(cfg config.CORSPolicy) *corsPolicy {
+	p := &corsPolicy{
+		methods:          make(map[string]bool),
+		headers:          make(map[string]bool),
+		allowCredentials: cfg.AllowCredentials,
+	}
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			p.anyOrigin = true
+			continue
+		}
+		p.origins = append(p.origins, strings.ToLower(strings.TrimSuffix(origin, "/")))
+	}
+
+	methods := make([]string, 0, len(cfg.AllowedMethods))
+	for _, m := range cfg.AllowedMethods {
+		m = strings.ToUpper(strings.TrimSpace(m))
+		p.methods[m] = true
+		methods = append(methods, m)
+	}
+	p.allowMethods = strings.Join(methods, ", ")
+
+	headers := make([]string, 0, len(cfg.AllowedHeaders))
+	for _, h := range cfg.AllowedHeaders {
+		if h == "*" {
+			p.anyHeader = true
+			continue
+		}
+		h = http.CanonicalHeaderKey(strings.TrimSpace(h))
+		p.headers[h] = true
+		headers = append(headers, h)
+	}
+	p.allowHeaders = strings.Join(headers, ", ")
+	p.exposeHeaders = strings.Join(cfg.ExposedHeaders, ", ")
+
+	if cfg.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(cfg.MaxAge)
+	}
+	return p
+}
+
+// matchOrigin reports whether origin is allowed and whether it matched an
+// explicitly listed origin or pattern rather than "*".
+func (p *corsPolicy) matchOrigin(origin string) (exact bool, allowed bool) {
+	origin = strings.ToLower(origin)
+	for _, pattern := range p.origins {
+		if matchOriginPattern(pattern, origin) {
+			return true, true
+		}
+	}
+	return false, p.anyOrigin
+}
+
+// matchOriginPattern matches origin against an exact origin or a pattern with
+// a "*." subdomain wildcard such as "https://*.example.com" or "http://*.local:3000".
+func matchOriginPattern(pattern, origin string) bool {
+	i := strings.Index(pattern, "*.")
+	if i < 0 {
+		return pattern == origin
+	}
+	prefix, suffix := pattern[:i], pattern[i+1:] // suffix keeps the leading dot
+	if len(origin) <= len(prefix)+len(suffix) || !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) {
+		return false
+	}
+	// The wildcard stands for one or more DNS labels, never a port, path or credentials
+	sub := origin[len(prefix) : len(origin)-len(suffix)]
+	return !strings.ContainsAny(sub, ":/@")
+}
+
+// checkPreflight validates the requested method and headers and returns a
+// rejection reason, or "" when the preflight is allowed.
+func (p *corsPolicy) checkPreflight(method, requestedHeaders string) string {
+	if !p.methods[method] {
+		return "method not allowed: " + method
+	}
+	if p.anyHeader || requestedHeaders == "" {
+		return ""
+	}
+	for _, h := range strings.Split(requestedHeaders, ",") {
+		h = http.CanonicalHeaderKey(strings.TrimSpace(h))
+		if h != "" && !p.headers[h] {
+			return "header not allowed: " + h
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apperror"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/lifecycle"
+)
+
+// Drain begins the pre-shutdown phase. /ready starts answering 503 so load
+// balancers take the instance out of rotation, and keep-alives are disabled
+// so clients open their next connection elsewhere. Requests keep being
+// served throughout. Drain then waits DrainDelay for load balancers to
+// notice before returning; call Shutdown afterwards.
+//
+// Parameters:
+//   - ctx: Bounds the wait; Drain returns ctx.Err() if it is done first
+//
+// Returns:
+//   - error: Context error if the delay was cut short
+func (s *Server) Drain(ctx context.Context) error {
+	delay := time.Duration(s.config.DrainDelay) * time.Second
+	s.draining.Store(true)
+	s.server.SetKeepAlivesEnabled(false)
+	s.mu.Lock()
+	admin := s.admin
+	s.mu.Unlock()
+	if admin != nil {
+		admin.SetKeepAlivesEnabled(false)
+	}
+
+	s.logger.Info("🚦 Draining: readiness reports not ready",
+		slog.Duration("delay", delay),            // Time load balancers get to stop routing here
+		slog.Int64("in_flight", s.InFlight()),    // Requests being served right now
+		slog.Int("workers", s.workers.Running()), // Background workers still running
+	)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Draining reports whether Drain has been called.
+func (s *Server) Draining() bool {
+	return s.draining.Load()
+}
+
+// InFlight returns the number of public API requests being served.
+func (s *Server) InFlight() int64 {
+	return s.inFlight.Load()
+}
+
+// Workers returns the background workers of the server. Start long-running
+// work with Workers().Go so shutdown cancels it and waits for it; stop them
+// with Workers().Stop after Shutdown.
+func (s *Server) Workers() *lifecycle.Workers {
+	return s.workers
+}
+
+// inFlightMiddleware counts requests being served for drain logging.
+func (s *Server) inFlightMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+		c.Next()
+	}
+}
+
+// readyResponse is the body of a successful readiness check.
+type readyResponse struct {
+	Status string `json:"status"` // Always "ready"
+}
+
+// handleReady reports whether the instance should receive traffic. Unlike
+// /health, which only says the process is alive, it fails as soon as
+// draining starts.
+//
+// Responses:
+//   - 200 {"status": "ready"}
+//   - 503 service_unavailable problem while draining
+func (s *Server) handleReady(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	if s.draining.Load() {
+		handlers.WriteError(c, apperror.ServiceUnavailable("server is shutting down"))
+		return
+	}
+	c.JSON(http.StatusOK, readyResponse{Status: "ready"})
+}
@@ -0,0 +1,270 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apperror"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/idempotency"
+)
+
+// idempotencyKeyHeader carries the client-chosen key of a retryable request.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength bounds keys so they fit comfortably in a document ID.
+const maxIdempotencyKeyLength = 255
+
+// idempotencyStoreTimeout bounds storing or releasing a key after the handler
+// finished, independently of the request context.
+const idempotencyStoreTimeout = 5 * time.Second
+
+// unstoredResponseHeaders are set by outer middleware per response, so they
+// are recomputed on replay instead of being stored.
+var unstoredResponseHeaders = map[string]bool{
+	"Content-Encoding":  true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+	"Vary":              true,
+	"Date":              true,
+}
+
+// SetIdempotencyStore replaces the store of Idempotency-Key records, e.g.
+// with a MongoDB store shared by all instances. Until it is called, keys are
+// kept in memory. It has no effect when idempotency handling is disabled.
+//
+// Example:
+//
+//	store, err := idempotency.OpenStore(ctx, cfg.Server.Idempotency, dbManager.GetDatabase())
+//	httpServer.SetIdempotencyStore(store)
+func (s *Server) SetIdempotencyStore(store idempotency.Store) {
+	cfg := s.config.Idempotency
+	s.idempotency.Store(idempotency.New(store,
+		time.Duration(cfg.TTL)*time.Second,
+		time.Duration(cfg.LockTimeout)*time.Second,
+	))
+}
+
+// idempotencyMiddleware creates a Gin middleware honouring the
+// Idempotency-Key header on the configured methods.
+//
+// Behaviour:
+//   - First request with a key: runs normally; its response is stored
+//   - Retry with the same key, method, path and body: the stored response is
+//     replayed with Idempotent-Replayed: true, without running the handler
+//   - Same key with a different method, path or body: 422 idempotency_key_reused
+//   - Retry while the first request is still running: 409 conflict with Retry-After
+//   - Requests without the header, or with other methods, pass through
+//
+// Keys are scoped per client (see idempotencyScope), so the same key sent by
+// two clients names two independent requests.
+//
+// Responses with status 5xx, 408 or 429, or bodies over MaxResponseBytes, are
+// not stored; the key is released so the client can retry. When the store
+// cannot be reached the request is rejected with 503 rather than risking a
+// duplicate.
+func (s *Server) idempotencyMiddleware() gin.HandlerFunc {
+	cfg := s.config.Idempotency
+	methods := make(map[string]bool, len(cfg.Methods))
+	for _, m := range cfg.Methods {
+		methods[strings.ToUpper(m)] = true
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" || !methods[c.Request.Method] {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			handlers.WriteError(c, apperror.BadRequest("Idempotency-Key must not be longer than 255 characters"))
+			return
+		}
+
+		// Read the body to fingerprint the request, then hand it on unchanged.
+		// The body limit applies, since limitsMiddleware runs first.
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			handlers.WriteError(c, err)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := idempotency.Fingerprint(c.Request.Method, c.Request.URL.RequestURI(), body)
+
+		scopedKey := idempotencyScope(c) + " " + key
+		claim, record, err := s.idempotency.Load().Begin(c.Request.Context(), scopedKey, fingerprint)
+		switch {
+		case err != nil:
+			s.logger.Error("❌ Idempotency key store unavailable",
+				slog.String("request_id", handlers.RequestID(c)), // Correlates with the response body
+				slog.String("error", err.Error()),                // Store failure
+			)
+			handlers.WriteError(c, apperror.ServiceUnavailable("idempotency keys cannot be checked; retry later").Wrap(err))
+			return
+		case claim != nil:
+			s.runIdempotent(c, key, claim)
+			return
+		case record.Fingerprint != fingerprint:
+			handlers.WriteError(c, apperror.New(http.StatusUnprocessableEntity, apperror.CodeIdempotencyKeyReused,
+				"Idempotency-Key was already used for a different request"))
+			return
+		case !record.Completed:
+			c.Header("Retry-After", "1")
+			handlers.WriteError(c, apperror.Conflict("a request with this Idempotency-Key is still being processed"))
+			return
+		}
+
+		// Replay the stored response
+		s.logger.Debug("🔁 Replaying idempotent response",
+			slog.String("request_id", handlers.RequestID(c)), // Correlates with the retry
+			slog.Int("status", record.Response.Status),       // Stored status
+			slog.String("path", c.Request.URL.Path),          // Request path
+		)
+		header := c.Writer.Header()
+		for name, values := range record.Response.Header {
+			header[name] = values
+		}
+		header.Set("Idempotent-Replayed", "true")
+		c.Writer.WriteHeader(record.Response.Status)
+		_, _ = c.Writer.Write(record.Response.Body)
+		c.Abort()
+	}
+}
+
+// userIDContextKey is the Gin context key under which authentication
+// middleware stores the authenticated user ID.
+const userIDContextKey = "user_id"
+
+// apiKeyIDContextKey is the Gin context key under which authentication
+// middleware stores the ID of the API key it verified. Unverified X-API-Key
+// headers are never used to identify a client, since anyone can send them.
+const apiKeyIDContextKey = "api_key_id"
+
+// idempotencyScope names the client whose keys a request may use: the
+// verified API key or authenticated user stored by authentication middleware
+// registered before the idempotency middleware, otherwise the client IP. One
+// client can therefore neither occupy nor probe the keys of another.
+func idempotencyScope(c *gin.Context) string {
+	if keyID := c.GetString(apiKeyIDContextKey); keyID != "" {
+		return "api_key:" + keyID
+	}
+	if userID := c.GetString(userIDContextKey); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// runIdempotent runs the handler chain for the first request with key, then
+// stores its response or releases the key.
+func (s *Server) runIdempotent(c *gin.Context, key string, claim *idempotency.Claim) {
+	before := c.Writer.Header().Clone() // Headers set by outer middleware are not stored
+	recorder := &responseRecorder{ResponseWriter: c.Writer, limit: s.config.Idempotency.MaxResponseBytes}
+	c.Writer = recorder
+
+	// Store the outcome even if the client went away or the request deadline passed
+	storeContext := func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.WithoutCancel(c.Request.Context()), idempotencyStoreTimeout)
+	}
+
+	// A panicking handler must not hold the key until the lock timeout, and
+	// recovery must write its 500 response through the original writer.
+	completed := false
+	defer func() {
+		c.Writer = recorder.ResponseWriter
+		if !completed {
+			ctx, cancel := storeContext()
+			defer cancel()
+			s.releaseIdempotencyKey(ctx, c, claim)
+		}
+	}()
+
+	c.Next()
+	completed = true
+
+	ctx, cancel := storeContext()
+	defer cancel()
+
+	status := c.Writer.Status()
+	if !storableStatus(status) || recorder.overflow {
+		s.releaseIdempotencyKey(ctx, c, claim)
+		return
+	}
+
+	header := http.Header{}
+	for name, values := range c.Writer.Header() {
+		if !unstoredResponseHeaders[name] && !slices.Equal(before[name], values) {
+			header[name] = values
+		}
+	}
+	err := claim.Complete(ctx, idempotency.Response{Status: status, Header: header, Body: recorder.body.Bytes()})
+	if err != nil {
+		level := slog.LevelError
+		if errors.Is(err, idempotency.ErrLockLost) {
+			level = slog.LevelWarn // Another attempt took over after the lock timeout
+		}
+		s.logger.Log(ctx, level, "⚠️ Failed to store idempotent response",
+			slog.String("request_id", handlers.RequestID(c)), // Correlates with the request log
+			slog.String("key", key),                          // Client-chosen key
+			slog.String("error", err.Error()),                // Store failure
+		)
+	}
+}
+
+// releaseIdempotencyKey gives up claim so the client can retry with its key.
+func (s *Server) releaseIdempotencyKey(ctx context.Context, c *gin.Context, claim *idempotency.Claim) {
+	if err := claim.Release(ctx); err != nil {
+		s.logger.Warn("⚠️ Failed to release idempotency key",
+			slog.String("request_id", handlers.RequestID(c)), // Correlates with the request log
+			slog.String("error", err.Error()),                // Store failure; the key frees itself after the lock timeout
+		)
+	}
+}
+
+// storableStatus reports whether a response with status is final for its
+// key. Server errors, timeouts and rate limiting are worth retrying.
+func storableStatus(status int) bool {
+	return status < http.StatusInternalServerError &&
+		status != http.StatusRequestTimeout &&
+		status != http.StatusTooManyRequests
+}
+
+// responseRecorder copies the response body up to limit bytes while writing it.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	limit    int
+	overflow bool // The body exceeded limit and was not kept
+}
+
+// Write implements io.Writer.
+func (w *responseRecorder) Write(p []byte) (int, error) {
+	w.record(p)
+	return w.ResponseWriter.Write(p)
+}
+
+// WriteString implements io.StringWriter.
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.record([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// record keeps p unless the body grew past the limit.
+func (w *responseRecorder) record(p []byte) {
+	if w.overflow {
+		return
+	}
+	if w.body.Len()+len(p) > w.limit {
+		w.overflow = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(p)
+}
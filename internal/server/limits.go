@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apperror"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+)
+
+// routeLimits is a config.RouteLimits prepared for per-request use.
+type routeLimits struct {
+	prefix         string        // Route group path prefix, empty for the defaults
+	maxBodyBytes   int64         // 0 means unlimited
+	handlerTimeout time.Duration // 0 means no deadline
+}
+
+// limitsMiddleware creates a Gin middleware enforcing request body size caps
+// and handler deadlines.
+//
+// Limit Selection:
+//   - Requests under a path prefix in cfg.Groups use that group's limits (longest prefix wins)
+//   - Group values of 0 inherit the defaults; -1 removes the limit for the group
+//
+// Body Size:
+// Requests whose Content-Length exceeds the limit are rejected with 413
+// before the handler runs. Other bodies are wrapped with http.MaxBytesReader,
+// so reads past the limit fail with *http.MaxBytesError, which
+// handlers.WriteError reports as 413 as well.
+//
+// Handler Deadline:
+// The request context gets a deadline and is canceled when it passes. The
+// deadline is cooperative: handlers and the clients they call must honour
+// the request context. If the deadline passed and the handler has not
+// responded, a 503 request_timeout problem is sent. Handlers returning
+// context.DeadlineExceeded get the same 503 through handlers.WriteError, or
+// 504 upstream_timeout when an upstream call timed out on its own while the
+// request still had time left.
+//
+// Parameters:
+//   - cfg: Limits configuration from config.Server
+//   - logger: Logger for expired handler deadlines
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function compatible with Gin router
+func limitsMiddleware(cfg config.ServerLimits, logger *slog.Logger) gin.HandlerFunc {
+	defaults := newRouteLimits("", cfg.RouteLimits)
+
+	groups := make([]routeLimits, 0, len(cfg.Groups))
+	for prefix, groupCfg := range cfg.Groups {
+		if groupCfg.MaxBodyBytes == 0 {
+			groupCfg.MaxBodyBytes = cfg.MaxBodyBytes
+		}
+		if groupCfg.HandlerTimeout == 0 {
+			groupCfg.HandlerTimeout = cfg.HandlerTimeout
+		}
+		groups = append(groups, newRouteLimits(prefix, groupCfg))
+	}
+	// Longest prefix first so the most specific group wins
+	sort.Slice(groups, func(i, j int) bool { return len(groups[i].prefix) > len(groups[j].prefix) })
+
+	return func(c *gin.Context) {
+		limits := defaults
+		for _, g := range groups {
+			if strings.HasPrefix(c.Request.URL.Path, g.prefix) {
+				limits = g
+				break
+			}
+		}
+
+		if limits.maxBodyBytes > 0 {
+			if c.Request.ContentLength > limits.maxBodyBytes {
+				handlers.WriteError(c, apperror.PayloadTooLarge(limits.maxBodyBytes))
+				return
+			}
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limits.maxBodyBytes)
+		}
+
+		if limits.handlerTimeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), limits.handlerTimeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			logger.Warn("⏱️ Handler deadline exceeded",
+				slog.String("request_id", handlers.RequestID(c)), // Correlates with the response body
+				slog.String("method", c.Request.Method),          // HTTP method
+				slog.String("route", c.FullPath()),               // Route pattern, e.g. /api/v1/courses/:id
+				slog.Duration("timeout", limits.handlerTimeout),  // Configured deadline
+				slog.Bool("responded", c.Writer.Written()),       // False when the timeout response was sent
+			)
+			if !c.Writer.Written() {
+				handlers.WriteError(c, apperror.Timeout())
+			}
+		}
+	}
+}
+
+// newRouteLimits converts configured limits, treating negative values as unlimited.
+func newRouteLimits(prefix string, cfg config.RouteLimits) routeLimits {
+	limits := routeLimits{prefix: prefix}
+	if cfg.MaxBodyBytes > 0 {
+		limits.maxBodyBytes = int64(cfg.MaxBodyBytes)
+	}
+	if cfg.HandlerTimeout > 0 {
+		limits.handlerTimeout = time.Duration(cfg.HandlerTimeout) * time.Second
+	}
+	return limits
+}
@@ -0,0 +1,289 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/restart"
+)
+
+// Names of inherited sockets. Systemd sockets are named with
+// FileDescriptorName; sockets handed over by a graceful restart use the same names.
+const (
+	publicSocketName   = "http"     // Serves the public API; also any unnamed or unknown socket
+	adminSocketName    = "admin"    // Serves the admin router
+	redirectSocketName = "redirect" // Serves HTTP-to-HTTPS redirects
+)
+
+// listenFDsStart is the first file descriptor passed by systemd socket activation.
+const listenFDsStart = 3
+
+// namedListener is a listener inherited from the parent process with its name.
+type namedListener struct {
+	name     string
+	listener net.Listener
+}
+
+// parseListenAddress splits a configured address into the network and
+// address arguments of net.Listen.
+//
+// Accepted forms:
+//   - "unix:///run/goedu.sock": Unix domain socket
+//   - "tcp://0.0.0.0:8080": TCP address
+//   - "0.0.0.0:8080": TCP address without a scheme
+func parseListenAddress(addr string) (network, address string, err error) {
+	switch {
+	case strings.HasPrefix(addr, "unix://"):
+		path := strings.TrimPrefix(addr, "unix://")
+		if path == "" {
+			return "", "", fmt.Errorf("address %q has no socket path", addr)
+		}
+		return "unix", path, nil
+	case strings.HasPrefix(addr, "tcp://"):
+		return "tcp", strings.TrimPrefix(addr, "tcp://"), nil
+	case strings.Contains(addr, "://"):
+		return "", "", fmt.Errorf("address %q has an unsupported scheme (want unix:// or tcp://)", addr)
+	}
+	return "tcp", addr, nil
+}
+
+// listenerAddress formats the bound address of l in the configuration syntax,
+// so Unix sockets are reported as "unix:///path".
+func listenerAddress(l net.Listener) string {
+	if addr, ok := l.Addr().(*net.UnixAddr); ok {
+		return "unix://" + addr.Name
+	}
+	return l.Addr().String()
+}
+
+// listen binds the listener described by spec. Unix domain sockets get the
+// configured file mode and group; a stale socket left behind by a crashed
+// process is replaced, but a socket another process still accepts on is not.
+func listen(spec config.ServerListener) (net.Listener, error) {
+	network, address, err := parseListenAddress(spec.Address)
+	if err != nil {
+		return nil, err
+	}
+	if network != "unix" {
+		return net.Listen(network, address)
+	}
+
+	mode, err := parseSocketMode(spec.SocketMode)
+	if err != nil {
+		return nil, err
+	}
+	if err := removeStaleSocket(address); err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("unix", address)
+	if err != nil {
+		return nil, err
+	}
+	// Closing the listener removes the socket file again
+	if err := os.Chmod(address, mode); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("failed to set mode of %s: %w", address, err)
+	}
+	if spec.SocketGroup != "" {
+		gid, err := lookupGroupID(spec.SocketGroup)
+		if err == nil {
+			err = os.Chown(address, -1, gid)
+		}
+		if err != nil {
+			l.Close()
+			return nil, fmt.Errorf("failed to set group of %s: %w", address, err)
+		}
+	}
+	return unixListener{l}, nil
+}
+
+// parseSocketMode parses an octal file mode such as "0660". Empty means 0660.
+func parseSocketMode(mode string) (os.FileMode, error) {
+	if mode == "" {
+		return 0o660, nil
+	}
+	value, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil || value > 0o777 {
+		return 0, fmt.Errorf("invalid socket mode %q (want octal permissions such as 0660)", mode)
+	}
+	return os.FileMode(value), nil
+}
+
+// lookupGroupID resolves a group name or numeric GID.
+func lookupGroupID(group string) (int, error) {
+	if gid, err := strconv.Atoi(group); err == nil {
+		return gid, nil
+	}
+	g, err := user.LookupGroup(group)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(g.Gid)
+}
+
+// removeStaleSocket deletes a leftover socket file at path. It refuses to
+// delete regular files and sockets that still accept connections.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s exists and is not a socket", path)
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return fmt.Errorf("%s is in use by another process", path)
+	}
+	return os.Remove(path)
+}
+
+// inheritedListeners returns the listening sockets handed over by a graceful
+// restart or, if systemd is set, passed by systemd socket activation. It
+// returns nil when the process inherited no sockets.
+func inheritedListeners(systemd bool) ([]namedListener, error) {
+	files := restart.Inherited()
+	if files == nil && systemd {
+		files = activatedFiles()
+	}
+
+	listeners := make([]namedListener, 0, len(files))
+	for i, f := range files {
+		// FileListener duplicates the descriptor with close-on-exec set
+		l, err := net.FileListener(f.File)
+		f.File.Close()
+		if err != nil {
+			for _, nl := range listeners {
+				nl.listener.Close()
+			}
+			for _, rest := range files[i+1:] {
+				rest.File.Close()
+			}
+			return nil, fmt.Errorf("inherited socket %q is not a listening socket: %w", f.Name, err)
+		}
+		if _, ok := l.(*net.UnixListener); ok {
+			l = unixListener{l}
+		}
+		listeners = append(listeners, namedListener{name: f.Name, listener: l})
+	}
+	return listeners, nil
+}
+
+// activatedFiles returns the sockets passed by systemd socket activation
+// (sd_listen_fds protocol), or nil when the process was not socket-activated.
+// The LISTEN_* variables are removed from the environment so child processes
+// do not try to claim the same sockets.
+func activatedFiles() []restart.Listener {
+	if pid, err := strconv.Atoi(os.Getenv("LISTEN_PID")); err != nil || pid != os.Getpid() {
+		return nil
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		return nil
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	files := make([]restart.Listener, 0, count)
+	for i := 0; i < count; i++ {
+		name := ""
+		if i < len(names) {
+			name = names[i]
+		}
+		files = append(files, restart.Listener{Name: name, File: os.NewFile(uintptr(listenFDsStart+i), name)})
+	}
+	return files
+}
+
+// listenerFile returns a duplicate of the listening socket of l for handing
+// over to a new process. Unix socket files are no longer removed when l is
+// closed, since the new process keeps serving them.
+//
+// The descriptor is duplicated directly rather than with File, whose result
+// switches the shared socket to blocking mode when passed to a new process;
+// a blocking accept would keep this server's Shutdown from returning.
+func listenerFile(l net.Listener) (*os.File, error) {
+	if ul, ok := l.(unixListener); ok {
+		l = ul.Listener
+	}
+	var raw syscall.RawConn
+	var err error
+	switch l := l.(type) {
+	case *net.TCPListener:
+		raw, err = l.SyscallConn()
+	case *net.UnixListener:
+		l.SetUnlinkOnClose(false)
+		raw, err = l.SyscallConn()
+	default:
+		return nil, fmt.Errorf("listener %s cannot be handed over", listenerAddress(l))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var dup int
+	var dupErr error
+	err = raw.Control(func(fd uintptr) {
+		// Hold the fork lock so no process started meanwhile inherits the descriptor
+		syscall.ForkLock.RLock()
+		defer syscall.ForkLock.RUnlock()
+		if dup, dupErr = syscall.Dup(int(fd)); dupErr == nil {
+			syscall.CloseOnExec(dup)
+		}
+	})
+	if err == nil {
+		err = dupErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to duplicate listener %s: %w", listenerAddress(l), err)
+	}
+	return os.NewFile(uintptr(dup), listenerAddress(l)), nil
+}
+
+// closeListeners closes every non-nil listener, used to undo a partial bind.
+func closeListeners(listeners ...net.Listener) {
+	for _, l := range listeners {
+		if l != nil {
+			l.Close()
+		}
+	}
+}
+
+// unixListener makes connections accepted on a Unix domain socket report a
+// loopback remote address. Unix socket peers are local by definition, and
+// without an IP address Gin could neither trust a local reverse proxy's
+// X-Forwarded-For header nor tell clients apart for logging and rate limiting.
+type unixListener struct {
+	net.Listener
+}
+
+// Accept implements net.Listener.
+func (l unixListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return unixConn{conn}, nil
+}
+
+// unixConn is a Unix socket connection reporting a loopback remote address.
+type unixConn struct {
+	net.Conn
+}
+
+// RemoteAddr implements net.Conn.
+func (c unixConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/openapi"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/routes"
+)
+
+// apiInfo describes the public API in the generated OpenAPI document.
+var apiInfo = openapi.Info{
+	Title:       "GoEdu-Theta API",
+	Version:     "1.0.0",
+	Description: "Public API of the GoEdu-Theta server. Errors are RFC 9457 problem details (application/problem+json).",
+}
+
+// explorerHTML is the API explorer page served at /docs. It is self-contained
+// so it works without internet access and under the default CSP.
+//
+//go:embed explorer.html
+var explorerHTML string
+
+// explorerTemplate renders explorerHTML with the request's CSP nonce.
+var explorerTemplate = template.Must(template.New("explorer").Parse(explorerHTML))
+
+// handle registers a route on the public router together with its OpenAPI
+// description, and records it in the route registry. Register every public
+// route through handle; a test fails for routes registered on the router
+// directly.
+//
+// Parameters:
+//   - method: HTTP method, e.g. http.MethodGet
+//   - path: Route pattern in Gin syntax, e.g. "/courses/:id"
+//   - op: OpenAPI description of the route
+//   - handler: Gin handler serving the route
+func (s *Server) handle(method, path string, op openapi.Operation, handler gin.HandlerFunc) {
+	s.router.Handle(method, path, handler)
+	s.api.Add(method, path, withCommonResponses(op, s.config))
+	s.routes.Add(routes.Route{
+		Listener:    routes.ListenerPublic,
+		Method:      method,
+		Path:        path,
+		Description: op.Summary,
+		Auth:        routes.AuthNone, // The public API has no authentication yet
+		Deprecated:  op.Deprecated,
+	})
+}
+
+// Routes returns the registry of all routes registered on the public router
+// and, when the admin listener is configured, the admin router.
+func (s *Server) Routes() *routes.Registry {
+	return s.routes
+}
+
+// withCommonResponses adds the error responses middleware can send on any
+// route, unless op describes them already.
+func withCommonResponses(op openapi.Operation, cfg config.Server) openapi.Operation {
+	responses := make(map[int]openapi.Response, len(op.Responses)+2)
+	if cfg.RateLimit.Enabled {
+		responses[http.StatusTooManyRequests] = openapi.ProblemResponse("Rate limit exceeded; see Retry-After")
+	}
+	responses[http.StatusInternalServerError] = openapi.ProblemResponse("Unexpected server error")
+	for status, resp := range op.Responses {
+		responses[status] = resp
+	}
+	op.Responses = responses
+	return op
+}
+
+// OpenAPI returns the OpenAPI document describing the public routes.
+func (s *Server) OpenAPI() *openapi.Document {
+	return s.api
+}
+
+// handleOpenAPI serves the OpenAPI document. It is rendered on first use,
+// after all routes have been registered.
+func (s *Server) handleOpenAPI(c *gin.Context) {
+	data, err := s.apiJSON()
+	if err != nil {
+		handlers.WriteError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "application/json", data)
+}
+
+// handleAPIExplorer serves the interactive API explorer page.
+func (s *Server) handleAPIExplorer(c *gin.Context) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	err := explorerTemplate.Execute(c.Writer, map[string]string{
+		"Nonce":   handlers.CSPNonce(c), // Marks the inline script and style as trusted
+		"SpecURL": "/openapi.json",
+	})
+	if err != nil {
+		_ = c.Error(err) // Headers are sent; the request log records the failure
+	}
+}
+
+// renderOpenAPI marshals the OpenAPI document for handleOpenAPI.
+func (s *Server) renderOpenAPI() ([]byte, error) {
+	return json.Marshal(s.api)
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apperror"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/ratelimit"
+)
+
+// rateLimitRule is a configured policy and the path prefix it applies to.
+type rateLimitRule struct {
+	prefix string // Route group path prefix, empty for the default rule
+	policy ratelimit.Policy
+}
+
+// SetRateLimitStore replaces the bucket store used by the rate limiter, e.g.
+// with a MongoDB store shared by all instances. Until it is called, buckets
+// are kept in memory. It has no effect when rate limiting is disabled.
+//
+// Example:
+//
+//	store, err := ratelimit.OpenStore(ctx, cfg.Server.RateLimit, dbManager.GetDatabase())
+//	httpServer.SetRateLimitStore(store)
+func (s *Server) SetRateLimitStore(store ratelimit.Store) {
+	s.limiter.Store(ratelimit.New(store, s.config.RateLimit.FailClosed))
+}
+
+// rateLimitMiddleware creates a Gin middleware enforcing the configured
+// per-client token bucket policies.
+//
+// Policy Selection:
+//   - Requests under a path prefix in cfg.Groups use that group's policy (longest prefix wins)
+//   - All other requests use the default policy
+//   - Policies with zero requests are not limited
+//   - Requests to cfg.ExemptPaths are never limited
+//
+// Response Headers:
+// Limited requests carry RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset
+// (seconds until the bucket is full) and RateLimit-Policy. Rejected requests
+// receive a 429 Too Many Requests problem response with Retry-After in seconds.
+//
+// Store Failures:
+// When the store cannot be reached the request is allowed, or rejected with
+// 503 if cfg.FailClosed is set; either way a warning is logged.
+func (s *Server) rateLimitMiddleware() gin.HandlerFunc {
+	cfg := s.config.RateLimit
+	defaultRule := rateLimitRule{policy: ratelimit.NewPolicy("default", cfg.RateLimitPolicy)}
+	exempt := make(map[string]bool, len(cfg.ExemptPaths))
+	for _, p := range cfg.ExemptPaths {
+		exempt[p] = true
+	}
+
+	groups := make([]rateLimitRule, 0, len(cfg.Groups))
+	for prefix, groupCfg := range cfg.Groups {
+		if groupCfg.Window == 0 {
+			groupCfg.Window = cfg.Window
+		}
+		groups = append(groups, rateLimitRule{
+			prefix: prefix,
+			policy: ratelimit.NewPolicy(prefix, groupCfg),
+		})
+	}
+	// Longest prefix first so the most specific group wins
+	sort.Slice(groups, func(i, j int) bool { return len(groups[i].prefix) > len(groups[j].prefix) })
+
+	return func(c *gin.Context) {
+		if exempt[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		rule := defaultRule
+		for _, g := range groups {
+			if strings.HasPrefix(c.Request.URL.Path, g.prefix) {
+				rule = g
+				break
+			}
+		}
+		if rule.policy.Unlimited() {
+			c.Next()
+			return
+		}
+
+		result, err := s.limiter.Load().Allow(c.Request.Context(), rule.policy, rateLimitKey(c))
+		if err != nil {
+			s.logger.Warn("⚠️ Rate limit store unavailable",
+				slog.String("policy", rule.policy.Name),  // Policy that could not be applied
+				slog.Bool("fail_closed", cfg.FailClosed), // Whether the request is rejected
+				slog.Any("error", err),
+			)
+			if !result.Allowed {
+				handlers.WriteError(c, apperror.ServiceUnavailable("rate limiter unavailable").Wrap(err))
+				return
+			}
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		header.Set("RateLimit-Policy", rateLimitPolicyHeader(rule.policy))
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			header.Set("Retry-After", strconv.Itoa(retryAfter))
+			s.logger.Debug("🚦 Rate limit exceeded",
+				slog.String("policy", rule.policy.Name),
+				slog.String("client_ip", c.ClientIP()),
+				slog.String("path", c.Request.URL.Path),
+				slog.Int("retry_after", retryAfter), // Seconds until the next token
+			)
+			handlers.WriteError(c, apperror.TooManyRequests("rate limit exceeded").WithDetail("retry_after", retryAfter))
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitKey identifies the client of c by IP. The client IP honours
+// forwarding headers only from the configured trusted proxies, so clients
+// cannot pick their own bucket.
+func rateLimitKey(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// rateLimitPolicyHeader formats the RateLimit-Policy value, e.g. "300;w=60" or "300;w=60;burst=50".
+func rateLimitPolicyHeader(p ratelimit.Policy) string {
+	if p.Burst != p.Limit {
+		return fmt.Sprintf("%d;w=%d;burst=%d", p.Limit, p.Window, p.Burst)
+	}
+	return fmt.Sprintf("%d;w=%d", p.Limit, p.Window)
+}
+
+// ceilSeconds rounds d up to whole seconds.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apperror"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+)
+
+// recoveryMiddleware creates a Gin middleware that turns handler panics into
+// logged 500 problem responses, replacing gin.Recovery.
+//
+// Each panic is logged through slog at Error level with the panic value, stack
+// trace, request ID, matched route and authenticated user, and counted in the
+// panics_total metric. The client receives an internal_error problem response
+// carrying the request ID, so support requests can be matched to the log entry.
+//
+// Special Cases:
+//   - Client disconnects (broken pipe, connection reset) are logged at Warn without a stack and nothing is written
+//   - http.ErrAbortHandler is re-panicked so net/http aborts the response silently
+//   - Handlers that set handlers.RepanicKey get the panic re-raised after logging, for tests
+//
+// Parameters:
+//   - logger: Logger for recovered panics
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function compatible with Gin router
+func recoveryMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			attrs := []any{
+				slog.String("request_id", handlers.RequestID(c)),   // Correlates with the response body
+				slog.String("method", c.Request.Method),            // HTTP method
+				slog.String("route", c.FullPath()),                 // Matched route pattern, empty if unrouted
+				slog.String("path", c.Request.URL.Path),            // Actual request path
+				slog.String("user", c.GetString(userIDContextKey)), // Authenticated user, if any
+				slog.String("client_ip", c.ClientIP()),             // Client identification
+			}
+
+			if brokenConnection(recovered) {
+				// The client is gone; there is nobody to answer and no bug to chase
+				logger.Warn("⚠️ Client connection lost while writing response",
+					append(attrs, slog.Any("error", recovered))...,
+				)
+				c.Abort()
+				return
+			}
+
+			handlers.RecordPanic()
+			logger.Error("💥 Panic recovered in HTTP handler",
+				append(attrs,
+					slog.String("panic", fmt.Sprint(recovered)), // Panic value
+					slog.String("stack", string(debug.Stack())), // Goroutine stack at the panic
+				)...,
+			)
+
+			if c.GetBool(handlers.RepanicKey) {
+				panic(recovered)
+			}
+
+			if c.Writer.Written() {
+				// Headers are already sent; the status cannot change any more
+				c.Abort()
+				return
+			}
+			if buffered, ok := c.Writer.(bufferedWriter); ok {
+				buffered.discardBuffered() // The partial body must not precede the problem response
+			}
+			handlers.WriteError(c, apperror.Internal(fmt.Errorf("panic: %v", recovered)))
+		}()
+		c.Next()
+	}
+}
+
+// bufferedWriter is a response writer holding body bytes that have not been
+// sent yet, such as compressWriter before it decided on compression.
+type bufferedWriter interface {
+	discardBuffered()
+}
+
+// brokenConnection reports whether a panic value is a write error caused by
+// the client closing the connection.
+func brokenConnection(recovered any) bool {
+	err, ok := recovered.(error)
+	if !ok {
+		return false
+	}
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
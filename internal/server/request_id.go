@@ -0,0 +1,54 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+)
+
+// maxRequestIDLength bounds client-supplied request IDs kept in logs and responses.
+const maxRequestIDLength = 128
+
+// requestIDMiddleware assigns every request an ID, stored in the Gin context
+// under handlers.RequestIDKey and returned in the X-Request-ID response header.
+//
+// An X-Request-ID sent by the client or an upstream proxy is reused so one ID
+// follows the request across services; IDs that are too long or contain
+// characters other than letters, digits, '-', '_', '.' and ':' are replaced
+// to keep log output clean.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(handlers.RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Set(handlers.RequestIDKey, id)
+		c.Header(handlers.RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// validRequestID reports whether a client-supplied request ID can be used as is.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns 128 random bits in hex.
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:]) // crypto/rand.Read never fails on supported platforms
+	return hex.EncodeToString(b[:])
+}
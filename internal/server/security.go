@@ -0,0 +1,248 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apperror"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+)
+
+// cspNoncePlaceholder is replaced with the request's nonce in the configured policy.
+const cspNoncePlaceholder = "{nonce}"
+
+// cspReportGroup names the Reporting API endpoint referenced by report-to.
+const cspReportGroup = "csp-endpoint"
+
+// Limits on violation reports, which any browser can send.
+const (
+	maxCSPReportBytes = 64 << 10 // Larger report bodies are rejected
+	maxCSPReports     = 20       // Reports logged per request; the rest are counted only
+)
+
+// errMissingCSPReport rejects JSON bodies without a csp-report object.
+var errMissingCSPReport = errors.New(`missing "csp-report" object`)
+
+// securityHeadersMiddleware creates a Gin middleware setting browser security
+// headers on every response. The headers are set before the handler runs, so
+// error responses carry them too.
+//
+// Headers:
+//   - Content-Security-Policy (or -Report-Only), with a fresh nonce per request
+//   - Strict-Transport-Security, only on requests received over TLS
+//   - X-Content-Type-Options: nosniff
+//   - Referrer-Policy, Permissions-Policy and X-Frame-Options as configured
+//   - Reporting-Endpoints, naming the violation report endpoint for report-to
+//
+// Parameters:
+//   - cfg: Security configuration from config.Server
+//
+// Returns:
+//   - gin.HandlerFunc: Middleware function compatible with Gin router
+func securityHeadersMiddleware(cfg config.ServerSecurity) gin.HandlerFunc {
+	cspHeader := "Content-Security-Policy"
+	if cfg.CSPReportOnly {
+		cspHeader = "Content-Security-Policy-Report-Only"
+	}
+	policy := strings.TrimRight(strings.TrimSpace(cfg.CSP), ";")
+	reporting := ""
+	if policy != "" && cfg.CSPReportPath != "" {
+		policy += "; report-uri " + cfg.CSPReportPath + "; report-to " + cspReportGroup
+		reporting = cspReportGroup + `="` + cfg.CSPReportPath + `"`
+	}
+	withNonce := strings.Contains(policy, cspNoncePlaceholder)
+	hsts := hstsValue(cfg)
+
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		if cfg.ReferrerPolicy != "" {
+			h.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		if cfg.PermissionsPolicy != "" {
+			h.Set("Permissions-Policy", cfg.PermissionsPolicy)
+		}
+		if cfg.FrameOptions != "" {
+			h.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		// Browsers ignore HSTS received over plain HTTP; sending it there only misleads
+		if hsts != "" && c.Request.TLS != nil {
+			h.Set("Strict-Transport-Security", hsts)
+		}
+		if policy != "" {
+			value := policy
+			if withNonce {
+				nonce := newCSPNonce()
+				c.Set(handlers.CSPNonceKey, nonce)
+				value = strings.ReplaceAll(policy, cspNoncePlaceholder, nonce)
+			}
+			h.Set(cspHeader, value)
+			if reporting != "" {
+				h.Set("Reporting-Endpoints", reporting)
+			}
+		}
+		c.Next()
+	}
+}
+
+// hstsValue builds the Strict-Transport-Security header, or "" when disabled.
+func hstsValue(cfg config.ServerSecurity) string {
+	if cfg.HSTSMaxAge <= 0 {
+		return ""
+	}
+	value := "max-age=" + strconv.Itoa(cfg.HSTSMaxAge)
+	if cfg.HSTSIncludeSubdomains {
+		value += "; includeSubDomains"
+	}
+	if cfg.HSTSPreload {
+		value += "; preload"
+	}
+	return value
+}
+
+// newCSPNonce returns 128 random bits in base64url, one of the encodings CSP nonces allow.
+func newCSPNonce() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])                            // crypto/rand.Read never fails on supported platforms
+	return base64.RawURLEncoding.EncodeToString(b[:]) // URL-safe, so HTML attributes need no escaping
+}
+
+// cspViolation is a Content Security Policy violation report, in the common
+// form of the legacy report-uri and the Reporting API formats.
+type cspViolation struct {
+	DocumentURL        string
+	EffectiveDirective string
+	BlockedURL         string
+	Disposition        string // "enforce" or "report"
+	SourceFile         string
+	LineNumber         int
+}
+
+// legacyCSPReport is the body browsers POST to report-uri as application/csp-report.
+type legacyCSPReport struct {
+	Report struct {
+		DocumentURI        string `json:"document-uri"`
+		ViolatedDirective  string `json:"violated-directive"`
+		EffectiveDirective string `json:"effective-directive"`
+		BlockedURI         string `json:"blocked-uri"`
+		Disposition        string `json:"disposition"`
+		SourceFile         string `json:"source-file"`
+		LineNumber         int    `json:"line-number"`
+	} `json:"csp-report"`
+}
+
+// reportingAPIReport is one entry of the application/reports+json array sent to report-to.
+type reportingAPIReport struct {
+	Type string `json:"type"`
+	Body struct {
+		DocumentURL        string `json:"documentURL"`
+		EffectiveDirective string `json:"effectiveDirective"`
+		BlockedURL         string `json:"blockedURL"`
+		Disposition        string `json:"disposition"`
+		SourceFile         string `json:"sourceFile"`
+		LineNumber         int    `json:"lineNumber"`
+	} `json:"body"`
+}
+
+// handleCSPReport logs Content Security Policy violations reported by
+// browsers. It accepts both the legacy report-uri format
+// (application/csp-report) and the Reporting API format
+// (application/reports+json), answering 204.
+//
+// Responses:
+//   - 204 No Content: Reports logged
+//   - 400 bad_request: Body is not a violation report
+func (s *Server) handleCSPReport(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCSPReportBytes+1))
+	if err != nil {
+		handlers.WriteError(c, err)
+		return
+	}
+	if len(body) > maxCSPReportBytes {
+		handlers.WriteError(c, apperror.PayloadTooLarge(maxCSPReportBytes))
+		return
+	}
+
+	violations, err := parseCSPReports(c.GetHeader("Content-Type"), body)
+	if err != nil {
+		handlers.WriteError(c, apperror.BadRequest("body is not a CSP violation report").Wrap(err))
+		return
+	}
+	for i, v := range violations {
+		if i == maxCSPReports {
+			s.logger.Warn("🛡️ CSP violation reports truncated",
+				slog.String("request_id", handlers.RequestID(c)),   // Correlates with the logged reports
+				slog.Int("dropped", len(violations)-maxCSPReports), // Reports not logged
+			)
+			break
+		}
+		s.logger.Warn("🛡️ CSP violation reported",
+			slog.String("request_id", handlers.RequestID(c)), // Report request, not the violating page load
+			slog.String("document", v.DocumentURL),           // Page where the violation happened
+			slog.String("directive", v.EffectiveDirective),   // Directive that was violated
+			slog.String("blocked", v.BlockedURL),             // Resource that was blocked, or "inline"/"eval"
+			slog.String("disposition", v.Disposition),        // "report" in report-only mode
+			slog.String("source", v.SourceFile),              // Script that caused the violation, if known
+			slog.Int("line", v.LineNumber),                   // Line in source, if known
+			slog.String("user_agent", c.Request.UserAgent()), // Browser sending the report
+		)
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// parseCSPReports decodes a violation report body according to its content type.
+func parseCSPReports(contentType string, body []byte) ([]cspViolation, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/reports+json" {
+		var reports []reportingAPIReport
+		if err := json.Unmarshal(body, &reports); err != nil {
+			return nil, err
+		}
+		violations := make([]cspViolation, 0, len(reports))
+		for _, r := range reports {
+			if r.Type != "csp-violation" {
+				continue // Other report types may share the endpoint
+			}
+			violations = append(violations, cspViolation{
+				DocumentURL:        r.Body.DocumentURL,
+				EffectiveDirective: r.Body.EffectiveDirective,
+				BlockedURL:         r.Body.BlockedURL,
+				Disposition:        r.Body.Disposition,
+				SourceFile:         r.Body.SourceFile,
+				LineNumber:         r.Body.LineNumber,
+			})
+		}
+		return violations, nil
+	}
+
+	var legacy legacyCSPReport
+	if err := json.Unmarshal(body, &legacy); err != nil {
+		return nil, err
+	}
+	r := legacy.Report
+	if r.DocumentURI == "" {
+		return nil, errMissingCSPReport
+	}
+	directive := r.EffectiveDirective
+	if directive == "" {
+		directive = r.ViolatedDirective // Older browsers send only the violated directive
+	}
+	return []cspViolation{{
+		DocumentURL:        r.DocumentURI,
+		EffectiveDirective: directive,
+		BlockedURL:         r.BlockedURI,
+		Disposition:        r.Disposition,
+		SourceFile:         r.SourceFile,
+		LineNumber:         r.LineNumber,
+	}}, nil
+}
@@ -2,25 +2,68 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
 	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/idempotency"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/lifecycle"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/loadshed"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/openapi"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/ratelimit"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/restart"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/routes"
 )
 
+// ErrAlreadyStarted is returned by Start when the server has already been started.
+var ErrAlreadyStarted = errors.New("server already started")
+
 // Server represents the HTTP server instance.
 //
 // This struct encapsulates the Gin router, HTTP server, configuration,
 // and logger for a complete web server implementation.
+//
+// Lifecycle:
+//   - Start binds the listeners synchronously and serves in the background
+//   - Ready is closed once the listeners are bound and Addr reports the bound address
+//   - Done is closed when serving stops; Err then reports why
+//   - Drain flips /ready to 503 ahead of Shutdown so load balancers stop routing here
 type Server struct {
 	router *gin.Engine   // Gin HTTP router
 	server *http.Server  // Standard library HTTP server
 	config config.Server // Server configuration
 	logger *slog.Logger  // Structured logger instance
+
+	mu        sync.Mutex     // Guards listeners, bound and serveErr
+	listeners []net.Listener // Public API listeners, primary first; nil until Start succeeds
+	bound     boundListeners // All bound listeners, handed over by ListenerFiles
+	redirect  *http.Server   // Plain-HTTP to HTTPS redirect server, nil unless configured
+	admin     *http.Server   // Admin listener server, nil unless AdminPort is set or a socket is inherited
+
+	adminRouter *gin.Engine      // Admin router, built by NewServer when AdminPort is set or by Start for an inherited socket
+	routes      *routes.Registry // Every registered public and admin route
+
+	configDump  *config.Config                      // Configuration served by the admin /config endpoint
+	limiter     atomic.Pointer[ratelimit.Limiter]   // Rate limiter, replaced by SetRateLimitStore
+	idempotency atomic.Pointer[idempotency.Manager] // Idempotency-Key records, replaced by SetIdempotencyStore
+	shedder     *loadshed.Limiter                   // Adaptive concurrency limit, nil unless load shedding is enabled
+	draining    atomic.Bool                         // Set by Drain; /ready then reports 503
+	inFlight    atomic.Int64                        // Public API requests being served
+	workers     *lifecycle.Workers                  // Background workers stopped after Shutdown
+	api         *openapi.Document                   // OpenAPI description of the public routes
+	apiJSON     func() ([]byte, error)              // Rendered api, cached on first use
+	negotiated  map[string]*negotiatedRoute         // Unversioned /api routes by "METHOD /path", under header negotiation
+	serveErr    error                               // Error that stopped serving, nil after a graceful shutdown
+	ready       chan struct{}                       // Closed once the listeners are bound
+	done        chan struct{}                       // Closed once serving has stopped
 }
 
 // NewServer creates a new HTTP server instance with Gin router.
@@ -46,12 +89,25 @@ func NewServer(cfg config.Server, logger *slog.Logger) *Server {
 	// Create a new Gin router instance without any default middleware
 	// gin.New() creates a bare router, unlike gin.Default() which includes logger and recovery
 	router := gin.New()
+	trustProxies(router, cfg.TrustedProxies, logger)
 
 	// Add custom middleware stack in order of execution:
-	// 1. Custom slog-based logging middleware for structured logging
+	// 1. Request ID assignment so every later log entry and error response carries it
+	router.Use(requestIDMiddleware())
+	// 2. Security headers; set before anything can respond so error responses carry them too
+	if cfg.Security.Enabled {
+		router.Use(securityHeadersMiddleware(cfg.Security))
+	}
+	// 3. Custom slog-based logging middleware for structured logging
 	router.Use(ginLoggerMiddleware(logger))
-	// 2. Recovery middleware to handle panics gracefully and return 500 errors
-	router.Use(gin.Recovery())
+	// 4. Response compression; outside recovery so error responses are compressed too
+	if cfg.Compression.Enabled {
+		router.Use(compressionMiddleware(cfg.Compression, logger))
+	}
+	// 5. Recovery middleware to log panics with context and return problem+json 500 errors
+	router.Use(recoveryMiddleware(logger))
+	// 6. CORS for browser front-ends; answers preflight requests before routing
+	router.Use(corsMiddleware(cfg.CORS, logger))
 
 	// Create the underlying HTTP server with configuration-driven timeouts
 	// These timeouts prevent resource exhaustion from slow or malicious clients
@@ -67,6 +123,16 @@ func NewServer(cfg config.Server, logger *slog.Logger) *Server {
 		// WriteTimeout: Maximum duration before timing out writes of the response
 		// Prevents slow clients from causing goroutine/memory leaks
 		WriteTimeout: time.Duration(cfg.WriteTimeout) * time.Second,
+
+		// ReadHeaderTimeout: Maximum duration for reading request headers only
+		// Stops slow-header (Slowloris) clients without limiting body uploads
+		ReadHeaderTimeout: time.Duration(cfg.ReadHeaderTimeout) * time.Second,
+
+		// IdleTimeout: Maximum time a keep-alive connection waits for the next request
+		IdleTimeout: time.Duration(cfg.IdleTimeout) * time.Second,
+
+		// MaxHeaderBytes: Upper bound on request header size; larger requests get 431
+		MaxHeaderBytes: cfg.MaxHeaderBytes,
 	}
 
 	// Create the Server struct instance with all necessary components
@@ -76,61 +142,489 @@ func NewServer(cfg config.Server, logger *slog.Logger) *Server {
 		server: httpServer, // Standard library HTTP server with timeouts
 		config: cfg,        // Configuration settings for server behavior
 		logger: logger,     // Structured logger for debugging and monitoring
+		ready:  make(chan struct{}),
+		done:   make(chan struct{}),
+
+		workers: lifecycle.NewWorkers(logger), // Background work tracked for shutdown
+		api:     openapi.New(apiInfo),         // Filled by setupRoutes
+		routes:  routes.NewRegistry(),         // Filled by setupRoutes and newAdminRouter
+
+		negotiated: make(map[string]*negotiatedRoute),
+	}
+	server.apiJSON = sync.OnceValues(server.renderOpenAPI)
+
+	// 7. In-flight request counting for drain logging
+	router.Use(server.inFlightMiddleware())
+	// 8. Adaptive load shedding; before any per-request work, so excess load is turned away cheaply
+	if cfg.LoadShedding.Enabled {
+		server.shedder = newLoadShedder(cfg.LoadShedding)
+		router.Use(server.loadShedMiddleware())
+	}
+	// 9. Per-client rate limiting; after CORS so preflight requests never consume tokens
+	if cfg.RateLimit.Enabled {
+		server.SetRateLimitStore(ratelimit.NewMemoryStore())
+		router.Use(server.rateLimitMiddleware())
+	}
+	// 10. Body size caps and handler deadlines; after rate limiting so rejected requests cost nothing
+	router.Use(limitsMiddleware(cfg.Limits, logger))
+	// 11. Idempotency-Key replay; after the limits so the fingerprinted body is capped
+	if cfg.Idempotency.Enabled {
+		server.SetIdempotencyStore(idempotency.NewMemoryStore())
+		router.Use(server.idempotencyMiddleware())
 	}
 
 	// Initialize all HTTP routes and their handlers
 	// This must be called after the router is created but before starting the server
 	server.setupRoutes()
+	if cfg.AdminPort > 0 {
+		server.adminRouter = server.newAdminRouter()
+	}
 
 	// Log successful server creation with key configuration details
 	// This helps with debugging and verifying correct configuration
 	logger.Debug("🚀 HTTP server created",
-		slog.String("addr", httpServer.Addr),        // Network address (host:port)
-		slog.Int("read_timeout", cfg.ReadTimeout),   // Read timeout in seconds
-		slog.Int("write_timeout", cfg.WriteTimeout), // Write timeout in seconds
+		slog.String("addr", httpServer.Addr),                // Network address (host:port)
+		slog.Int("read_timeout", cfg.ReadTimeout),           // Read timeout in seconds
+		slog.Int("write_timeout", cfg.WriteTimeout),         // Write timeout in seconds
+		slog.Bool("tls", cfg.TLS.Enabled),                   // HTTPS termination in the application
+		slog.Bool("rate_limit", cfg.RateLimit.Enabled),      // Per-client request rate limiting
+		slog.Bool("compression", cfg.Compression.Enabled),   // Negotiated response compression
+		slog.Bool("security_headers", cfg.Security.Enabled), // CSP, HSTS and related headers
 	)
 
 	// Return the fully configured and ready-to-start server instance
 	return server
 }
 
-// Start starts the HTTP server in a non-blocking manner.
+// trustProxies restricts which peers router believes about the client IP.
 //
-// The server will start listening on the configured address and port.
-// This method is non-blocking and returns immediately.
+// Gin trusts X-Forwarded-For and X-Real-IP from every peer unless told
+// otherwise, which would let any client choose its own rate limit bucket and
+// logged address. Only the configured proxies are trusted; with none, the
+// client IP is always the peer address. Invalid entries are logged and all
+// proxies are distrusted, failing safe.
+func trustProxies(router *gin.Engine, proxies []string, logger *slog.Logger) {
+	if err := router.SetTrustedProxies(proxies); err != nil {
+		logger.Error("❌ Invalid trusted proxy; forwarding headers will be ignored",
+			slog.Any("trusted_proxies", proxies), // Configured addresses and ranges
+			slog.String("error", err.Error()),    // Entry that failed to parse
+		)
+		_ = router.SetTrustedProxies(nil)
+	}
+}
+
+// Start binds the listeners and starts serving HTTP requests in the background.
+//
+// The listeners are bound synchronously, so configuration problems such as a
+// port already in use or an invalid host are returned to the caller instead of
+// surfacing later in the logs. Once Start returns nil the server accepts
+// connections; failures while serving are reported through Done and Err.
+//
+// Listeners:
+//   - The primary listener binds Address, or Host:Port when Address is empty
+//   - Each entry of Listeners adds a listener served by the same router
+//   - Sockets handed over by a graceful restart or passed by systemd socket activation replace all of the above
+//
+// A Port of 0 binds an ephemeral port chosen by the operating system; use Addr
+// to find out which one.
 //
 // Returns:
-//   - error: Any error that occurred during server startup
+//   - error: Bind error, or ErrAlreadyStarted if Start was called before
 //
 // Example:
 //
 //	if err := server.Start(); err != nil {
-//	    log.Fatal("Failed to start server:", err)
+//	    return fmt.Errorf("failed to start server: %w", err)
 //	}
+//	slog.Info("listening", slog.String("addr", server.Addr()))
 func (s *Server) Start() error {
+	s.mu.Lock()
+	if s.listeners != nil {
+		s.mu.Unlock()
+		return ErrAlreadyStarted
+	}
+
 	// Log server startup with the network address for debugging and monitoring
 	s.logger.Info("🚀 Starting HTTP server",
-		slog.String("addr", s.server.Addr), // Shows exactly where the server will listen
+		slog.String("addr", s.primaryAddress()),              // Configured address; the bound addresses are logged below
+		slog.Int("extra_listeners", len(s.config.Listeners)), // Additional configured listeners
+	)
+
+	// Build the TLS configuration first so missing or invalid certificates fail startup
+	tlsEnabled := s.config.TLS.Enabled
+	if tlsEnabled {
+		tlsConfig, err := newTLSConfig(s.config.TLS, s.config.Host, s.logger)
+		if err != nil {
+			s.mu.Unlock()
+			s.logger.Error("❌ Invalid TLS configuration",
+				slog.String("error", err.Error()), // Which setting or file is wrong
+			)
+			return fmt.Errorf("invalid TLS configuration: %w", err)
+		}
+		s.server.TLSConfig = tlsConfig
+		if s.config.TLS.SelfSigned {
+			s.logger.Warn("🔐 Using a self-signed TLS certificate - for development only")
+		}
+	}
+
+	// Bind the listeners before returning so bind errors reach the caller
+	bound, err := s.bindListeners()
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	listeners := bound.public
+
+	// Bind the optional HTTP-to-HTTPS redirect listener alongside the main one,
+	// unless it was inherited
+	if !tlsEnabled || s.config.TLS.RedirectPort <= 0 {
+		closeListeners(bound.redirect) // Inherited, but redirects are no longer configured
+		bound.redirect = nil
+	} else if bound.redirect == nil {
+		redirectAddr := fmt.Sprintf("%s:%d", s.config.Host, s.config.TLS.RedirectPort)
+		bound.redirect, err = net.Listen("tcp", redirectAddr)
+		if err != nil {
+			closeListeners(append(listeners, bound.admin)...)
+			s.mu.Unlock()
+			s.logger.Error("❌ HTTPS redirect listener failed to bind",
+				slog.String("addr", redirectAddr),
+				slog.String("error", err.Error()),
+			)
+			return fmt.Errorf("failed to listen on %s: %w", redirectAddr, err)
+		}
+	}
+	if bound.redirect != nil {
+		s.redirect = &http.Server{
+			Handler:           httpsRedirectHandler(s.httpsPort(listeners)),
+			ReadTimeout:       s.server.ReadTimeout,
+			ReadHeaderTimeout: s.server.ReadHeaderTimeout, // Falls back to ReadTimeout when 0
+			IdleTimeout:       s.server.IdleTimeout,
+		}
+	}
+	// Bind the admin listener unless it was inherited; it shares the server
+	// lifecycle but not its router or middleware
+	if bound.admin == nil && s.config.AdminPort > 0 {
+		adminAddr := fmt.Sprintf("%s:%d", s.config.AdminHost, s.config.AdminPort)
+		bound.admin, err = net.Listen("tcp", adminAddr)
+		if err != nil {
+			closeListeners(append(listeners, bound.redirect)...)
+			s.mu.Unlock()
+			s.logger.Error("❌ Admin listener failed to bind",
+				slog.String("addr", adminAddr),
+				slog.String("error", err.Error()),
+			)
+			return fmt.Errorf("failed to listen on %s: %w", adminAddr, err)
+		}
+	}
+	if bound.admin != nil {
+		if s.adminRouter == nil {
+			s.adminRouter = s.newAdminRouter() // Admin socket inherited without AdminPort configured
+		}
+		s.admin = &http.Server{
+			Handler:           s.adminRouter,
+			ReadTimeout:       s.server.ReadTimeout,
+			ReadHeaderTimeout: s.server.ReadHeaderTimeout,
+			IdleTimeout:       s.server.IdleTimeout,
+			MaxHeaderBytes:    s.server.MaxHeaderBytes,
+			WriteTimeout:      0, // CPU profiles and traces stream for a caller-chosen duration
+		}
+	}
+	s.listeners = listeners
+	s.bound = bound
+	s.mu.Unlock()
+	close(s.ready)
+
+	// Log the routing table once, so the startup log shows exactly what is served
+	listed := s.routes.Routes(routes.ListenerPublic)
+	if bound.admin != nil {
+		listed = s.routes.Routes("")
+	}
+	endpoints := make([]string, 0, len(listed))
+	for _, r := range listed {
+		endpoints = append(endpoints, r.Listener+" "+r.String())
+	}
+	s.logger.Info("🛤️ Routes registered",
+		slog.Int("route_count", len(listed)), // Public routes, plus admin routes when the admin listener is up
+		slog.Any("routes", endpoints),        // "listener METHOD /path" entries
 	)
 
-	// Start the HTTP server in a separate goroutine to make this method non-blocking
-	// This allows the calling code to continue execution while the server runs
+	for _, l := range listeners {
+		s.logger.Info("🚀 HTTP server listening",
+			slog.String("addr", listenerAddress(l)), // Actual bound address (resolves port 0)
+			slog.Bool("tls", tlsEnabled),            // HTTPS with HTTP/2 when true
+		)
+	}
+
+	if bound.redirect != nil {
+		s.logger.Info("🔐 Redirecting plain HTTP to HTTPS",
+			slog.String("addr", bound.redirect.Addr().String()),
+		)
+		go func() {
+			// Redirect failures are logged only; the HTTPS listener keeps serving
+			if err := s.redirect.Serve(bound.redirect); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.logger.Error("❌ HTTPS redirect listener stopped",
+					slog.String("error", err.Error()),
+				)
+			}
+		}()
+	}
+
+	if bound.admin != nil {
+		s.logger.Info("🛠️ Admin listener started",
+			slog.String("addr", listenerAddress(bound.admin)),
+			slog.Bool("token_auth", s.config.AdminToken != ""), // Whether requests need the admin token
+		)
+		go func() {
+			// Admin failures are logged only; the public listener keeps serving
+			if err := s.admin.Serve(bound.admin); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.logger.Error("❌ Admin listener stopped",
+					slog.String("error", err.Error()),
+				)
+			}
+		}()
+	}
+
+	// Serve each listener in a separate goroutine to make this method non-blocking
+	// Serve blocks until the server is shut down or the listener fails
+	var wg sync.WaitGroup
+	var stopOnce sync.Once
+	stopped := func() { stopOnce.Do(func() { close(s.done) }) }
+	for _, l := range listeners {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if tlsEnabled {
+				// ServeTLS enables HTTP/2 via ALPN; certificates come from TLSConfig
+				err = s.server.ServeTLS(l, "", "")
+			} else {
+				err = s.server.Serve(l)
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				// Only record errors that aren't from normal server shutdown
+				// http.ErrServerClosed is returned when Shutdown() is called, which is expected
+				s.logger.Error("❌ HTTP server stopped serving",
+					slog.String("addr", listenerAddress(l)), // Listener that failed
+					slog.String("error", err.Error()),       // Detailed error message for debugging
+				)
+				s.mu.Lock()
+				if s.serveErr == nil {
+					s.serveErr = err
+				}
+				s.mu.Unlock()
+				// A failed listener means the server no longer serves as configured
+				stopped()
+			}
+		}()
+	}
 	go func() {
-		// Attempt to start the server and listen for incoming connections
-		// ListenAndServe blocks until the server is shut down or encounters an error
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			// Only log errors that aren't from normal server shutdown
-			// http.ErrServerClosed is returned when Shutdown() is called, which is expected
-			s.logger.Error("❌ HTTP server failed to start",
+		wg.Wait()
+		stopped()
+	}()
+
+	return nil
+}
+
+// primaryAddress returns the configured address of the primary listener.
+func (s *Server) primaryAddress() string {
+	if s.config.Address != "" {
+		return s.config.Address
+	}
+	return s.server.Addr
+}
+
+// boundListeners are the listeners of a started server.
+type boundListeners struct {
+	public   []net.Listener // Public API listeners, primary first
+	admin    net.Listener   // Admin listener, nil if disabled
+	redirect net.Listener   // HTTP-to-HTTPS redirect listener, nil if disabled
+}
+
+// bindListeners binds the public API listeners, primary first, or takes them
+// over from a graceful restart or systemd socket activation. Inherited
+// sockets named "admin" or "redirect" are returned as the admin and redirect
+// listeners. On error nothing is left bound.
+func (s *Server) bindListeners() (boundListeners, error) {
+	var bound boundListeners
+	inherited, err := inheritedListeners(s.config.SocketActivation)
+	if err != nil {
+		s.logger.Error("❌ Inherited sockets unusable",
+			slog.String("error", err.Error()), // Which descriptor could not be used
+		)
+		return bound, err
+	}
+	for _, nl := range inherited {
+		switch {
+		case nl.name == adminSocketName && bound.admin == nil:
+			bound.admin = nl.listener
+		case nl.name == redirectSocketName && bound.redirect == nil:
+			bound.redirect = nl.listener
+		default:
+			bound.public = append(bound.public, nl.listener)
+		}
+	}
+	if len(inherited) > 0 {
+		s.logger.Info("🧦 Using inherited sockets",
+			slog.Int("public", len(bound.public)),        // Sockets serving the public API
+			slog.Bool("admin", bound.admin != nil),       // Whether a socket named "admin" was passed
+			slog.Bool("redirect", bound.redirect != nil), // Whether a socket named "redirect" was passed
+		)
+	}
+	if len(bound.public) > 0 {
+		return bound, nil
+	}
+
+	specs := append([]config.ServerListener{{Address: s.primaryAddress()}}, s.config.Listeners...)
+	for _, spec := range specs {
+		if spec.SocketMode == "" {
+			spec.SocketMode = s.config.SocketMode
+		}
+		if spec.SocketGroup == "" {
+			spec.SocketGroup = s.config.SocketGroup
+		}
+		l, err := listen(spec)
+		if err != nil {
+			closeListeners(append(bound.public, bound.admin, bound.redirect)...)
+			s.logger.Error("❌ HTTP server failed to bind",
+				slog.String("addr", spec.Address), // Address that could not be bound
 				slog.String("error", err.Error()), // Detailed error message for debugging
 			)
+			return boundListeners{}, fmt.Errorf("failed to listen on %s: %w", spec.Address, err)
 		}
-		// If we reach here, the server has stopped (either gracefully or due to error)
+		bound.public = append(bound.public, l)
+	}
+	return bound, nil
+}
+
+// ListenerFiles returns duplicates of all bound listening sockets, named by
+// role, for handing over to a new process with restart.Spawn. The caller
+// closes the files. Once called, Unix socket files stay on disk when this
+// server shuts down, since the new process keeps serving them.
+//
+// Example:
+//
+//	listeners, err := httpServer.ListenerFiles()
+//	pid, err := restart.Spawn(ctx, listeners)
+func (s *Server) ListenerFiles() ([]restart.Listener, error) {
+	s.mu.Lock()
+	bound := s.bound
+	s.mu.Unlock()
+	if len(bound.public) == 0 {
+		return nil, errors.New("server not started")
+	}
+
+	var files []restart.Listener
+	add := func(name string, l net.Listener) error {
+		f, err := listenerFile(l)
+		if err != nil {
+			return err
+		}
+		files = append(files, restart.Listener{Name: name, File: f})
+		return nil
+	}
+	err := func() error {
+		for _, l := range bound.public {
+			if err := add(publicSocketName, l); err != nil {
+				return err
+			}
+		}
+		if bound.admin != nil {
+			if err := add(adminSocketName, bound.admin); err != nil {
+				return err
+			}
+		}
+		if bound.redirect != nil {
+			return add(redirectSocketName, bound.redirect)
+		}
+		return nil
 	}()
+	if err != nil {
+		for _, f := range files {
+			f.File.Close()
+		}
+		return nil, err
+	}
+	return files, nil
+}
 
-	// Return immediately without waiting for the server to start listening
-	// The caller can use other mechanisms to verify the server is ready if needed
-	return nil
+// httpsPort returns the port HTTP requests are redirected to: the port of the
+// first TCP listener, or the configured Port when all listeners are Unix sockets.
+func (s *Server) httpsPort(listeners []net.Listener) int {
+	for _, l := range listeners {
+		if addr, ok := l.Addr().(*net.TCPAddr); ok {
+			return addr.Port
+		}
+	}
+	return s.config.Port
+}
+
+// Addr returns the address the primary listener is listening on.
+//
+// After a successful Start this is the actual bound address, including the
+// port chosen by the operating system when Port is 0. Before that it is the
+// configured address. Unix sockets are reported as "unix:///path".
+//
+// Example:
+//
+//	resp, err := http.Get("http://" + server.Addr() + "/health")
+func (s *Server) Addr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.listeners) > 0 {
+		return listenerAddress(s.listeners[0])
+	}
+	return s.primaryAddress()
+}
+
+// Addrs returns the bound addresses of all public API listeners, primary
+// first, or nil before Start.
+func (s *Server) Addrs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	addrs := make([]string, 0, len(s.listeners))
+	for _, l := range s.listeners {
+		addrs = append(addrs, listenerAddress(l))
+	}
+	if len(addrs) == 0 {
+		return nil
+	}
+	return addrs
+}
+
+// Router returns the Gin engine of the public listener so additional routes
+// can be registered before Start, e.g. test endpoints. Routes registered here
+// run behind the full middleware stack.
+func (s *Server) Router() *gin.Engine {
+	return s.router
+}
+
+// Ready returns a channel that is closed once the listener is bound and the
+// server accepts connections.
+func (s *Server) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Done returns a channel that is closed when a started server has stopped
+// serving, either after Shutdown or because serving failed. Check Err to tell them apart.
+//
+// Example:
+//
+//	select {
+//	case <-quit:
+//	    // Graceful shutdown requested
+//	case <-server.Done():
+//	    return fmt.Errorf("server stopped: %w", server.Err())
+//	}
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the error that stopped the server from serving. It is nil while
+// the server is running and after a graceful shutdown.
+func (s *Server) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.serveErr
 }
 
 // Shutdown gracefully shuts down the HTTP server.
@@ -153,7 +647,9 @@ func (s *Server) Start() error {
 //	}
 func (s *Server) Shutdown(ctx context.Context) error {
 	// Log the beginning of the graceful shutdown process
-	s.logger.Info("🛑 Shutting down HTTP server gracefully...")
+	s.logger.Info("🛑 Shutting down HTTP server gracefully...",
+		slog.Int64("in_flight", s.InFlight()), // Requests Shutdown waits for
+	)
 
 	// Attempt to gracefully shutdown the server within the provided context timeout
 	// This will:
@@ -161,6 +657,18 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	// 2. Close idle connections
 	// 3. Wait for active connections to finish their current requests
 	// 4. Return an error if the context timeout is exceeded
+	s.mu.Lock()
+	redirect := s.redirect
+	s.mu.Unlock()
+	if redirect != nil {
+		// The redirect listener only answers with redirects; stop it first
+		if err := redirect.Shutdown(ctx); err != nil {
+			s.logger.Warn("⚠️ Error shutting down HTTPS redirect listener",
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
 	if err := s.server.Shutdown(ctx); err != nil {
 		// Log any errors that occur during shutdown (e.g., timeout, force close needed)
 		s.logger.Error("❌ Error during server shutdown",
@@ -170,6 +678,18 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		return err
 	}
 
+	// Stop the admin listener last so health and metrics stay observable while requests drain
+	s.mu.Lock()
+	admin := s.admin
+	s.mu.Unlock()
+	if admin != nil {
+		if err := admin.Shutdown(ctx); err != nil {
+			s.logger.Warn("⚠️ Error shutting down admin listener",
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+
 	// Log successful completion of graceful shutdown
 	s.logger.Info("✅ HTTP server shutdown completed")
 
@@ -193,42 +713,139 @@ func (s *Server) Shutdown(ctx context.Context) error {
 // Endpoints:
 //   - GET /: Root endpoint with API information and service details
 //   - GET /health: Health check for load balancers and monitoring
+//   - GET /ready: Readiness check; 503 once draining has started
 //   - GET /metrics: Application metrics for observability platforms
+//   - POST /csp-report: Content Security Policy violation reports, when reporting is configured
+//   - GET /openapi.json: OpenAPI 3.1 description of these endpoints
+//   - GET /docs: Interactive API explorer for the OpenAPI description
+//   - GET /api/v1/: API information in the original root endpoint format
+//   - GET /api/v2/: API information listing the versioned routes
+//   - GET /api/: The above in the version named by the Accept header, with server.api.negotiation "header"
+//
+// Versioned API routes live in /api/v1 and /api/v2 route groups registered
+// through apiGroup.handle (HandleVersioned outside this package), which
+// declares deprecations at registration time.
+// Operational endpoints stay at the root so probes, scrapers and browsers
+// need no version.
+//
+// Every route is registered through handle with its OpenAPI description, so
+// the published document cannot drift from the routing table.
 //
 // Security Considerations:
-//   - All endpoints are read-only (GET methods only)
+//   - All endpoints except the violation report endpoint are read-only
 //   - No sensitive information exposed in responses
-//   - Rate limiting should be applied at reverse proxy level
+//   - Per-client rate limiting is applied by rateLimitMiddleware when enabled
 func (s *Server) setupRoutes() {
 	// Create a handler instance with the logger dependency
 	// This centralizes all HTTP handler dependencies in one place
 	h := handlers.NewHandler(s.logger)
+	h.SetRoutes(s.routes)       // The root endpoint lists the routes registered below
+	h.SetLoadShedder(s.shedder) // Reported by the metrics endpoint
 
 	// Root endpoint - provides basic API information and version details
 	// Used for: API discovery, version checking, basic connectivity tests
 	// Expected response time: <50ms
 	// Dependencies: None (static response)
-	s.router.GET("/", h.HandleRoot)
+	s.handle(http.MethodGet, "/", openapi.Operation{
+		Summary:   "API information",
+		Tags:      []string{"Service"},
+		Responses: map[int]openapi.Response{http.StatusOK: {Description: "Service name, version and endpoints", Content: openapi.JSON(handlers.RootResponse{})}},
+	}, h.HandleRoot)
 
 	// Health check endpoint - indicates if the service is operational
 	// Used by: Load balancers, monitoring systems, container orchestrators
 	// Expected response time: <100ms
 	// Dependencies: Should not depend on external services for basic health
 	// Note: Complex health checks (DB connectivity) should be separate endpoint
-	s.router.GET("/health", h.HandleHealth)
+	s.handle(http.MethodGet, "/health", openapi.Operation{
+		Summary:     "Liveness check",
+		Description: "Always 200 while the process serves requests. Use /ready to decide whether to route traffic here.",
+		Tags:        []string{"Operations"},
+		Responses:   map[int]openapi.Response{http.StatusOK: {Description: "Process is alive", Content: openapi.JSON(handlers.HealthResponse{})}},
+	}, h.HandleHealth)
+
+	// Readiness endpoint - tells load balancers whether to route traffic here
+	// Used by: Load balancer target health checks, Kubernetes readiness probes
+	// Expected response time: <10ms
+	// Dependencies: None; fails only while the server drains before shutdown
+	s.handle(http.MethodGet, "/ready", openapi.Operation{
+		Summary: "Readiness check",
+		Tags:    []string{"Operations"},
+		Responses: map[int]openapi.Response{
+			http.StatusOK:                 {Description: "Instance accepts traffic", Content: openapi.JSON(readyResponse{})},
+			http.StatusServiceUnavailable: openapi.ProblemResponse("Instance is draining before shutdown"),
+		},
+	}, s.handleReady)
 
 	// Metrics endpoint - exposes application performance and usage statistics
 	// Used by: Monitoring systems (Prometheus, Grafana), observability platforms
 	// Expected response time: <200ms
 	// Dependencies: Internal metrics collection only
 	// TODO: Consider implementing Prometheus-compatible format (/metrics with text/plain)
-	s.router.GET("/metrics", h.HandleMetrics)
+	s.handle(http.MethodGet, "/metrics", openapi.Operation{
+		Summary:   "Runtime and application metrics",
+		Tags:      []string{"Operations"},
+		Responses: map[int]openapi.Response{http.StatusOK: {Description: "Metrics grouped by area; the set of fields may grow", Content: openapi.JSON(gin.H{})}},
+	}, h.HandleMetrics)
+
+	// CSP violation report endpoint - receives reports browsers send for the policy
+	// Used by: Browsers enforcing or trialling the Content-Security-Policy
+	// Dependencies: None; reports are written to the log
+	if sec := s.config.Security; sec.Enabled && sec.CSP != "" && sec.CSPReportPath != "" {
+		s.handle(http.MethodPost, sec.CSPReportPath, openapi.Operation{
+			Summary: "Report Content Security Policy violations",
+			Tags:    []string{"Security"},
+			Request: []openapi.Content{
+				{MediaType: "application/csp-report", Schema: legacyCSPReport{}},
+				{MediaType: "application/reports+json", Schema: []reportingAPIReport{}},
+			},
+			Responses: map[int]openapi.Response{
+				http.StatusNoContent:  {Description: "Reports logged"},
+				http.StatusBadRequest: openapi.ProblemResponse("Body is not a violation report"),
+			},
+		}, s.handleCSPReport)
+	}
+
+	// API description and explorer - generated from the routes registered above
+	// Used by: Client developers, code generators, API gateways
+	// Dependencies: None; the document is rendered once on first request
+	s.handle(http.MethodGet, "/openapi.json", openapi.Operation{
+		Summary:   "OpenAPI description of this API",
+		Tags:      []string{"Documentation"},
+		Responses: map[int]openapi.Response{http.StatusOK: {Description: "OpenAPI 3.1 document", Content: openapi.JSON(map[string]any{})}},
+	}, s.handleOpenAPI)
+	s.handle(http.MethodGet, "/docs", openapi.Operation{
+		Summary: "Interactive API explorer",
+		Tags:    []string{"Documentation"},
+		Responses: map[int]openapi.Response{http.StatusOK: {
+			Description: "HTML page rendering /openapi.json",
+			Content:     []openapi.Content{{MediaType: "text/html", Schema: ""}},
+		}},
+	}, s.handleAPIExplorer)
+
+	// Versioned API - one route group per version under /api
+	// Used by: API clients; they pick a version by path or, under header
+	// negotiation, by Accept header on the unversioned paths
+	// Dependencies: None; calls to deprecated routes are counted in /metrics
+	v1 := s.apiGroup("v1")
+	v1.handle(http.MethodGet, "/", openapi.Operation{
+		Summary:   "API information (v1)",
+		Tags:      []string{"Service"},
+		Responses: map[int]openapi.Response{http.StatusOK: {Description: "Service name, version and endpoints", Content: openapi.JSON(handlers.RootResponse{})}},
+	}, nil, h.HandleRoot)
+
+	v2 := s.apiGroup("v2")
+	v2.handle(http.MethodGet, "/", openapi.Operation{
+		Summary:   "API information",
+		Tags:      []string{"Service"},
+		Responses: map[int]openapi.Response{http.StatusOK: {Description: "Routes of every API version", Content: openapi.JSON(handlers.APIInfoResponse{})}},
+	}, nil, h.APIInfoHandler("v2"))
+
+	// Unknown paths and unsupported methods answer with problem+json like every other error
+	s.router.HandleMethodNotAllowed = true
+	s.router.NoRoute(h.HandleNoRoute)
+	s.router.NoMethod(h.HandleNoMethod)
 
-	// Log the completion of route setup for debugging and operational visibility
-	// This helps with troubleshooting startup issues and configuration verification
-	s.logger.Debug("🛤️  HTTP routes configured",
-		slog.Int("route_count", 3), // Track number of registered routes
-	)
 }
 
 // ginLoggerMiddleware creates a Gin middleware that logs HTTP requests using structured logging.
@@ -309,6 +926,7 @@ func ginLoggerMiddleware(logger *slog.Logger) gin.HandlerFunc {
 		// This creates a single log entry per request with all relevant information
 		// for debugging, monitoring, and security analysis
 		logger.Log(context.Background(), logLevel, "🌐 HTTP Request",
+			slog.String("request_id", handlers.RequestID(c)),     // Request ID for correlation with error responses
 			slog.String("method", method),                        // HTTP method for request classification
 			slog.String("path", path),                            // Full request path with query params
 			slog.Int("status", statusCode),                       // HTTP status code for response analysis
@@ -0,0 +1,178 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/audit"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/logger"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// startAdminServer starts a server with the admin listener on adminPort.
+func startAdminServer(t *testing.T, adminPort int, token string) *server.Server {
+	t.Helper()
+	cfg := config.Server{
+		Port:         0,
+		Host:         "127.0.0.1",
+		ReadTimeout:  30,
+		WriteTimeout: 30,
+		AdminPort:    adminPort,
+		AdminHost:    "127.0.0.1",
+		AdminToken:   token,
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	srv := server.NewServer(cfg, logger)
+	srv.SetConfigDump(&config.Config{
+		Server:   cfg,
+		Database: config.Database{Host: "db.internal", Password: "hunter2"},
+	})
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	})
+	return srv
+}
+
+// adminRequest sends a request to the admin listener and decodes the JSON response.
+func adminRequest(t *testing.T, method, url, token, body string) (int, map[string]interface{}) {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	var decoded map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&decoded)
+	return resp.StatusCode, decoded
+}
+
+// TestAdminListener_Endpoints tests the admin endpoints and their separation from the public router.
+func TestAdminListener_Endpoints(t *testing.T) {
+	srv := startAdminServer(t, 8097, "")
+	admin := "http://127.0.0.1:8097"
+
+	if status, _ := adminRequest(t, http.MethodGet, admin+"/health", "", ""); status != http.StatusOK {
+		t.Errorf("Expected admin /health 200, got %d", status)
+	}
+	if status, _ := adminRequest(t, http.MethodGet, admin+"/metrics", "", ""); status != http.StatusOK {
+		t.Errorf("Expected admin /metrics 200, got %d", status)
+	}
+	resp, err := http.Get(admin + "/debug/pprof/goroutine?debug=1")
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected pprof goroutine profile, got %v %v", resp, err)
+	} else {
+		resp.Body.Close()
+	}
+
+	// Admin-only endpoints are not exposed on the public listener
+	if status, _ := adminRequest(t, http.MethodGet, "http://"+srv.Addr()+"/debug/pprof/", "", ""); status != http.StatusNotFound {
+		t.Errorf("Expected pprof to be absent from the public listener, got %d", status)
+	}
+
+	status, dump := adminRequest(t, http.MethodGet, admin+"/config", "", "")
+	if status != http.StatusOK {
+		t.Fatalf("Expected /config 200, got %d", status)
+	}
+	database := dump["database"].(map[string]interface{})
+	if database["password"] != logger.RedactedMask || database["host"] != "db.internal" {
+		t.Errorf("Expected redacted password and visible host, got %v", database)
+	}
+	if serverCfg := dump["server"].(map[string]interface{}); serverCfg["admin_token"] != "" {
+		t.Errorf("Expected an unset admin token to stay empty, got %v", serverCfg["admin_token"])
+	}
+}
+
+// TestAdminListener_TokenAuth tests bearer token protection of the admin endpoints.
+func TestAdminListener_TokenAuth(t *testing.T) {
+	startAdminServer(t, 8098, "s3cret")
+	admin := "http://127.0.0.1:8098"
+
+	if status, _ := adminRequest(t, http.MethodGet, admin+"/health", "", ""); status != http.StatusOK {
+		t.Errorf("Expected /health without token to be allowed, got %d", status)
+	}
+	if status, _ := adminRequest(t, http.MethodGet, admin+"/ready", "", ""); status != http.StatusOK {
+		t.Errorf("Expected /ready without token to be allowed, got %d", status)
+	}
+	if status, _ := adminRequest(t, http.MethodGet, admin+"/metrics", "", ""); status != http.StatusUnauthorized {
+		t.Errorf("Expected /metrics without token to be rejected, got %d", status)
+	}
+	if status, _ := adminRequest(t, http.MethodGet, admin+"/metrics", "wrong", ""); status != http.StatusUnauthorized {
+		t.Errorf("Expected /metrics with wrong token to be rejected, got %d", status)
+	}
+	if status, _ := adminRequest(t, http.MethodGet, admin+"/metrics", "s3cret", ""); status != http.StatusOK {
+		t.Errorf("Expected /metrics with token to succeed, got %d", status)
+	}
+}
+
+// TestAdminListener_LogLevel tests changing the log level at runtime.
+func TestAdminListener_LogLevel(t *testing.T) {
+	startAdminServer(t, 8099, "")
+	url := "http://127.0.0.1:8099/log-level"
+
+	logger.ConfigureLogger(config.Logger{Level: "info", Format: "text"})
+	defer logger.SetLevel(slog.LevelInfo)
+
+	// Without an audit trail the change is refused
+	audit.SetDefault(nil)
+	if status, _ := adminRequest(t, http.MethodPut, url, "", `{"level":"debug"}`); status != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 without audit trail, got %d", status)
+	}
+
+	store, err := audit.OpenFileStore(filepath.Join(t.TempDir(), "audit.log"))
+	if err != nil {
+		t.Fatalf("Failed to open audit store: %v", err)
+	}
+	auditKey := []byte(strings.Repeat("k", audit.MinKeyLength))
+	auditLog, _ := audit.New(context.Background(), store, auditKey, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	audit.SetDefault(auditLog)
+	defer audit.SetDefault(nil)
+
+	if status, _ := adminRequest(t, http.MethodPut, url, "", `{"level":"verbose"}`); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for unknown level, got %d", status)
+	}
+	status, body := adminRequest(t, http.MethodPut, url, "", `{"level":"trace"}`)
+	if status != http.StatusOK || body["level"] != "TRACE" || body["previous"] != "INFO" {
+		t.Errorf("Expected level change INFO -> TRACE, got %d %v", status, body)
+	}
+	if !logger.GetLogger().Enabled(context.Background(), logger.LevelTrace) {
+		t.Error("Expected the configured logger to emit TRACE after the change")
+	}
+	if _, body := adminRequest(t, http.MethodGet, url, "", ""); body["level"] != "TRACE" {
+		t.Errorf("Expected GET /log-level to report TRACE, got %v", body)
+	}
+
+	report, _ := audit.Verify(context.Background(), store, auditKey)
+	if report.Entries != 1 {
+		t.Errorf("Expected the level change in the audit trail, got %d entries", report.Entries)
+	}
+}
+
+// TestAdminListener_BindFailureStopsStart tests that an unavailable admin port fails Start.
+func TestAdminListener_BindFailureStopsStart(t *testing.T) {
+	startAdminServer(t, 8100, "")
+
+	cfg := config.Server{Port: 0, Host: "127.0.0.1", AdminPort: 8100, AdminHost: "127.0.0.1"}
+	srv := server.NewServer(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := srv.Start(); err == nil {
+		srv.Shutdown(context.Background())
+		t.Error("Expected Start to fail when the admin port is in use")
+	}
+}
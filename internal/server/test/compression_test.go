@@ -0,0 +1,171 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// newCompressionServer creates a server with default compression settings and extra test routes.
+func newCompressionServer(t *testing.T) *server.Server {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	defaults := config.NewDefaultConfig(*logger)
+	srv := server.NewServer(config.Server{Port: 0, Host: "127.0.0.1", Compression: defaults.Server.Compression}, logger)
+
+	large := strings.Repeat("course content ", 200)
+	srv.Router().GET("/etag", func(c *gin.Context) {
+		c.Header("ETag", `"v1"`)
+		c.String(http.StatusOK, large)
+	})
+	srv.Router().GET("/events", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.String(http.StatusOK, "data: "+large+"\n\n")
+	})
+	srv.Router().GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(large))
+	})
+	return srv
+}
+
+// compressedRequest serves a GET request with the given Accept-Encoding.
+func compressedRequest(srv *server.Server, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+	return w
+}
+
+// decompress decodes a response body according to its Content-Encoding.
+func decompress(t *testing.T, w *httptest.ResponseRecorder) []byte {
+	t.Helper()
+	var r io.Reader = w.Body
+	switch w.Header().Get("Content-Encoding") {
+	case "gzip":
+		gr, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("Invalid gzip body: %v", err)
+		}
+		r = gr
+	case "zstd":
+		zr, err := zstd.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("Invalid zstd body: %v", err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to decode body: %v", err)
+	}
+	return data
+}
+
+// TestCompression_Negotiation tests encoding selection from Accept-Encoding.
+func TestCompression_Negotiation(t *testing.T) {
+	srv := newCompressionServer(t)
+
+	testCases := []struct {
+		name           string
+		acceptEncoding string
+		wantEncoding   string
+	}{
+		{"No Accept-Encoding", "", ""},
+		{"Gzip only", "gzip", "gzip"},
+		{"Server prefers zstd", "gzip, deflate, br, zstd", "zstd"},
+		{"Zstd refused with q=0", "zstd;q=0, gzip;q=0.5", "gzip"},
+		{"Wildcard", "*", "zstd"},
+		{"Unsupported encodings only", "br, deflate", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := compressedRequest(srv, "/metrics", tc.acceptEncoding)
+			if got := w.Header().Get("Content-Encoding"); got != tc.wantEncoding {
+				t.Fatalf("Expected Content-Encoding %q, got %q", tc.wantEncoding, got)
+			}
+			if !strings.Contains(strings.Join(w.Header().Values("Vary"), ","), "Accept-Encoding") {
+				t.Errorf("Expected Vary: Accept-Encoding, got %v", w.Header().Values("Vary"))
+			}
+			var metrics map[string]interface{}
+			if err := json.Unmarshal(decompress(t, w), &metrics); err != nil {
+				t.Errorf("Expected valid JSON after decoding: %v", err)
+			}
+		})
+	}
+}
+
+// TestCompression_SkipRules tests responses that must not be compressed.
+func TestCompression_SkipRules(t *testing.T) {
+	srv := newCompressionServer(t)
+
+	if w := compressedRequest(srv, "/health", "gzip"); w.Header().Get("Content-Encoding") != "" {
+		t.Error("Expected a response below the minimum size to stay uncompressed")
+	} else if w.Header().Get("Vary") == "" {
+		t.Error("Expected Vary on an uncompressed but compressible response")
+	}
+	if w := compressedRequest(srv, "/events", "gzip"); w.Header().Get("Content-Encoding") != "" {
+		t.Error("Expected Server-Sent Events to stay uncompressed")
+	}
+	if w := compressedRequest(srv, "/image", "gzip"); w.Header().Get("Content-Encoding") != "" || w.Header().Get("Vary") != "" {
+		t.Errorf("Expected a non-listed content type to pass through untouched, got %v", w.Header())
+	}
+}
+
+// TestCompression_ETagWeakened tests that compressed responses carry a weak ETag.
+func TestCompression_ETagWeakened(t *testing.T) {
+	srv := newCompressionServer(t)
+
+	w := compressedRequest(srv, "/etag", "gzip")
+	if w.Header().Get("ETag") != `W/"v1"` {
+		t.Errorf("Expected weak ETag on a compressed response, got %q", w.Header().Get("ETag"))
+	}
+	if !bytes.HasPrefix(decompress(t, w), []byte("course content")) {
+		t.Error("Expected the original body after decoding")
+	}
+
+	if w := compressedRequest(srv, "/etag", ""); w.Header().Get("ETag") != `"v1"` {
+		t.Errorf("Expected the strong ETag on an uncompressed response, got %q", w.Header().Get("ETag"))
+	}
+}
+
+// TestCompression_PanicAfterPartialWrite tests that a panic after a write below
+// MinSize still produces the 500 problem response instead of the partial body.
+func TestCompression_PanicAfterPartialWrite(t *testing.T) {
+	srv := newCompressionServer(t)
+	srv.Router().GET("/partial", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial roster")
+		panic("roster export failed")
+	})
+
+	w := compressedRequest(srv, "/partial", "gzip")
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected 500, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Expected a problem response, got Content-Type %q", ct)
+	}
+	body := string(decompress(t, w))
+	if strings.Contains(body, "partial roster") {
+		t.Errorf("Expected the partial body to be discarded, got %s", body)
+	}
+	var problem map[string]any
+	if err := json.Unmarshal([]byte(body), &problem); err != nil || problem["status"] != float64(http.StatusInternalServerError) {
+		t.Errorf("Expected a problem details body, got %s (%v)", body, err)
+	}
+}
@@ -0,0 +1,174 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// startCORSServer starts a server with the given CORS configuration on an ephemeral port.
+func startCORSServer(t *testing.T, cors config.ServerCORS) string {
+	t.Helper()
+	cfg := config.Server{Port: 0, Host: "127.0.0.1", ReadTimeout: 30, WriteTimeout: 30, CORS: cors}
+	srv := server.NewServer(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	})
+	return "http://" + srv.Addr()
+}
+
+// corsRequest sends a request with the given headers and returns the response.
+func corsRequest(t *testing.T, method, url string, headers map[string]string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+// TestCORS_Policies tests origin matching, preflight handling and group overrides.
+func TestCORS_Policies(t *testing.T) {
+	base := startCORSServer(t, config.ServerCORS{
+		CORSPolicy: config.CORSPolicy{
+			AllowedOrigins:   []string{"https://app.example.com", "https://*.preview.example.com"},
+			AllowedMethods:   []string{"GET", "POST"},
+			AllowedHeaders:   []string{"Content-Type", "Authorization"},
+			ExposedHeaders:   []string{"X-Request-ID"},
+			AllowCredentials: true,
+			MaxAge:           300,
+		},
+		Groups: map[string]config.CORSPolicy{
+			"/metrics": {AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}},
+		},
+	})
+
+	testCases := []struct {
+		name        string
+		method      string
+		path        string
+		headers     map[string]string
+		wantStatus  int
+		wantHeaders map[string]string
+	}{
+		{
+			name:       "Simple request from allowed origin",
+			method:     http.MethodGet,
+			path:       "/health",
+			headers:    map[string]string{"Origin": "https://app.example.com"},
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "X-Request-ID",
+				"Vary":                             "Origin",
+			},
+		},
+		{
+			name:        "Wildcard subdomain",
+			method:      http.MethodGet,
+			path:        "/health",
+			headers:     map[string]string{"Origin": "https://pr-42.preview.example.com"},
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": "https://pr-42.preview.example.com"},
+		},
+		{
+			name:        "Wildcard does not match the bare domain",
+			method:      http.MethodGet,
+			path:        "/health",
+			headers:     map[string]string{"Origin": "https://preview.example.com"},
+			wantStatus:  http.StatusOK,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:   "Preflight is answered without routing",
+			method: http.MethodOptions,
+			path:   "/health",
+			headers: map[string]string{
+				"Origin":                         "https://app.example.com",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "content-type",
+			},
+			wantStatus: http.StatusNoContent,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://app.example.com",
+				"Access-Control-Allow-Methods": "GET, POST",
+				"Access-Control-Allow-Headers": "Content-Type, Authorization",
+				"Access-Control-Max-Age":       "300",
+			},
+		},
+		{
+			name:   "Preflight with disallowed method",
+			method: http.MethodOptions,
+			path:   "/health",
+			headers: map[string]string{
+				"Origin":                        "https://app.example.com",
+				"Access-Control-Request-Method": "DELETE",
+			},
+			wantStatus:  http.StatusForbidden,
+			wantHeaders: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:   "Preflight from unknown origin",
+			method: http.MethodOptions,
+			path:   "/health",
+			headers: map[string]string{
+				"Origin":                        "https://evil.example.net",
+				"Access-Control-Request-Method": "GET",
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Group override allows any origin without credentials",
+			method:     http.MethodGet,
+			path:       "/metrics",
+			headers:    map[string]string{"Origin": "https://dashboard.example.org"},
+			wantStatus: http.StatusOK,
+			wantHeaders: map[string]string{
+				"Access-Control-Allow-Origin":      "*",
+				"Access-Control-Allow-Credentials": "",
+				"Access-Control-Expose-Headers":    "X-Request-ID", // Inherited from the default policy
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := corsRequest(t, tc.method, base+tc.path, tc.headers)
+			if resp.StatusCode != tc.wantStatus {
+				t.Errorf("Expected status %d, got %d", tc.wantStatus, resp.StatusCode)
+			}
+			for k, want := range tc.wantHeaders {
+				if got := resp.Header.Get(k); got != want {
+					t.Errorf("Expected %s %q, got %q", k, want, got)
+				}
+			}
+		})
+	}
+}
+
+// TestCORS_DisabledByDefault tests that no CORS headers are sent without allowed origins.
+func TestCORS_DisabledByDefault(t *testing.T) {
+	base := startCORSServer(t, config.ServerCORS{})
+
+	resp := corsRequest(t, http.MethodGet, base+"/health", map[string]string{"Origin": "https://app.example.com"})
+	if resp.Header.Get("Access-Control-Allow-Origin") != "" || resp.Header.Get("Vary") != "" {
+		t.Errorf("Expected no CORS headers, got %v", resp.Header)
+	}
+}
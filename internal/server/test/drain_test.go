@@ -0,0 +1,91 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// TestDrain_ReadinessFlips tests that /ready turns 503 on Drain while /health stays 200.
+func TestDrain_ReadinessFlips(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := server.NewServer(config.Server{Port: 0, Host: "127.0.0.1"}, logger)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	if w := get("/ready"); w.Code != http.StatusOK {
+		t.Fatalf("Expected /ready to be 200 before draining, got %d", w.Code)
+	}
+	if err := srv.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if !srv.Draining() {
+		t.Error("Expected Draining to report true")
+	}
+
+	w := get("/ready")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected /ready to be 503 while draining, got %d", w.Code)
+	}
+	var problem map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil || problem["code"] != "service_unavailable" {
+		t.Errorf("Expected a service_unavailable problem, got %s", w.Body.String())
+	}
+	if w := get("/health"); w.Code != http.StatusOK {
+		t.Errorf("Expected /health to stay 200 while draining, got %d", w.Code)
+	}
+}
+
+// TestDrain_Delay tests that Drain waits DrainDelay, keeps serving and disables keep-alives.
+func TestDrain_Delay(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := server.NewServer(config.Server{Port: 0, Host: "127.0.0.1", DrainDelay: 1}, logger)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+
+	drained := make(chan error, 1)
+	start := time.Now()
+	go func() { drained <- srv.Drain(context.Background()) }()
+
+	// Requests are still served during the delay, on connections closed afterwards
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for !srv.Draining() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	resp, err := http.Get("http://" + srv.Addr() + "/ready")
+	if err != nil {
+		t.Fatalf("Expected requests to be served while draining: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || !resp.Close {
+		t.Errorf("Expected 503 with Connection: close, got %d (close=%t)", resp.StatusCode, resp.Close)
+	}
+
+	if err := <-drained; err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected Drain to wait the 1 second delay, returned after %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := srv.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the delay to be cut short by the context, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package server_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apperror"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// problemRequest serves a request on a fresh server and decodes the problem response.
+func problemRequest(t *testing.T, srv *server.Server, method, path string) (*httptest.ResponseRecorder, apperror.Problem) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	var problem apperror.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Expected a JSON problem body, got %q", w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != apperror.ContentType {
+		t.Errorf("Expected Content-Type %s, got %s", apperror.ContentType, ct)
+	}
+	return w, problem
+}
+
+// TestErrors_NoRouteAndNoMethod tests problem responses for unmatched requests.
+func TestErrors_NoRouteAndNoMethod(t *testing.T) {
+	srv := server.NewServer(config.Server{Port: 0, Host: "127.0.0.1"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	w, problem := problemRequest(t, srv, http.MethodGet, "/does-not-exist")
+	if w.Code != http.StatusNotFound || problem.Status != 404 || problem.Code != apperror.CodeNotFound ||
+		problem.Instance != "/does-not-exist" || problem.Title != "Not Found" || problem.RequestID == "" {
+		t.Errorf("Unexpected 404 problem %d %+v", w.Code, problem)
+	}
+
+	w, problem = problemRequest(t, srv, http.MethodDelete, "/health")
+	if w.Code != http.StatusMethodNotAllowed || problem.Code != apperror.CodeMethodNotAllowed {
+		t.Fatalf("Unexpected 405 problem %d %+v", w.Code, problem)
+	}
+	if w.Header().Get("Allow") != "GET" {
+		t.Errorf("Expected Allow: GET, got %q", w.Header().Get("Allow"))
+	}
+	if allowed, _ := problem.Details["allowed_methods"].([]interface{}); len(allowed) != 1 || allowed[0] != "GET" {
+		t.Errorf("Expected allowed_methods [GET], got %v", problem.Details)
+	}
+}
+
+// TestErrors_WriteError tests rendering of application and unexpected errors.
+func TestErrors_WriteError(t *testing.T) {
+	srv := server.NewServer(config.Server{Port: 0, Host: "127.0.0.1"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	srv.Router().POST("/enrollments", func(c *gin.Context) {
+		handlers.WriteError(c, apperror.Validation("invalid enrollment",
+			apperror.FieldError{Field: "email", Code: "required", Message: "email is required"}).
+			WithDetail("course_id", "42"))
+	})
+	srv.Router().GET("/wrapped", func(c *gin.Context) {
+		handlers.WriteError(c, errors.Join(apperror.Conflict("already enrolled"), errors.New("duplicate key")))
+	})
+	srv.Router().GET("/unexpected", func(c *gin.Context) {
+		handlers.WriteError(c, errors.New("connection refused by db-7.internal"))
+	})
+
+	w, problem := problemRequest(t, srv, http.MethodPost, "/enrollments")
+	if w.Code != http.StatusUnprocessableEntity || problem.Code != apperror.CodeValidation ||
+		len(problem.Errors) != 1 || problem.Errors[0].Field != "email" || problem.Details["course_id"] != "42" {
+		t.Errorf("Unexpected validation problem %d %+v", w.Code, problem)
+	}
+
+	if w, problem := problemRequest(t, srv, http.MethodGet, "/wrapped"); w.Code != http.StatusConflict || problem.Detail != "already enrolled" {
+		t.Errorf("Expected the wrapped application error to be used, got %d %+v", w.Code, problem)
+	}
+
+	w, problem = problemRequest(t, srv, http.MethodGet, "/unexpected")
+	if w.Code != http.StatusInternalServerError || problem.Code != apperror.CodeInternal || problem.Detail != "internal server error" {
+		t.Errorf("Expected an opaque internal error, got %d %+v", w.Code, problem)
+	}
+}
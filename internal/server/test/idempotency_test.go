@@ -0,0 +1,212 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/idempotency"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// newIdempotencyServer creates a server with a POST /test/enroll route whose
+// calls are counted; the route answers with the status in the "status" query
+// parameter, or panics for status=panic.
+func newIdempotencyServer(t *testing.T, release <-chan struct{}) (*server.Server, *atomic.Int32) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := config.NewDefaultConfig(*logger).Server
+	cfg.Port = 0
+	srv := server.NewServer(cfg, logger)
+
+	calls := &atomic.Int32{}
+	srv.Router().POST("/test/enroll", func(c *gin.Context) {
+		n := calls.Add(1)
+		if release != nil {
+			<-release
+		}
+		if c.Query("status") == "panic" {
+			panic("enrollment failed")
+		}
+		body, _ := io.ReadAll(c.Request.Body)
+		status := http.StatusCreated
+		if c.Query("status") == "500" {
+			status = http.StatusInternalServerError
+		}
+		c.Header("Location", "/test/enroll/"+strconv.Itoa(int(n)))
+		c.JSON(status, gin.H{"call": n, "body": string(body)})
+	})
+	return srv, calls
+}
+
+// postWithKey sends POST /test/enroll with an Idempotency-Key.
+func postWithKey(srv *server.Server, key, query, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/test/enroll"+query, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+	return w
+}
+
+// TestIdempotency_Replay tests that a retry receives the first response without running the handler.
+func TestIdempotency_Replay(t *testing.T) {
+	srv, calls := newIdempotencyServer(t, nil)
+
+	first := postWithKey(srv, "key-1", "", `{"course":1}`)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", first.Code, first.Body.String())
+	}
+	retry := postWithKey(srv, "key-1", "", `{"course":1}`)
+	if calls.Load() != 1 {
+		t.Errorf("Expected the handler to run once, ran %d times", calls.Load())
+	}
+	if retry.Code != first.Code || retry.Body.String() != first.Body.String() {
+		t.Errorf("Expected the first response replayed, got %d %s", retry.Code, retry.Body.String())
+	}
+	if retry.Header().Get("Location") != first.Header().Get("Location") || retry.Header().Get("Content-Type") != first.Header().Get("Content-Type") {
+		t.Errorf("Expected the handler's headers replayed, got %v", retry.Header())
+	}
+	if retry.Header().Get("Idempotent-Replayed") != "true" || first.Header().Get("Idempotent-Replayed") != "" {
+		t.Error("Expected Idempotent-Replayed on the retry only")
+	}
+	if retry.Header().Get("X-Request-ID") == first.Header().Get("X-Request-ID") {
+		t.Error("Expected the retry to keep its own request ID")
+	}
+
+	// Without a key every request runs
+	postWithKey(srv, "", "", `{"course":1}`)
+	postWithKey(srv, "", "", `{"course":1}`)
+	if calls.Load() != 3 {
+		t.Errorf("Expected requests without a key to run, handler ran %d times", calls.Load())
+	}
+}
+
+// TestIdempotency_Rejections tests reused keys, overlong keys and requests still in progress.
+func TestIdempotency_Rejections(t *testing.T) {
+	release := make(chan struct{})
+	srv, _ := newIdempotencyServer(t, release)
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- postWithKey(srv, "key-1", "", `{"course":1}`) }()
+	time.Sleep(50 * time.Millisecond) // Let the first request claim the key
+
+	inProgress := postWithKey(srv, "key-1", "", `{"course":1}`)
+	if inProgress.Code != http.StatusConflict || inProgress.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 409 with Retry-After while in progress, got %d", inProgress.Code)
+	}
+	reused := postWithKey(srv, "key-1", "", `{"course":2}`)
+	if reused.Code != http.StatusUnprocessableEntity || !strings.Contains(reused.Body.String(), "idempotency_key_reused") {
+		t.Errorf("Expected 422 idempotency_key_reused, got %d: %s", reused.Code, reused.Body.String())
+	}
+
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("Expected the first request to succeed, got %d", first.Code)
+	}
+
+	if w := postWithKey(srv, strings.Repeat("k", 256), "", `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an overlong key, got %d", w.Code)
+	}
+}
+
+// TestIdempotency_ServerErrorsNotStored tests that failed requests can be retried.
+func TestIdempotency_ServerErrorsNotStored(t *testing.T) {
+	srv, calls := newIdempotencyServer(t, nil)
+
+	if w := postWithKey(srv, "key-1", "?status=500", `{}`); w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected 500, got %d", w.Code)
+	}
+	if w := postWithKey(srv, "key-1", "?status=500", `{}`); w.Header().Get("Idempotent-Replayed") != "" {
+		t.Error("Expected a server error not to be replayed")
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected the handler to run again, ran %d times", calls.Load())
+	}
+}
+
+// TestIdempotency_PanicReleasesKey tests that a retry after a panicking handler runs again.
+func TestIdempotency_PanicReleasesKey(t *testing.T) {
+	srv, calls := newIdempotencyServer(t, nil)
+
+	for i := 0; i < 2; i++ {
+		w := postWithKey(srv, "key-1", "?status=panic", `{}`)
+		if w.Code != http.StatusInternalServerError || !strings.Contains(w.Header().Get("Content-Type"), "application/problem+json") {
+			t.Fatalf("Attempt %d: expected a 500 problem response, got %d %s", i+1, w.Code, w.Header().Get("Content-Type"))
+		}
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected the retry to run the handler instead of waiting for the lock, ran %d times", calls.Load())
+	}
+}
+
+// TestIdempotency_KeysScopedPerClient tests that clients choosing the same key do not share it.
+func TestIdempotency_KeysScopedPerClient(t *testing.T) {
+	srv, calls := newIdempotencyServer(t, nil)
+
+	post := func(remoteAddr, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/test/enroll", strings.NewReader(body))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Idempotency-Key", "key-1")
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post("192.0.2.1:1234", `{"course":1}`); w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", w.Code)
+	}
+	other := post("192.0.2.2:1234", `{"course":2}`)
+	if other.Code != http.StatusCreated || other.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("Expected another client's key to be independent, got %d: %s", other.Code, other.Body.String())
+	}
+	if retry := post("192.0.2.1:5678", `{"course":1}`); retry.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("Expected the first client's retry to be replayed")
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected the handler to run once per client, ran %d times", calls.Load())
+	}
+}
+
+// failingIdempotencyStore is an idempotency.Store that is always unavailable.
+type failingIdempotencyStore struct{}
+
+func (failingIdempotencyStore) Acquire(context.Context, string, string, string, time.Duration, time.Duration) (*idempotency.Record, error) {
+	return nil, errors.New("store unavailable")
+}
+
+func (failingIdempotencyStore) Complete(context.Context, string, string, idempotency.Response) error {
+	return errors.New("store unavailable")
+}
+
+func (failingIdempotencyStore) Release(context.Context, string, string) error {
+	return errors.New("store unavailable")
+}
+
+// TestIdempotency_StoreFailure tests that keyed requests are rejected while the store is down.
+func TestIdempotency_StoreFailure(t *testing.T) {
+	srv, calls := newIdempotencyServer(t, nil)
+	srv.SetIdempotencyStore(failingIdempotencyStore{})
+
+	if w := postWithKey(srv, "key-1", "", `{}`); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", w.Code)
+	}
+	if w := postWithKey(srv, "", "", `{}`); w.Code != http.StatusCreated {
+		t.Errorf("Expected requests without a key to run, got %d", w.Code)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected only the unkeyed request to run, ran %d times", calls.Load())
+	}
+}
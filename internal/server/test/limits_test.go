@@ -0,0 +1,182 @@
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apperror"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// newLimitsServer creates a server with small limits, a larger upload group and test routes.
+func newLimitsServer(t *testing.T) *server.Server {
+	t.Helper()
+	cfg := config.Server{Port: 0, Host: "127.0.0.1", Limits: config.ServerLimits{
+		RouteLimits: config.RouteLimits{MaxBodyBytes: 16, HandlerTimeout: 1},
+		Groups: map[string]config.RouteLimits{
+			"/uploads":   {MaxBodyBytes: 64},
+			"/unlimited": {MaxBodyBytes: -1, HandlerTimeout: -1},
+		},
+	}}
+	srv := server.NewServer(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	echo := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			handlers.WriteError(c, err)
+			return
+		}
+		c.String(http.StatusOK, "%d", len(body))
+	}
+	srv.Router().POST("/echo", echo)
+	srv.Router().POST("/uploads", echo)
+	srv.Router().POST("/unlimited", echo)
+	srv.Router().GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+	srv.Router().GET("/upstream", func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), time.Millisecond)
+		defer cancel()
+		<-ctx.Done()
+		handlers.WriteError(c, fmt.Errorf("calling catalog service: %w", ctx.Err()))
+	})
+	hasDeadline := func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		c.String(http.StatusOK, "%t", ok)
+	}
+	srv.Router().GET("/deadline", hasDeadline)
+	srv.Router().GET("/unlimited/deadline", hasDeadline)
+	return srv
+}
+
+// TestLimits_BodySize tests body caps by Content-Length and while streaming, with group overrides.
+func TestLimits_BodySize(t *testing.T) {
+	srv := newLimitsServer(t)
+
+	testCases := []struct {
+		name       string
+		path       string
+		size       int
+		streamed   bool // Hide Content-Length so the limit is hit while reading
+		wantStatus int
+	}{
+		{"Within default limit", "/echo", 16, false, http.StatusOK},
+		{"Declared length over limit", "/echo", 17, false, http.StatusRequestEntityTooLarge},
+		{"Streamed body over limit", "/echo", 17, true, http.StatusRequestEntityTooLarge},
+		{"Group override allows more", "/uploads", 64, false, http.StatusOK},
+		{"Group override still enforced", "/uploads", 65, true, http.StatusRequestEntityTooLarge},
+		{"Group without limit", "/unlimited", 4096, true, http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var body io.Reader = strings.NewReader(strings.Repeat("x", tc.size))
+			if tc.streamed {
+				body = io.MultiReader(body)
+			}
+			req := httptest.NewRequest(http.MethodPost, tc.path, body)
+			if tc.streamed {
+				req.ContentLength = -1
+			}
+			w := httptest.NewRecorder()
+			srv.Router().ServeHTTP(w, req)
+
+			if w.Code != tc.wantStatus {
+				t.Fatalf("Expected %d, got %d: %s", tc.wantStatus, w.Code, w.Body.String())
+			}
+			if tc.wantStatus == http.StatusRequestEntityTooLarge && !strings.Contains(w.Body.String(), apperror.CodePayloadTooLarge) {
+				t.Errorf("Expected a payload_too_large problem, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+// TestLimits_HandlerDeadline tests 503 for expired handler deadlines and 504 for upstream timeouts.
+func TestLimits_HandlerDeadline(t *testing.T) {
+	srv := newLimitsServer(t)
+
+	start := time.Now()
+	w, problem := problemRequest(t, srv, http.MethodGet, "/slow")
+	if w.Code != http.StatusServiceUnavailable || problem.Code != apperror.CodeTimeout {
+		t.Errorf("Expected a 503 request_timeout problem, got %d %+v", w.Code, problem)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Expected the request context to be canceled after 1s, took %v", elapsed)
+	}
+
+	w, problem = problemRequest(t, srv, http.MethodGet, "/upstream")
+	if w.Code != http.StatusGatewayTimeout || problem.Code != apperror.CodeUpstreamTimeout {
+		t.Errorf("Expected a 504 upstream_timeout problem, got %d %+v", w.Code, problem)
+	}
+	if strings.Contains(w.Body.String(), "catalog service") {
+		t.Error("Expected the upstream error text to stay out of the response")
+	}
+
+	for path, want := range map[string]string{"/deadline": "true", "/unlimited/deadline": "false"} {
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Body.String() != want {
+			t.Errorf("Expected deadline set %s for %s, got %s", want, path, w.Body.String())
+		}
+	}
+}
+
+// TestLimits_WriteErrorClassification tests that limit errors map to the same problems from any handler.
+func TestLimits_WriteErrorClassification(t *testing.T) {
+	srv := server.NewServer(config.Server{Port: 0, Host: "127.0.0.1"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	srv.Router().GET("/too-large", func(c *gin.Context) {
+		handlers.WriteError(c, fmt.Errorf("decoding upload: %w", &http.MaxBytesError{Limit: 10}))
+	})
+	srv.Router().GET("/canceled", func(c *gin.Context) {
+		handlers.WriteError(c, fmt.Errorf("querying catalog: %w", context.DeadlineExceeded))
+	})
+
+	if w, problem := problemRequest(t, srv, http.MethodGet, "/too-large"); w.Code != http.StatusRequestEntityTooLarge ||
+		problem.Details["max_body_bytes"] != float64(10) {
+		t.Errorf("Expected 413 with the limit in details, got %d %+v", w.Code, problem)
+	}
+	// No handler deadline is configured, so the request itself cannot have expired
+	if w, _ := problemRequest(t, srv, http.MethodGet, "/canceled"); w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected 504 for a deadline error outside the request deadline, got %d", w.Code)
+	}
+}
+
+// TestLimits_HTTPServerSettings tests that header limits are applied to the listening server.
+func TestLimits_HTTPServerSettings(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := config.NewDefaultConfig(*logger).Server
+	cfg.Port, cfg.Host, cfg.AdminPort, cfg.MaxHeaderBytes = 0, "127.0.0.1", 0, 1024
+	srv := server.NewServer(cfg, logger)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+
+	conn, err := net.Dial("tcp", srv.Addr())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+	// net/http allows 4 KiB of slack on top of MaxHeaderBytes
+	fmt.Fprintf(conn, "GET /health HTTP/1.1\r\nHost: test\r\nX-Padding: %s\r\n\r\n", strings.Repeat("a", 8192))
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestHeaderFieldsTooLarge {
+		t.Errorf("Expected 431 for oversized headers, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,198 @@
+package server_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// unixClient returns an HTTP client connecting to the Unix socket at path.
+func unixClient(path string) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		},
+	}}
+}
+
+// getStatus sends GET url with client and returns the status code.
+func getStatus(t *testing.T, client *http.Client, url string) int {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// TestListeners_UnixSocketAndTCP tests a Unix socket primary listener with an additional TCP listener.
+func TestListeners_UnixSocketAndTCP(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "goedu.sock")
+	cfg := config.Server{
+		Address:    "unix://" + socket,
+		SocketMode: "0600",
+		Listeners:  []config.ServerListener{{Address: "tcp://127.0.0.1:0"}},
+	}
+	srv := server.NewServer(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+
+	addrs := srv.Addrs()
+	if len(addrs) != 2 || addrs[0] != "unix://"+socket || srv.Addr() != addrs[0] || !strings.HasPrefix(addrs[1], "127.0.0.1:") {
+		t.Fatalf("Unexpected listener addresses %v", addrs)
+	}
+	info, err := os.Stat(socket)
+	if err != nil || info.Mode()&os.ModeSocket == 0 || info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected a socket with mode 0600, got %v (%v)", info.Mode(), err)
+	}
+
+	if status := getStatus(t, unixClient(socket), "http://goedu/health"); status != http.StatusOK {
+		t.Errorf("Expected 200 over the Unix socket, got %d", status)
+	}
+	if status := getStatus(t, http.DefaultClient, "http://"+addrs[1]+"/health"); status != http.StatusOK {
+		t.Errorf("Expected 200 over TCP, got %d", status)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	<-srv.Done()
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Errorf("Expected the socket file to be removed on shutdown, got %v", err)
+	}
+}
+
+// TestListeners_ExistingSocketFile tests stale socket replacement and refusal to clobber other files.
+func TestListeners_ExistingSocketFile(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dir := t.TempDir()
+
+	stale := filepath.Join(dir, "stale.sock")
+	l, err := net.Listen("unix", stale)
+	if err != nil {
+		t.Fatalf("Failed to create socket: %v", err)
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close() // Leaves the socket file behind, as a crashed process would
+
+	srv := server.NewServer(config.Server{Address: "unix://" + stale}, logger)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Expected a stale socket to be replaced, got %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+
+	// The socket is now live, so a second server must not take it over
+	if err := server.NewServer(config.Server{Address: "unix://" + stale}, logger).Start(); err == nil {
+		t.Error("Expected a socket in use to be refused")
+	}
+
+	regular := filepath.Join(dir, "data.txt")
+	os.WriteFile(regular, []byte("keep me"), 0o600)
+	if err := server.NewServer(config.Server{Address: "unix://" + regular}, logger).Start(); err == nil {
+		t.Error("Expected a regular file at the socket path to be refused")
+	}
+	if data, _ := os.ReadFile(regular); string(data) != "keep me" {
+		t.Error("Expected the regular file to be left untouched")
+	}
+}
+
+// TestListeners_InvalidAddress tests that bad listener settings fail Start and release bound listeners.
+func TestListeners_InvalidAddress(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	socket := filepath.Join(t.TempDir(), "goedu.sock")
+
+	testCases := []config.Server{
+		{Address: "http://127.0.0.1:0"},
+		{Address: "unix://"},
+		{Address: "unix://" + socket, SocketMode: "rw-rw----"},
+		{Host: "127.0.0.1", Port: 0, Listeners: []config.ServerListener{{Address: "ftp://example"}}},
+	}
+	for _, cfg := range testCases {
+		if err := server.NewServer(cfg, logger).Start(); err == nil {
+			t.Errorf("Expected Start to fail for %+v", cfg)
+		}
+	}
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Errorf("Expected no socket left behind after a failed Start, got %v", err)
+	}
+}
+
+// TestListeners_SocketActivation tests serving sockets passed with the systemd LISTEN_FDS protocol.
+//
+// The test re-executes the test binary with two listening sockets as file
+// descriptors 3 and 4, because LISTEN_PID must match the serving process.
+func TestListeners_SocketActivation(t *testing.T) {
+	if os.Getenv("GOEDU_TEST_ACTIVATED") == "1" {
+		runActivatedServer(t)
+		return
+	}
+
+	public, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer public.Close()
+	admin, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer admin.Close()
+	publicFile, _ := public.(*net.TCPListener).File()
+	adminFile, _ := admin.(*net.TCPListener).File()
+
+	// exec keeps the shell's PID, so $$ is the PID of the test binary
+	cmd := exec.Command("/bin/sh", "-c", `LISTEN_PID=$$ exec "$0" -test.run '^TestListeners_SocketActivation$'`, os.Args[0])
+	cmd.Env = append(os.Environ(),
+		"GOEDU_TEST_ACTIVATED=1",
+		"LISTEN_FDS=2",
+		"LISTEN_FDNAMES=http:admin",
+		"GOEDU_TEST_PUBLIC_ADDR="+public.Addr().String(),
+		"GOEDU_TEST_ADMIN_ADDR="+admin.Addr().String(),
+	)
+	cmd.ExtraFiles = []*os.File{publicFile, adminFile}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Activated server failed: %v\n%s", err, out)
+	}
+}
+
+// runActivatedServer is the child process of TestListeners_SocketActivation.
+func runActivatedServer(t *testing.T) {
+	// An unbindable host proves the passed sockets are used instead
+	cfg := config.Server{Host: "256.0.0.1", Port: 8080, SocketActivation: true}
+	srv := server.NewServer(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Failed to start with activated sockets: %v", err)
+	}
+	defer srv.Shutdown(context.Background())
+
+	publicAddr := os.Getenv("GOEDU_TEST_PUBLIC_ADDR")
+	if addrs := srv.Addrs(); len(addrs) != 1 || addrs[0] != publicAddr {
+		t.Errorf("Expected the activated socket %s as the only public listener, got %v", publicAddr, addrs)
+	}
+	if status := getStatus(t, http.DefaultClient, "http://"+publicAddr+"/"); status != http.StatusOK {
+		t.Errorf("Expected 200 from the public router, got %d", status)
+	}
+	// The admin router serves /log-level, which the public router does not
+	if status := getStatus(t, http.DefaultClient, "http://"+os.Getenv("GOEDU_TEST_ADMIN_ADDR")+"/log-level"); status != http.StatusOK {
+		t.Errorf("Expected the socket named admin to serve the admin router, got %d", status)
+	}
+	if os.Getenv("LISTEN_FDS") != "" || os.Getenv("LISTEN_PID") != "" {
+		t.Error("Expected LISTEN_* variables to be removed from the environment")
+	}
+}
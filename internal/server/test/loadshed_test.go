@@ -0,0 +1,111 @@
+package server_test
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// newLoadShedServer creates a server admitting one request at a time, with a
+// GET /test/slow route that blocks until release is closed.
+func newLoadShedServer(t *testing.T, release <-chan struct{}) (*server.Server, <-chan struct{}) {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := config.NewDefaultConfig(*logger).Server
+	cfg.Port = 0
+	cfg.LoadShedding.Enabled = true
+	cfg.LoadShedding.InitialLimit = 1
+	cfg.LoadShedding.MinLimit = 1
+	cfg.LoadShedding.MaxLimit = 1
+	cfg.LoadShedding.RetryAfter = 3
+	srv := server.NewServer(cfg, logger)
+
+	started := make(chan struct{})
+	srv.Router().GET("/test/slow", func(c *gin.Context) {
+		close(started)
+		<-release
+		c.Status(http.StatusNoContent)
+	})
+	return srv, started
+}
+
+// TestLoadShedding_RejectsExcess tests that requests beyond the limit get 503 with Retry-After,
+// exempt paths are still served and the metrics report the rejection.
+func TestLoadShedding_RejectsExcess(t *testing.T) {
+	release := make(chan struct{})
+	srv, started := newLoadShedServer(t, release)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		srv.Router().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test/slow", nil))
+	}()
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the slow request")
+	}
+
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 while at the limit, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "3" {
+		t.Errorf("Expected Retry-After 3, got %q", got)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("Expected a problem response, got %q", got)
+	}
+
+	for _, path := range []string{"/health", "/metrics"} {
+		w = httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected exempt %s to be served, got %d", path, w.Code)
+		}
+	}
+
+	var metrics struct {
+		Application struct {
+			LoadShedding struct {
+				Enabled       bool `json:"enabled"`
+				Limit         int  `json:"limit"`
+				InFlight      int  `json:"in_flight"`
+				RejectedTotal int  `json:"rejected_total"`
+			} `json:"load_shedding"`
+		} `json:"application"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &metrics); err != nil {
+		t.Fatalf("Failed to decode metrics: %v", err)
+	}
+	shed := metrics.Application.LoadShedding
+	if !shed.Enabled || shed.Limit != 1 || shed.InFlight != 1 || shed.RejectedTotal != 1 {
+		t.Errorf("Expected limit 1, 1 in flight and 1 rejected, got %+v", shed)
+	}
+
+	close(release)
+	<-done
+	w = httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected requests to be admitted once load drops, got %d", w.Code)
+	}
+}
+
+// TestLoadShedding_Disabled tests that the limiter is absent by default.
+func TestLoadShedding_Disabled(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	srv := server.NewServer(config.NewDefaultConfig(*logger).Server, logger)
+	if srv.LoadShedder() != nil {
+		t.Error("Expected no load shedder when load shedding is disabled")
+	}
+}
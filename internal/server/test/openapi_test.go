@@ -0,0 +1,107 @@
+package server_test
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// newDefaultServer creates a server with the complete default configuration, so every optional route is registered.
+func newDefaultServer() *server.Server {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := config.NewDefaultConfig(*logger).Server
+	cfg.Port = 0
+	cfg.RateLimit.Enabled = true
+	return server.NewServer(cfg, logger)
+}
+
+// TestOpenAPI_EveryRouteDocumented fails when a public route is registered without an OpenAPI description.
+func TestOpenAPI_EveryRouteDocumented(t *testing.T) {
+	srv := newDefaultServer()
+	routes := srv.Router().Routes()
+	if len(routes) == 0 {
+		t.Fatal("Expected registered routes")
+	}
+	for _, r := range routes {
+		if !srv.OpenAPI().Has(r.Method, r.Path) {
+			t.Errorf("%s %s is registered without an OpenAPI description; register it with Server.handle", r.Method, r.Path)
+		}
+	}
+}
+
+// TestOpenAPI_Document tests the document served at /openapi.json.
+func TestOpenAPI_Document(t *testing.T) {
+	srv := newDefaultServer()
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var doc struct {
+		OpenAPI    string                               `json:"openapi"`
+		Paths      map[string]map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("Expected OpenAPI 3.1.0, got %q", doc.OpenAPI)
+	}
+	for _, path := range []string{"/", "/health", "/ready", "/metrics", "/openapi.json", "/docs"} {
+		if _, ok := doc.Paths[path]["get"]; !ok {
+			t.Errorf("Expected GET %s in the document", path)
+		}
+	}
+	if _, ok := doc.Paths["/csp-report"]["post"]; !ok {
+		t.Error("Expected POST /csp-report in the document")
+	}
+	responses, _ := doc.Paths["/ready"]["get"]["responses"].(map[string]any)
+	for _, status := range []string{"200", "429", "500", "503"} {
+		if _, ok := responses[status]; !ok {
+			t.Errorf("Expected a %s response for /ready, got %v", status, responses)
+		}
+	}
+	health := doc.Components.Schemas["HealthResponse"]
+	if props, _ := health["properties"].(map[string]any); props["memory"] == nil {
+		t.Errorf("Expected the HealthResponse schema to describe memory, got %v", health)
+	}
+	if doc.Components.Schemas["Problem"] == nil {
+		t.Error("Expected the problem details schema")
+	}
+}
+
+// TestOpenAPI_Explorer tests that the explorer page loads the document under the CSP nonce.
+func TestOpenAPI_Explorer(t *testing.T) {
+	srv := newDefaultServer()
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("Expected an HTML page, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	csp := w.Header().Get("Content-Security-Policy")
+	start := strings.Index(csp, "'nonce-")
+	if start < 0 {
+		t.Fatalf("Expected a nonce in the policy, got %q", csp)
+	}
+	nonce := strings.TrimPrefix(csp[start:], "'nonce-")
+	nonce = nonce[:strings.Index(nonce, "'")]
+	body := w.Body.String()
+	if !strings.Contains(body, `<script nonce="`+nonce+`">`) || !strings.Contains(body, `<style nonce="`+nonce+`">`) {
+		t.Error("Expected the inline script and style to carry the request's nonce")
+	}
+	if !strings.Contains(body, "openapi.json") {
+		t.Error("Expected the page to load /openapi.json")
+	}
+}
@@ -0,0 +1,167 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/ratelimit"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// startRateLimitServer starts a server with the given rate limit configuration
+// and trusted proxies on an ephemeral port.
+func startRateLimitServer(t *testing.T, rl config.ServerRateLimit, store ratelimit.Store, trustedProxies ...string) string {
+	t.Helper()
+	rl.Enabled = true
+	cfg := config.Server{Port: 0, Host: "127.0.0.1", ReadTimeout: 30, WriteTimeout: 30, RateLimit: rl, TrustedProxies: trustedProxies}
+	srv := server.NewServer(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if store != nil {
+		srv.SetRateLimitStore(store)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	})
+	return "http://" + srv.Addr()
+}
+
+// failingStore is a ratelimit.Store that is always unavailable.
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Policy) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store unavailable")
+}
+
+// TestRateLimit_Policies tests the default policy, group overrides and response headers.
+func TestRateLimit_Policies(t *testing.T) {
+	base := startRateLimitServer(t, config.ServerRateLimit{
+		RateLimitPolicy: config.RateLimitPolicy{Requests: 3, Window: 60},
+		Groups: map[string]config.RateLimitPolicy{
+			"/health":  {Requests: 0},
+			"/metrics": {Requests: 1},
+		},
+	}, nil)
+
+	for i, wantRemaining := range []string{"2", "1", "0"} {
+		resp := corsRequest(t, http.MethodGet, base+"/", nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Request %d: expected 200, got %d", i+1, resp.StatusCode)
+		}
+		if got := resp.Header.Get("RateLimit-Remaining"); got != wantRemaining {
+			t.Errorf("Request %d: expected RateLimit-Remaining %s, got %s", i+1, wantRemaining, got)
+		}
+		if resp.Header.Get("RateLimit-Limit") != "3" || resp.Header.Get("RateLimit-Policy") != "3;w=60" {
+			t.Errorf("Request %d: unexpected limit headers %v", i+1, resp.Header)
+		}
+	}
+
+	resp := corsRequest(t, http.MethodGet, base+"/", nil)
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429 once the bucket is empty, got %d", resp.StatusCode)
+	}
+	// One token per 20 seconds
+	if retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After")); retryAfter < 19 || retryAfter > 20 {
+		t.Errorf("Expected Retry-After of about 20 seconds, got %q", resp.Header.Get("Retry-After"))
+	}
+
+	// Groups with zero requests are not limited
+	for i := 0; i < 5; i++ {
+		resp := corsRequest(t, http.MethodGet, base+"/health", nil)
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("RateLimit-Limit") != "" {
+			t.Fatalf("Expected unlimited /health without headers, got %d %v", resp.StatusCode, resp.Header)
+		}
+	}
+
+	// Groups have their own buckets; an X-API-Key header buys no new budget
+	if resp := corsRequest(t, http.MethodGet, base+"/metrics", map[string]string{"X-API-Key": "key-a"}); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the first /metrics request to pass, got %d", resp.StatusCode)
+	}
+	if resp := corsRequest(t, http.MethodGet, base+"/metrics", map[string]string{"X-API-Key": "key-b"}); resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected another API key to share the IP budget, got %d", resp.StatusCode)
+	}
+
+	var limited uint64
+	for _, stats := range ratelimit.Stats() {
+		if stats.Policy == "/metrics" {
+			limited = stats.Limited
+		}
+	}
+	if limited == 0 {
+		t.Errorf("Expected limited requests to be counted for /metrics, got %+v", ratelimit.Stats())
+	}
+}
+
+// TestRateLimit_ForwardedFor tests that X-Forwarded-For only selects the bucket
+// when the peer is a trusted proxy.
+func TestRateLimit_ForwardedFor(t *testing.T) {
+	policy := config.ServerRateLimit{RateLimitPolicy: config.RateLimitPolicy{Requests: 2, Window: 60}}
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		wantAllowed    int
+	}{
+		{"untrusted peer", nil, 2},
+		{"trusted proxy", []string{"127.0.0.1"}, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := startRateLimitServer(t, policy, ratelimit.NewMemoryStore(), tt.trustedProxies...)
+			allowed := 0
+			for i := 0; i < 10; i++ {
+				spoofed := map[string]string{"X-Forwarded-For": "203.0.113." + strconv.Itoa(i+1)}
+				if resp := corsRequest(t, http.MethodGet, base+"/", spoofed); resp.StatusCode == http.StatusOK {
+					allowed++
+				}
+			}
+			if allowed != tt.wantAllowed {
+				t.Errorf("Expected %d of 10 requests with rotating X-Forwarded-For to pass, got %d", tt.wantAllowed, allowed)
+			}
+		})
+	}
+}
+
+// TestRateLimit_ExemptPaths tests that probes stay reachable once a client's bucket is empty.
+func TestRateLimit_ExemptPaths(t *testing.T) {
+	base := startRateLimitServer(t, config.ServerRateLimit{
+		RateLimitPolicy: config.RateLimitPolicy{Requests: 1, Window: 60},
+		ExemptPaths:     []string{"/health", "/ready"},
+	}, nil)
+
+	corsRequest(t, http.MethodGet, base+"/", nil)
+	if resp := corsRequest(t, http.MethodGet, base+"/", nil); resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429 once the bucket is empty, got %d", resp.StatusCode)
+	}
+	for i := 0; i < 3; i++ {
+		resp := corsRequest(t, http.MethodGet, base+"/health", nil)
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("RateLimit-Limit") != "" {
+			t.Fatalf("Expected exempt /health without limit headers, got %d %v", resp.StatusCode, resp.Header)
+		}
+	}
+}
+
+// TestRateLimit_StoreFailure tests fail-open and fail-closed behavior.
+func TestRateLimit_StoreFailure(t *testing.T) {
+	policy := config.RateLimitPolicy{Requests: 10, Window: 60}
+
+	failOpen := startRateLimitServer(t, config.ServerRateLimit{RateLimitPolicy: policy}, failingStore{})
+	if resp := corsRequest(t, http.MethodGet, failOpen+"/health", nil); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected fail-open to allow the request, got %d", resp.StatusCode)
+	}
+
+	failClosed := startRateLimitServer(t, config.ServerRateLimit{RateLimitPolicy: policy, FailClosed: true}, failingStore{})
+	if resp := corsRequest(t, http.MethodGet, failClosed+"/health", nil); resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Expected fail-closed to reject the request with 503, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,124 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// newPanicServer creates a server with routes that panic, logging into the returned buffer.
+func newPanicServer(t *testing.T) (*server.Server, *bytes.Buffer) {
+	t.Helper()
+	var logs bytes.Buffer
+	srv := server.NewServer(config.Server{Port: 0, Host: "127.0.0.1"}, slog.New(slog.NewJSONHandler(&logs, nil)))
+	srv.Router().GET("/boom/:id", func(c *gin.Context) {
+		panic("handler exploded")
+	})
+	srv.Router().GET("/repanic", func(c *gin.Context) {
+		c.Set(handlers.RepanicKey, true)
+		panic("surface in test")
+	})
+	return srv, &logs
+}
+
+// TestRecovery_JSONResponseAndLog tests the problem response, request ID propagation and panic log entry.
+func TestRecovery_JSONResponseAndLog(t *testing.T) {
+	srv, logs := newPanicServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/boom/42", nil)
+	req.Header.Set("X-Request-ID", "trace-abc-123")
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected 500, got %d", w.Code)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected JSON error body, got %q", w.Body.String())
+	}
+	if body["code"] != "internal_error" || body["request_id"] != "trace-abc-123" {
+		t.Errorf("Unexpected error body %v", body)
+	}
+	if strings.Contains(w.Body.String(), "handler exploded") {
+		t.Error("Expected the panic value to stay out of the response")
+	}
+	if w.Header().Get("X-Request-ID") != "trace-abc-123" {
+		t.Errorf("Expected the request ID echoed in the response header, got %q", w.Header().Get("X-Request-ID"))
+	}
+
+	var entry map[string]interface{}
+	for _, line := range strings.Split(logs.String(), "\n") {
+		if strings.Contains(line, "Panic recovered") {
+			json.Unmarshal([]byte(line), &entry)
+		}
+	}
+	if entry == nil {
+		t.Fatalf("Expected a panic log entry, got %s", logs.String())
+	}
+	if entry["level"] != "ERROR" || entry["panic"] != "handler exploded" || entry["route"] != "/boom/:id" ||
+		entry["request_id"] != "trace-abc-123" || !strings.Contains(entry["stack"].(string), "recovery_test.go") {
+		t.Errorf("Unexpected panic log entry %v", entry)
+	}
+}
+
+// TestRecovery_GeneratesRequestID tests that invalid or missing request IDs are replaced.
+func TestRecovery_GeneratesRequestID(t *testing.T) {
+	srv, _ := newPanicServer(t)
+
+	for _, given := range []string{"", "bad id with spaces", strings.Repeat("x", 200)} {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		req.Header.Set("X-Request-ID", given)
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, req)
+
+		if id := w.Header().Get("X-Request-ID"); len(id) != 32 {
+			t.Errorf("Expected a generated 32 character request ID for %q, got %q", given, id)
+		}
+	}
+}
+
+// TestRecovery_Repanic tests that handlers can opt into re-panicking.
+func TestRecovery_Repanic(t *testing.T) {
+	srv, logs := newPanicServer(t)
+
+	defer func() {
+		if recovered := recover(); recovered != "surface in test" {
+			t.Errorf("Expected the original panic value, got %v", recovered)
+		}
+		if !strings.Contains(logs.String(), "Panic recovered") {
+			t.Error("Expected the panic to be logged before re-panicking")
+		}
+	}()
+	srv.Router().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/repanic", nil))
+	t.Error("Expected ServeHTTP to panic")
+}
+
+// TestRecovery_CountsPanics tests the panics_total metric.
+func TestRecovery_CountsPanics(t *testing.T) {
+	srv, _ := newPanicServer(t)
+
+	panics := func() float64 {
+		w := httptest.NewRecorder()
+		srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		var metrics map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &metrics)
+		requests := metrics["application"].(map[string]interface{})["requests"].(map[string]interface{})
+		return requests["panics_total"].(float64)
+	}
+
+	before := panics()
+	srv.Router().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom/1", nil))
+	if after := panics(); after != before+1 {
+		t.Errorf("Expected panics_total to grow by 1, got %v -> %v", before, after)
+	}
+}
@@ -0,0 +1,106 @@
+package server_test
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/routes"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// TestRoutes_RegistryMatchesRouter tests that the registry records exactly the routes the public router serves.
+func TestRoutes_RegistryMatchesRouter(t *testing.T) {
+	srv := newDefaultServer()
+
+	var served []string
+	for _, r := range srv.Router().Routes() {
+		served = append(served, r.Method+" "+r.Path)
+	}
+	var recorded []string
+	for _, r := range srv.Routes().Routes(routes.ListenerPublic) {
+		recorded = append(recorded, r.String())
+		if r.Auth != routes.AuthNone {
+			t.Errorf("%s: expected auth %q, got %q", r, routes.AuthNone, r.Auth)
+		}
+		if r.Description == "" {
+			t.Errorf("%s has no description", r)
+		}
+	}
+	sort.Strings(served)
+	sort.Strings(recorded)
+	if len(served) != len(recorded) {
+		t.Fatalf("Router serves %v, registry records %v", served, recorded)
+	}
+	for i := range served {
+		if served[i] != recorded[i] {
+			t.Fatalf("Router serves %v, registry records %v", served, recorded)
+		}
+	}
+}
+
+// TestRoutes_RootListing tests that the root endpoint lists the registered public routes.
+func TestRoutes_RootListing(t *testing.T) {
+	srv := newDefaultServer()
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+
+	var body struct {
+		Endpoints []string       `json:"endpoints"`
+		Routes    []routes.Route `json:"routes"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	want := srv.Routes().Routes(routes.ListenerPublic)
+	if len(body.Endpoints) != len(want) || len(body.Routes) != len(want) {
+		t.Fatalf("Expected %d routes, got %d endpoints and %d routes", len(want), len(body.Endpoints), len(body.Routes))
+	}
+	for i, r := range want {
+		if body.Endpoints[i] != r.String() || body.Routes[i] != r {
+			t.Errorf("Entry %d: expected %s, got %s / %+v", i, r, body.Endpoints[i], body.Routes[i])
+		}
+	}
+}
+
+// TestRoutes_Admin tests that admin routes are recorded with their auth requirement.
+func TestRoutes_Admin(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := config.NewDefaultConfig(*logger).Server
+	cfg.Port = 0
+	cfg.AdminPort = 1 // Never bound; NewServer only builds the admin router
+	cfg.AdminToken = "secret"
+	srv := server.NewServer(cfg, logger)
+
+	auth := make(map[string]string)
+	for _, r := range srv.Routes().Routes(routes.ListenerAdmin) {
+		auth[r.String()] = r.Auth
+	}
+	tests := map[string]string{
+		"GET /health":       routes.AuthNone,
+		"GET /ready":        routes.AuthNone,
+		"GET /config":       routes.AuthAdminToken,
+		"PUT /log-level":    routes.AuthAdminToken,
+		"GET /debug/pprof/": routes.AuthAdminToken,
+	}
+	for route, want := range tests {
+		if got, ok := auth[route]; !ok {
+			t.Errorf("%s not recorded", route)
+		} else if got != want {
+			t.Errorf("%s: expected auth %q, got %q", route, want, got)
+		}
+	}
+
+	// Without AdminPort there is no admin router to record
+	if admin := newDefaultServer().Routes().Routes(routes.ListenerAdmin); len(admin) != 0 {
+		t.Errorf("Expected no admin routes, got %v", admin)
+	}
+}
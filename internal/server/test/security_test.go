@@ -0,0 +1,162 @@
+package server_test
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// newSecurityServer creates a server with the default security settings, modified by adjust.
+func newSecurityServer(logger *slog.Logger, adjust func(*config.ServerSecurity)) *server.Server {
+	defaults := config.NewDefaultConfig(*logger)
+	security := defaults.Server.Security
+	if adjust != nil {
+		adjust(&security)
+	}
+	srv := server.NewServer(config.Server{Port: 0, Host: "127.0.0.1", Security: security}, logger)
+	srv.Router().GET("/page", func(c *gin.Context) {
+		c.String(http.StatusOK, handlers.CSPNonce(c))
+	})
+	return srv
+}
+
+// serve sends req to the server's router and returns the recorded response.
+func serve(srv *server.Server, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+	return w
+}
+
+// TestSecurityHeaders_Defaults tests the default headers on successful and error responses.
+func TestSecurityHeaders_Defaults(t *testing.T) {
+	srv := newSecurityServer(slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	for _, path := range []string{"/health", "/missing"} {
+		w := serve(srv, httptest.NewRequest(http.MethodGet, path, nil))
+		want := map[string]string{
+			"X-Content-Type-Options": "nosniff",
+			"Referrer-Policy":        "no-referrer",
+			"X-Frame-Options":        "DENY",
+			"Reporting-Endpoints":    `csp-endpoint="/csp-report"`,
+		}
+		for header, value := range want {
+			if got := w.Header().Get(header); got != value {
+				t.Errorf("%s: expected %s %q, got %q", path, header, value, got)
+			}
+		}
+		csp := w.Header().Get("Content-Security-Policy")
+		if !strings.HasPrefix(csp, "default-src 'none'") || !strings.HasSuffix(csp, "report-uri /csp-report; report-to csp-endpoint") {
+			t.Errorf("%s: unexpected Content-Security-Policy %q", path, csp)
+		}
+		if w.Header().Get("Permissions-Policy") == "" {
+			t.Errorf("%s: expected a Permissions-Policy header", path)
+		}
+		if w.Header().Get("Strict-Transport-Security") != "" {
+			t.Errorf("%s: expected no HSTS over plain HTTP", path)
+		}
+	}
+}
+
+// TestSecurityHeaders_Nonce tests that each request gets a fresh nonce, shared by the header and handlers.
+func TestSecurityHeaders_Nonce(t *testing.T) {
+	srv := newSecurityServer(slog.New(slog.NewTextHandler(io.Discard, nil)), nil)
+
+	first := serve(srv, httptest.NewRequest(http.MethodGet, "/page", nil))
+	second := serve(srv, httptest.NewRequest(http.MethodGet, "/page", nil))
+	nonce := first.Body.String()
+	if nonce == "" || nonce == second.Body.String() {
+		t.Fatalf("Expected distinct per-request nonces, got %q and %q", nonce, second.Body.String())
+	}
+	csp := first.Header().Get("Content-Security-Policy")
+	if !strings.Contains(csp, "script-src 'self' 'nonce-"+nonce+"'") || strings.Contains(csp, "{nonce}") {
+		t.Errorf("Expected the handler's nonce in the policy, got %q", csp)
+	}
+}
+
+// TestSecurityHeaders_Options tests report-only mode, HSTS over TLS and disabling the middleware.
+func TestSecurityHeaders_Options(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	srv := newSecurityServer(logger, func(s *config.ServerSecurity) {
+		s.CSPReportOnly = true
+		s.HSTSPreload = true
+	})
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.TLS = &tls.ConnectionState{}
+	w := serve(srv, req)
+	if w.Header().Get("Content-Security-Policy") != "" || w.Header().Get("Content-Security-Policy-Report-Only") == "" {
+		t.Errorf("Expected only a report-only policy, got %v", w.Header())
+	}
+	if got := w.Header().Get("Strict-Transport-Security"); got != "max-age=31536000; includeSubDomains; preload" {
+		t.Errorf("Expected HSTS over TLS, got %q", got)
+	}
+
+	srv = newSecurityServer(logger, func(s *config.ServerSecurity) { s.Enabled = false })
+	w = serve(srv, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if w.Header().Get("X-Content-Type-Options") != "" || w.Header().Get("Content-Security-Policy") != "" {
+		t.Errorf("Expected no security headers when disabled, got %v", w.Header())
+	}
+	if w := serve(srv, httptest.NewRequest(http.MethodPost, "/csp-report", nil)); w.Code != http.StatusNotFound {
+		t.Errorf("Expected no report endpoint when disabled, got %d", w.Code)
+	}
+}
+
+// TestCSPReport tests that violation reports in both formats are logged and malformed bodies rejected.
+func TestCSPReport(t *testing.T) {
+	var logs bytes.Buffer
+	srv := newSecurityServer(slog.New(slog.NewTextHandler(&logs, nil)), nil)
+
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		wantLogged  string
+	}{
+		{
+			"Legacy report-uri",
+			"application/csp-report",
+			`{"csp-report":{"document-uri":"https://app.example.com/","violated-directive":"script-src-elem","blocked-uri":"https://evil.example.com/x.js","disposition":"enforce"}}`,
+			http.StatusNoContent,
+			"blocked=https://evil.example.com/x.js",
+		},
+		{
+			"Reporting API",
+			"application/reports+json",
+			`[{"type":"csp-violation","body":{"documentURL":"https://app.example.com/","effectiveDirective":"style-src-elem","blockedURL":"inline","disposition":"report"}},{"type":"deprecation","body":{}}]`,
+			http.StatusNoContent,
+			"directive=style-src-elem",
+		},
+		{"Not JSON", "application/csp-report", "nonsense", http.StatusBadRequest, ""},
+		{"JSON without report", "application/json", `{"hello":"world"}`, http.StatusBadRequest, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logs.Reset()
+			req := httptest.NewRequest(http.MethodPost, "/csp-report", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			w := serve(srv, req)
+			if w.Code != tc.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.wantStatus, w.Code, w.Body.String())
+			}
+			logged := strings.Count(logs.String(), "CSP violation reported")
+			if tc.wantLogged == "" && logged != 0 {
+				t.Errorf("Expected nothing logged, got %s", logs.String())
+			}
+			if tc.wantLogged != "" && (logged != 1 || !strings.Contains(logs.String(), tc.wantLogged)) {
+				t.Errorf("Expected one violation logged with %s, got %s", tc.wantLogged, logs.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package server_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// testCA issues certificates for TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+// newTestCA creates a self-signed certificate authority.
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key signed by the CA.
+func (ca *testCA) issue(t *testing.T, serial int64, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Failed to issue certificate: %v", err)
+	}
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeFile writes data to name in dir and returns the path.
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// startTLSServer starts a server on an ephemeral port and stops it at test end.
+func startTLSServer(t *testing.T, tlsCfg config.ServerTLS) *server.Server {
+	t.Helper()
+	cfg := config.Server{Port: 0, Host: "127.0.0.1", ReadTimeout: 30, WriteTimeout: 30, TLS: tlsCfg}
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	srv := server.NewServer(cfg, logger)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	})
+	return srv
+}
+
+// tlsClient returns an HTTP client with the given TLS settings and HTTP/2 enabled.
+func tlsClient(tlsConfig *tls.Config) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsConfig, ForceAttemptHTTP2: true},
+		Timeout:   5 * time.Second,
+	}
+}
+
+// TestServerTLS_SelfSignedServesHTTP2 tests the development certificate mode.
+func TestServerTLS_SelfSignedServesHTTP2(t *testing.T) {
+	srv := startTLSServer(t, config.ServerTLS{Enabled: true, SelfSigned: true})
+
+	resp, err := tlsClient(&tls.Config{InsecureSkipVerify: true}).Get("https://" + srv.Addr() + "/health")
+	if err != nil {
+		t.Fatalf("HTTPS request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.ProtoMajor != 2 {
+		t.Errorf("Expected HTTP/2 over TLS, got %s", resp.Proto)
+	}
+	if names := resp.TLS.PeerCertificates[0].DNSNames; len(names) == 0 || names[0] != "localhost" {
+		t.Errorf("Expected self-signed certificate for localhost, got %v", names)
+	}
+}
+
+// TestServerTLS_ReloadsRenewedCertificate tests that replaced certificate
+// files are served without restarting.
+func TestServerTLS_ReloadsRenewedCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certPEM, keyPEM := ca.issue(t, 100, x509.ExtKeyUsageServerAuth)
+	certFile := writeFile(t, dir, "tls.crt", certPEM)
+	keyFile := writeFile(t, dir, "tls.key", keyPEM)
+
+	// ReloadInterval 0 checks the files on every handshake
+	srv := startTLSServer(t, config.ServerTLS{Enabled: true, CertFile: certFile, KeyFile: keyFile, MinVersion: "1.3"})
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(ca.pem)
+	serial := func() int64 {
+		// A new client per request forces a fresh handshake
+		resp, err := tlsClient(&tls.Config{RootCAs: pool}).Get("https://" + srv.Addr() + "/health")
+		if err != nil {
+			t.Fatalf("HTTPS request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.TLS.Version != tls.VersionTLS13 {
+			t.Errorf("Expected TLS 1.3, got %x", resp.TLS.Version)
+		}
+		return resp.TLS.PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	if got := serial(); got != 100 {
+		t.Fatalf("Expected initial certificate serial 100, got %d", got)
+	}
+
+	certPEM, keyPEM = ca.issue(t, 200, x509.ExtKeyUsageServerAuth)
+	writeFile(t, dir, "tls.crt", certPEM)
+	writeFile(t, dir, "tls.key", keyPEM)
+	later := time.Now().Add(time.Minute) // Guarantee a visible modification time change
+	os.Chtimes(certFile, later, later)
+	os.Chtimes(keyFile, later, later)
+
+	if got := serial(); got != 200 {
+		t.Errorf("Expected renewed certificate serial 200, got %d", got)
+	}
+}
+
+// TestServerTLS_MutualTLS tests that client certificates are required when a client CA is configured.
+func TestServerTLS_MutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certPEM, keyPEM := ca.issue(t, 1, x509.ExtKeyUsageServerAuth)
+	clientCertPEM, clientKeyPEM := ca.issue(t, 2, x509.ExtKeyUsageClientAuth)
+
+	srv := startTLSServer(t, config.ServerTLS{
+		Enabled:      true,
+		CertFile:     writeFile(t, dir, "tls.crt", certPEM),
+		KeyFile:      writeFile(t, dir, "tls.key", keyPEM),
+		ClientCAFile: writeFile(t, dir, "ca.pem", ca.pem),
+		CipherPolicy: "intermediate",
+	})
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(ca.pem)
+	url := "https://" + srv.Addr() + "/health"
+
+	if resp, err := tlsClient(&tls.Config{RootCAs: pool}).Get(url); err == nil {
+		resp.Body.Close()
+		t.Error("Expected request without client certificate to be rejected")
+	}
+
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	if err != nil {
+		t.Fatalf("Failed to load client certificate: %v", err)
+	}
+	resp, err := tlsClient(&tls.Config{RootCAs: pool, Certificates: []tls.Certificate{clientCert}}).Get(url)
+	if err != nil {
+		t.Fatalf("Request with client certificate failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected 200 with client certificate, got %d", resp.StatusCode)
+	}
+}
+
+// TestServerTLS_RedirectsPlainHTTP tests the HTTP-to-HTTPS redirect listener.
+func TestServerTLS_RedirectsPlainHTTP(t *testing.T) {
+	srv := startTLSServer(t, config.ServerTLS{Enabled: true, SelfSigned: true, RedirectPort: 8096})
+	_, port, _ := net.SplitHostPort(srv.Addr())
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get("http://localhost:8096/health?probe=1")
+	if err != nil {
+		t.Fatalf("Plain HTTP request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPermanentRedirect {
+		t.Errorf("Expected 308 redirect, got %d", resp.StatusCode)
+	}
+	if want := "https://localhost:" + port + "/health?probe=1"; resp.Header.Get("Location") != want {
+		t.Errorf("Expected Location %s, got %s", want, resp.Header.Get("Location"))
+	}
+}
+
+// TestServerTLS_InvalidConfigFailsStart tests that TLS problems are reported by Start.
+func TestServerTLS_InvalidConfigFailsStart(t *testing.T) {
+	testCases := []struct {
+		name string
+		tls  config.ServerTLS
+	}{
+		{"Missing certificate files", config.ServerTLS{Enabled: true}},
+		{"Unreadable certificate", config.ServerTLS{Enabled: true, CertFile: "missing.crt", KeyFile: "missing.key"}},
+		{"Unsupported min version", config.ServerTLS{Enabled: true, SelfSigned: true, MinVersion: "1.0"}},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := server.NewServer(config.Server{Port: 0, Host: "127.0.0.1", TLS: tc.tls}, logger)
+			if err := srv.Start(); err == nil {
+				srv.Shutdown(context.Background())
+				t.Error("Expected Start to fail")
+			}
+		})
+	}
+}
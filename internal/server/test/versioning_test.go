@@ -0,0 +1,229 @@
+package server_test
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apiversion"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/openapi"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/server"
+)
+
+// addLegacyRoute registers GET /api/v1/legacy, deprecated in favour of /api/v2/.
+func addLegacyRoute(srv *server.Server) {
+	srv.HandleVersioned("v1", http.MethodGet, "/legacy", openapi.Operation{
+		Summary:   "Legacy test route",
+		Responses: map[int]openapi.Response{http.StatusOK: {Description: "Empty object"}},
+	}, &apiversion.Deprecation{
+		Since:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC),
+		Successor: "/api/v2/",
+	}, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+}
+
+// newNegotiatingServer creates a server that also serves unversioned /api paths.
+func newNegotiatingServer() *server.Server {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := config.NewDefaultConfig(*logger).Server
+	cfg.Port = 0
+	cfg.API.Negotiation = "header"
+	return server.NewServer(cfg, logger)
+}
+
+// getAPI sends a GET request with an optional Accept header.
+func getAPI(srv *server.Server, path, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	srv.Router().ServeHTTP(w, req)
+	return w
+}
+
+// TestVersioning_PathVersions tests the versioned route groups and their deprecation headers.
+func TestVersioning_PathVersions(t *testing.T) {
+	srv := newDefaultServer()
+	addLegacyRoute(srv)
+
+	w := getAPI(srv, "/api/v1/", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("v1: expected 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("API-Version"); got != "v1" {
+		t.Errorf("v1: expected API-Version v1, got %q", got)
+	}
+	if w.Header().Get("Deprecation") != "" {
+		t.Errorf("v1: unexpected Deprecation header %q", w.Header().Get("Deprecation"))
+	}
+
+	w = getAPI(srv, "/api/v1/legacy", "")
+	if w.Header().Get("Deprecation") == "" || w.Header().Get("Sunset") == "" {
+		t.Errorf("legacy: expected Deprecation and Sunset headers, got %v", w.Header())
+	}
+	if got := w.Header().Get("Link"); got != `</api/v2/>; rel="successor-version"` {
+		t.Errorf("legacy: unexpected Link %q", got)
+	}
+
+	w = getAPI(srv, "/api/v2/", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("v2: expected 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("API-Version"); got != "v2" {
+		t.Errorf("v2: expected API-Version v2, got %q", got)
+	}
+	if w.Header().Get("Deprecation") != "" {
+		t.Errorf("v2: unexpected Deprecation header %q", w.Header().Get("Deprecation"))
+	}
+	var info handlers.APIInfoResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+		t.Fatalf("v2: failed to decode response: %v", err)
+	}
+	if info.Version != "v2" || len(info.Routes) != 3 {
+		t.Fatalf("v2: expected the three versioned routes, got %+v", info)
+	}
+	for _, route := range info.Routes {
+		legacy := route.Path == "/api/v1/legacy"
+		if route.Deprecated != legacy || (legacy && (route.Sunset != "2027-04-30" || route.Version != "v1")) {
+			t.Errorf("v2: unexpected entry %+v", route)
+		}
+	}
+
+	// Unversioned paths are not served under path negotiation
+	if w := getAPI(srv, "/api/", apiversion.MediaType("v2")); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for /api/ under path negotiation, got %d", w.Code)
+	}
+}
+
+// TestVersioning_HeaderNegotiation tests choosing the version of unversioned paths by Accept header.
+func TestVersioning_HeaderNegotiation(t *testing.T) {
+	srv := newNegotiatingServer()
+	addLegacyRoute(srv)
+
+	tests := []struct {
+		name       string
+		path       string
+		accept     string
+		status     int
+		version    string
+		deprecated bool
+	}{
+		{"default version", "/api/", "", http.StatusOK, "v1", false},
+		{"plain JSON", "/api/", "application/json", http.StatusOK, "v1", false},
+		{"v2 by media type", "/api/", apiversion.MediaType("v2"), http.StatusOK, "v2", false},
+		{"unknown version", "/api/", apiversion.MediaType("v9"), http.StatusNotAcceptable, "", false},
+		{"deprecated route", "/api/legacy", "", http.StatusOK, "v1", true},
+		{"route missing from version", "/api/legacy", apiversion.MediaType("v2"), http.StatusNotAcceptable, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := getAPI(srv, tt.path, tt.accept)
+			if w.Code != tt.status {
+				t.Fatalf("Expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if got := w.Header().Get("API-Version"); got != tt.version {
+				t.Errorf("Expected API-Version %q, got %q", tt.version, got)
+			}
+			if got := w.Header().Get("Deprecation") != ""; got != tt.deprecated {
+				t.Errorf("Expected deprecated=%v, got headers %v", tt.deprecated, w.Header())
+			}
+			if !strings.Contains(strings.Join(w.Header().Values("Vary"), ","), "Accept") {
+				t.Errorf("Expected Vary: Accept, got %v", w.Header().Values("Vary"))
+			}
+		})
+	}
+
+	// Explicit versions in the path still work
+	if w := getAPI(srv, "/api/v2/", apiversion.MediaType("v1")); w.Header().Get("API-Version") != "v2" {
+		t.Errorf("Expected the path version to win, got %q", w.Header().Get("API-Version"))
+	}
+}
+
+// TestVersioning_DeprecatedCallMetric tests that calls to deprecated routes are counted in /metrics.
+func TestVersioning_DeprecatedCallMetric(t *testing.T) {
+	srv := newDefaultServer()
+	addLegacyRoute(srv)
+
+	calls := func() float64 {
+		w := getAPI(srv, "/metrics", "")
+		var body struct {
+			Application struct {
+				DeprecatedRoutes []struct {
+					Route  string  `json:"route"`
+					Calls  float64 `json:"calls_total"`
+					Sunset string  `json:"sunset"`
+				} `json:"deprecated_routes"`
+			} `json:"application"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Failed to decode metrics: %v", err)
+		}
+		for _, r := range body.Application.DeprecatedRoutes {
+			if r.Route == "GET /api/v1/legacy" {
+				if r.Sunset == "" {
+					t.Error("Expected the sunset of GET /api/v1/legacy")
+				}
+				return r.Calls
+			}
+		}
+		t.Fatalf("GET /api/v1/legacy missing from deprecated_routes: %s", w.Body.String())
+		return 0
+	}
+
+	before := calls()
+	getAPI(srv, "/api/v1/legacy", "")
+	getAPI(srv, "/api/v1/legacy", "")
+	getAPI(srv, "/api/v1/", "")
+	getAPI(srv, "/api/v2/", "")
+	if got := calls(); got != before+2 {
+		t.Errorf("Expected %v deprecated calls, got %v", before+2, got)
+	}
+}
+
+// TestVersioning_OpenAPI tests that deprecation is declared in the OpenAPI document.
+func TestVersioning_OpenAPI(t *testing.T) {
+	srv := newNegotiatingServer()
+	addLegacyRoute(srv)
+	data, err := json.Marshal(srv.OpenAPI())
+	if err != nil {
+		t.Fatalf("Failed to render document: %v", err)
+	}
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Deprecated bool `json:"deprecated"`
+			Responses  map[string]struct {
+				Headers map[string]any `json:"headers"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Failed to decode document: %v", err)
+	}
+
+	legacy := doc.Paths["/api/v1/legacy"]["get"]
+	if !legacy.Deprecated {
+		t.Error("Expected GET /api/v1/legacy to be deprecated")
+	}
+	for _, header := range []string{"Deprecation", "Sunset", "Link"} {
+		if _, ok := legacy.Responses["200"].Headers[header]; !ok {
+			t.Errorf("Expected the %s header documented on GET /api/v1/legacy", header)
+		}
+	}
+	if doc.Paths["/api/v1/"]["get"].Deprecated || doc.Paths["/api/v2/"]["get"].Deprecated {
+		t.Error("Expected GET /api/v1/ and /api/v2/ not to be deprecated")
+	}
+	if _, ok := doc.Paths["/api/"]["get"].Responses["406"]; !ok {
+		t.Error("Expected a 406 response on the negotiated GET /api/")
+	}
+}
@@ -0,0 +1,265 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/radek-zitek-cloud/goedu-theta/internal/config"
+)
+
+// selfSignedValidity is how long a generated development certificate is valid.
+const selfSignedValidity = 30 * 24 * time.Hour
+
+// intermediateCipherSuites is the Mozilla "intermediate" TLS 1.2 suite list:
+// ECDHE key exchange with AEAD ciphers only. TLS 1.3 suites are not
+// configurable in crypto/tls and are always enabled.
+var intermediateCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// newTLSConfig builds the server TLS configuration from cfg.
+//
+// Parameters:
+//   - cfg: TLS settings; cfg.Enabled is not checked here
+//   - host: Server host, added to the self-signed certificate's names
+//   - logger: Logger for certificate reload events
+//
+// Returns:
+//   - *tls.Config: Configuration for http.Server.ServeTLS; HTTP/2 is added by ServeTLS via ALPN
+//   - error: Invalid settings or unreadable certificate, key or CA files
+func newTLSConfig(cfg config.ServerTLS, host string, logger *slog.Logger) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+
+	switch cfg.MinVersion {
+	case "", "1.2":
+		tlsConfig.MinVersion = tls.VersionTLS12
+	case "1.3":
+		tlsConfig.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported TLS min_version %q (use \"1.2\" or \"1.3\")", cfg.MinVersion)
+	}
+
+	switch strings.ToLower(cfg.CipherPolicy) {
+	case "", "default":
+		// Go's defaults already exclude insecure suites
+	case "intermediate":
+		tlsConfig.CipherSuites = intermediateCipherSuites
+	case "modern":
+		tlsConfig.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported TLS cipher_policy %q (use \"default\", \"intermediate\" or \"modern\")", cfg.CipherPolicy)
+	}
+
+	if cfg.SelfSigned {
+		cert, err := selfSignedCertificate(host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate self-signed certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	} else {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, errors.New("TLS is enabled but cert_file and key_file are not set (or enable self_signed for development)")
+		}
+		reloader, err := newCertReloader(cfg.CertFile, cfg.KeyFile, time.Duration(cfg.ReloadInterval)*time.Second, logger)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.GetCertificate = reloader.GetCertificate
+	}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in client CA file %s", cfg.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+
+		switch strings.ToLower(cfg.ClientAuth) {
+		case "", "require":
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		case "optional":
+			tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		default:
+			return nil, fmt.Errorf("unsupported TLS client_auth %q (use \"require\" or \"optional\")", cfg.ClientAuth)
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// certReloader serves a certificate loaded from disk and re-reads it when the
+// certificate or key file changes, so renewed certificates are picked up
+// without a restart. A renewal that fails to load keeps the previous
+// certificate in use.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration // Minimum time between file checks
+	logger   *slog.Logger
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	certMod   time.Time // Modification time of certFile when cert was loaded
+	keyMod    time.Time // Modification time of keyFile when cert was loaded
+	lastCheck time.Time
+}
+
+// newCertReloader loads the initial certificate and returns a reloader for it.
+func newCertReloader(certFile, keyFile string, interval time.Duration, logger *slog.Logger) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, interval: interval, logger: logger}
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(certMod, keyMod); err != nil {
+		return nil, err
+	}
+	r.lastCheck = time.Now()
+	return r, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if now := time.Now(); now.Sub(r.lastCheck) >= r.interval {
+		r.lastCheck = now
+		certMod, keyMod, err := r.modTimes()
+		if err != nil {
+			r.logger.Warn("🔐 Failed to check TLS certificate files, keeping current certificate",
+				slog.String("error", err.Error()),
+			)
+		} else if !certMod.Equal(r.certMod) || !keyMod.Equal(r.keyMod) {
+			if err := r.load(certMod, keyMod); err != nil {
+				// Files may be mid-rotation (certificate written, key not yet); retry on a later handshake
+				r.logger.Warn("🔐 Failed to reload TLS certificate, keeping current certificate",
+					slog.String("error", err.Error()),
+				)
+			} else {
+				r.logger.Info("🔐 TLS certificate reloaded",
+					slog.String("cert_file", r.certFile),
+					slog.Time("not_after", r.cert.Leaf.NotAfter), // Expiry of the new certificate
+				)
+			}
+		}
+	}
+	return r.cert, nil
+}
+
+// load reads the key pair and records the file modification times it was read at.
+func (r *certReloader) load(certMod, keyMod time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return fmt.Errorf("failed to parse TLS certificate: %w", err)
+		}
+	}
+	r.cert = &cert
+	r.certMod = certMod
+	r.keyMod = keyMod
+	return nil
+}
+
+// modTimes returns the modification times of the certificate and key files.
+func (r *certReloader) modTimes() (time.Time, time.Time, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to stat TLS certificate file: %w", err)
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to stat TLS key file: %w", err)
+	}
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
+
+// selfSignedCertificate generates an ECDSA P-256 certificate valid for
+// localhost, the loopback addresses and host.
+func selfSignedCertificate(host string) (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"GoEdu-Theta development"}, CommonName: "localhost"},
+		NotBefore:             now.Add(-time.Hour), // Tolerate small clock skew
+		NotAfter:              now.Add(selfSignedValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	if host != "" && host != "localhost" {
+		if ip := net.ParseIP(host); ip != nil {
+			if !ip.IsUnspecified() {
+				template.IPAddresses = append(template.IPAddresses, ip)
+			}
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
+
+// httpsRedirectHandler permanently redirects every request to the same host
+// and path on the HTTPS listener at httpsPort.
+func httpsRedirectHandler(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]") // Bracketed IPv6 literal without a port
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]" // Bare IPv6 literal
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
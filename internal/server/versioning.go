@@ -0,0 +1,211 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apiversion"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/apperror"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/handlers"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/openapi"
+	"github.com/radek-zitek-cloud/goedu-theta/internal/routes"
+)
+
+// apiGroup registers the routes of one API version under /api/<version>.
+type apiGroup struct {
+	server  *Server
+	version string           // e.g. "v2"
+	group   *gin.RouterGroup // Routes under /api/<version>
+}
+
+// apiGroup returns the route group of an API version.
+func (s *Server) apiGroup(version string) *apiGroup {
+	return &apiGroup{server: s, version: version, group: s.router.Group("/api/" + version)}
+}
+
+// HandleVersioned registers a route of an API version under /api/<version>,
+// like the built-in versioned routes: with the API-Version header, the
+// deprecation headers and metrics when dep is set, the OpenAPI description
+// and an entry in the route registry. Register routes before Start.
+//
+// Example:
+//
+//	srv.HandleVersioned("v1", http.MethodGet, "/courses", op, &apiversion.Deprecation{
+//	    Since:     time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+//	    Successor: "/api/v2/courses",
+//	}, listCoursesV1)
+func (s *Server) HandleVersioned(version, method, path string, op openapi.Operation, dep *apiversion.Deprecation, handler gin.HandlerFunc) {
+	s.apiGroup(version).handle(method, path, op, dep, handler)
+}
+
+// handle registers a route of the group's version together with its OpenAPI
+// description, and records it in the route registry. Responses carry an
+// API-Version header.
+//
+// Parameters:
+//   - method: HTTP method, e.g. http.MethodGet
+//   - path: Route pattern relative to the group, e.g. "/courses/:id"
+//   - op: OpenAPI description of the route
+//   - dep: Deprecation of the route, sent as response headers; nil for current routes
+//   - handler: Gin handler serving the route
+func (g *apiGroup) handle(method, path string, op openapi.Operation, dep *apiversion.Deprecation, handler gin.HandlerFunc) {
+	s := g.server
+	route := routes.Route{
+		Listener:    routes.ListenerPublic,
+		Method:      method,
+		Path:        strings.TrimSuffix(g.group.BasePath(), "/") + path,
+		Description: op.Summary,
+		Auth:        routes.AuthNone,
+		Version:     g.version,
+	}
+
+	// The chain is also run by the unversioned route under header negotiation,
+	// so its middleware must not call c.Next
+	chain := gin.HandlersChain{apiVersionMiddleware(g.version)}
+	if dep != nil {
+		apiversion.TrackDeprecated(route.String(), *dep) // Reported with zero calls until first used
+		chain = append(chain, deprecationMiddleware(route.String(), *dep))
+		op = withDeprecation(op, *dep)
+		route.Deprecated = true
+		if !dep.Sunset.IsZero() {
+			route.Sunset = dep.Sunset.UTC().Format(time.DateOnly)
+		}
+	}
+	chain = append(chain, handler)
+
+	g.group.Handle(method, path, chain...)
+	s.api.Add(method, route.Path, withCommonResponses(op, s.config))
+	s.routes.Add(route)
+
+	if s.config.API.Negotiation == "header" {
+		s.handleNegotiated(method, path, g.version, op, chain)
+	}
+}
+
+// negotiatedRoute serves an unversioned /api path with the handler chain of
+// the API version the client asks for.
+type negotiatedRoute struct {
+	chains map[string]gin.HandlersChain // Handler chain by version
+}
+
+// versions returns the versions serving the route, sorted.
+func (n *negotiatedRoute) versions() []string {
+	versions := make([]string, 0, len(n.chains))
+	for v := range n.chains {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// handleNegotiated adds version's chain to the unversioned route /api<path>,
+// registering the route when the first version offers it. The OpenAPI
+// description is the one of the latest version registered.
+func (s *Server) handleNegotiated(method, path, version string, op openapi.Operation, chain gin.HandlersChain) {
+	unversioned := "/api" + path
+	key := method + " " + unversioned
+	route, ok := s.negotiated[key]
+	if !ok {
+		route = &negotiatedRoute{chains: make(map[string]gin.HandlersChain)}
+		s.negotiated[key] = route
+		s.router.Handle(method, unversioned, s.serveNegotiated(route))
+		s.routes.Add(routes.Route{
+			Listener:    routes.ListenerPublic,
+			Method:      method,
+			Path:        unversioned,
+			Description: op.Summary + " (version chosen by Accept header)",
+			Auth:        routes.AuthNone,
+		})
+	}
+	route.chains[version] = chain
+
+	op = withCommonResponses(op, s.config)
+	op.Description = strings.TrimSpace(op.Description + " Served by the API version named in the Accept header, e.g. " +
+		apiversion.MediaType(version) + ", or by " + s.config.API.DefaultVersion + " when it names none.")
+	op.Responses[http.StatusNotAcceptable] = openapi.ProblemResponse("Requested API version does not serve this route")
+	s.api.Add(method, unversioned, op)
+}
+
+// serveNegotiated runs the handler chain of the version named in the Accept
+// header, or of the default version.
+func (s *Server) serveNegotiated(route *negotiatedRoute) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept") // Caches must key responses on the version asked for
+
+		version, ok := apiversion.FromAccept(c.GetHeader("Accept"))
+		if !ok {
+			version = s.config.API.DefaultVersion
+		}
+		chain, ok := route.chains[version]
+		if !ok {
+			handlers.WriteError(c, apperror.NotAcceptable("API version "+version+" does not serve this route").
+				WithDetail("available_versions", route.versions()))
+			return
+		}
+		for _, h := range chain {
+			h(c)
+			if c.IsAborted() {
+				return
+			}
+		}
+	}
+}
+
+// apiVersionMiddleware names the API version serving the request in the
+// API-Version response header.
+func apiVersionMiddleware(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("API-Version", version)
+	}
+}
+
+// deprecationMiddleware announces the deprecation of route in the response
+// headers and counts the call for the deprecated route metrics.
+func deprecationMiddleware(route string, dep apiversion.Deprecation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dep.SetHeaders(c.Writer.Header())
+		apiversion.RecordDeprecatedCall(route)
+	}
+}
+
+// withDeprecation marks op deprecated and documents the deprecation headers
+// on its successful responses.
+func withDeprecation(op openapi.Operation, dep apiversion.Deprecation) openapi.Operation {
+	op.Deprecated = true
+
+	notice := "Deprecated since " + dep.Since.UTC().Format(time.DateOnly) + "."
+	if !dep.Sunset.IsZero() {
+		notice += " Removed on " + dep.Sunset.UTC().Format(time.DateOnly) + "."
+	}
+	if dep.Successor != "" {
+		notice += " Use " + dep.Successor + " instead."
+	}
+	op.Description = strings.TrimSpace(op.Description + " " + notice)
+
+	headers := map[string]string{"Deprecation": "Time the route was deprecated (RFC 9745)"}
+	if !dep.Sunset.IsZero() {
+		headers["Sunset"] = "Time the route will be removed (RFC 8594)"
+	}
+	if dep.Successor != "" {
+		headers["Link"] = "successor-version link to " + dep.Successor
+	}
+	responses := make(map[int]openapi.Response, len(op.Responses))
+	for status, resp := range op.Responses {
+		if status >= 200 && status < 300 {
+			merged := make(map[string]string, len(resp.Headers)+len(headers))
+			for name, desc := range resp.Headers {
+				merged[name] = desc
+			}
+			for name, desc := range headers {
+				merged[name] = desc
+			}
+			resp.Headers = merged
+		}
+		responses[status] = resp
+	}
+	op.Responses = responses
+	return op
+}